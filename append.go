@@ -0,0 +1,19 @@
+package xxhash
+
+// Sum64Append computes the XXH64 digest of prefix followed by suffix, as if
+// they were a single concatenated slice, without allocating.
+//
+// Note that there is no way to extend a finished digest: the 64-bit value
+// returned by Sum64 is the output of a lossy finalization step and does not
+// contain the internal state needed to hash more data. Computing the digest of
+// prefix||suffix therefore requires either prefix itself (as here) or the
+// saved state of a Digest that has consumed prefix. To resume hashing later
+// without keeping prefix around, save the state with Digest.MarshalBinary and
+// restore it with Digest.UnmarshalBinary.
+func Sum64Append(prefix, suffix []byte) uint64 {
+	var d Digest
+	d.Reset()
+	d.Write(prefix)
+	d.Write(suffix)
+	return d.Sum64()
+}
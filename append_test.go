@@ -0,0 +1,29 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSum64Append(t *testing.T) {
+	input := []byte("Call me Ishmael. Some years ago--never mind how long precisely-")
+	for i := 0; i <= len(input); i++ {
+		prefix, suffix := input[:i], input[i:]
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			if got, want := Sum64Append(prefix, suffix), Sum64(input); got != want {
+				t.Fatalf("got 0x%x; want 0x%x", got, want)
+			}
+		})
+	}
+	if got, want := Sum64Append(nil, nil), Sum64(nil); got != want {
+		t.Fatalf("empty: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestSum64AppendAllocs(t *testing.T) {
+	prefix := []byte("abcdefghijklmnopqrstuvwxyz")
+	suffix := []byte("0123456789")
+	testAllocs(t, func() {
+		sink = Sum64Append(prefix, suffix)
+	})
+}
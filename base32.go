@@ -0,0 +1,68 @@
+package xxhash
+
+import "errors"
+
+// base32Alphabet is Crockford's base32 alphabet, in lower case.
+const base32Alphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// FormatBase32 returns a 13-character base32 encoding of the digest h,
+// suitable for URLs and file names.
+//
+// The encoding uses Crockford's alphabet (0-9 and a-z excluding i, l, o, and
+// u) in lower case, without padding. The digits are big-endian, so the first
+// character holds the top 4 bits of h and the encoded strings sort in the
+// same order as the digests. The format is stable across releases.
+func FormatBase32(h uint64) string {
+	var b [13]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = base32Alphabet[h&31]
+		h >>= 5
+	}
+	return string(b[:])
+}
+
+// ParseBase32 parses a digest in the format produced by FormatBase32.
+//
+// Parsing is case-insensitive and, following Crockford, treats i and l as 1
+// and o as 0. Any other character outside the alphabet, a length other than
+// 13, or a value that does not fit in 64 bits is an error.
+func ParseBase32(s string) (uint64, error) {
+	if len(s) != 13 {
+		return 0, errors.New("xxhash: base32 digest must be 13 characters")
+	}
+	var h uint64
+	for i := 0; i < len(s); i++ {
+		v := base32Value(s[i])
+		if v < 0 {
+			return 0, errors.New("xxhash: invalid character in base32 digest")
+		}
+		if i == 0 && v > 15 {
+			return 0, errors.New("xxhash: base32 digest overflows 64 bits")
+		}
+		h = h<<5 | uint64(v)
+	}
+	return h, nil
+}
+
+func base32Value(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'A' <= c && c <= 'Z':
+		c += 'a' - 'A'
+	}
+	switch c {
+	case 'o':
+		return 0
+	case 'i', 'l':
+		return 1
+	case 'u':
+		return -1
+	}
+	for i := 10; i < len(base32Alphabet); i++ {
+		if base32Alphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,80 @@
+package xxhash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBase32(t *testing.T) {
+	for _, tt := range []struct {
+		h    uint64
+		want string
+	}{
+		{0, "0000000000000"},
+		{1, "0000000000001"},
+		{31, "000000000000z"},
+		{32, "0000000000010"},
+		{^uint64(0), "fzzzzzzzzzzzz"},
+		{0xef46db3751d8e999, "eyhpv6x8xhtcs"},
+	} {
+		got := FormatBase32(tt.h)
+		if got != tt.want {
+			t.Errorf("FormatBase32(0x%x): got %q; want %q", tt.h, got, tt.want)
+		}
+		h, err := ParseBase32(got)
+		if err != nil {
+			t.Errorf("ParseBase32(%q): %s", got, err)
+			continue
+		}
+		if h != tt.h {
+			t.Errorf("ParseBase32(%q): got 0x%x; want 0x%x", got, h, tt.h)
+		}
+		if h, err := ParseBase32(strings.ToUpper(got)); err != nil || h != tt.h {
+			t.Errorf("ParseBase32(%q): got (0x%x, %v); want 0x%x", strings.ToUpper(got), h, err, tt.h)
+		}
+	}
+}
+
+func TestBase32RoundTrip(t *testing.T) {
+	for i := uint64(0); i < 1000; i++ {
+		h := Sum64String(strings.Repeat("x", int(i)))
+		s := FormatBase32(h)
+		got, err := ParseBase32(s)
+		if err != nil || got != h {
+			t.Fatalf("round trip of 0x%x via %q: got (0x%x, %v)", h, s, got, err)
+		}
+	}
+}
+
+func TestBase32Order(t *testing.T) {
+	hs := []uint64{0, 1, 1 << 10, 1 << 40, 1<<63 - 1, 1 << 63, ^uint64(0)}
+	for i := 1; i < len(hs); i++ {
+		if a, b := FormatBase32(hs[i-1]), FormatBase32(hs[i]); a >= b {
+			t.Errorf("FormatBase32 order: %q >= %q", a, b)
+		}
+	}
+}
+
+func TestParseBase32Aliases(t *testing.T) {
+	got, err := ParseBase32("OOOOOOOOOOOil")
+	if err != nil || got != 0x21 {
+		t.Fatalf("got (0x%x, %v); want 0x21", got, err)
+	}
+}
+
+func TestParseBase32Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"000000000000",
+		"00000000000000",
+		"000000000000u",
+		"000000000000-",
+		"000000000000=",
+		"g000000000000",
+		"zzzzzzzzzzzzz",
+	} {
+		if h, err := ParseBase32(s); err == nil {
+			t.Errorf("ParseBase32(%q): got 0x%x, nil; want error", s, h)
+		}
+	}
+}
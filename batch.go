@@ -0,0 +1,86 @@
+package xxhash
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Sum64Records computes the XXH64 digests of the consecutive recordSize-byte
+// records in buf, storing the digest of the ith record in out[i].
+//
+// It returns an error (and writes nothing to out) if recordSize is not
+// positive, if len(buf) is not a multiple of recordSize, or if out is too
+// short to hold len(buf)/recordSize digests.
+func Sum64Records(buf []byte, recordSize int, out []uint64) error {
+	if recordSize <= 0 {
+		return errors.New("xxhash: record size must be positive")
+	}
+	if len(buf)%recordSize != 0 {
+		return errors.New("xxhash: buffer length is not a multiple of the record size")
+	}
+	n := len(buf) / recordSize
+	if len(out) < n {
+		return errors.New("xxhash: output slice too short for all records")
+	}
+	out = out[:n]
+	for i := range out {
+		out[i] = Sum64(buf[:recordSize:recordSize])
+		buf = buf[recordSize:]
+	}
+	return nil
+}
+
+// Sum64Batch computes the XXH64 digests of srcs, storing the digest of
+// srcs[i] in dst[i]. It panics if dst is shorter than srcs.
+func Sum64Batch(dst []uint64, srcs [][]byte) {
+	if len(dst) < len(srcs) {
+		panic("xxhash: Sum64Batch output slice too short")
+	}
+	dst = dst[:len(srcs)]
+	for i, b := range srcs {
+		dst[i] = Sum64(b)
+	}
+}
+
+// Sum64Strings computes the XXH64 digests of keys, storing the digest of
+// keys[i] in dst[i]. It panics if dst is shorter than keys.
+func Sum64Strings(dst []uint64, keys []string) {
+	if len(dst) < len(keys) {
+		panic("xxhash: Sum64Strings output slice too short")
+	}
+	dst = dst[:len(keys)]
+	for i, k := range keys {
+		dst[i] = Sum64String(k)
+	}
+}
+
+// BucketFor maps hash to a bucket in [0, n) as the high 64 bits of hash*n
+// (Lemire's "fastrange"), which is much cheaper than hash%n. Each bucket
+// receives either floor(2^64/n) or ceil(2^64/n) of the possible hash values,
+// the same evenness as hash%n, so for uniformly distributed hashes such as
+// XXH64 digests the bias is at most n/2^64. Unlike masking with n-1, it works
+// for any n and uses all the bits of the hash, weighted toward the high
+// ones. BucketFor panics if n is 0.
+func BucketFor(hash, n uint64) uint64 {
+	if n == 0 {
+		panic("xxhash: BucketFor bucket count must be positive")
+	}
+	hi, _ := bits.Mul64(hash, n)
+	return hi
+}
+
+// BucketStrings stores in dst[i] BucketFor(Sum64String(keys[i]), n), the
+// index of the bucket of keys[i] in a table of n buckets. It panics if n is
+// not positive or if dst is shorter than keys.
+func BucketStrings(dst []int, keys []string, n int) {
+	if n <= 0 {
+		panic("xxhash: BucketStrings bucket count must be positive")
+	}
+	if len(dst) < len(keys) {
+		panic("xxhash: BucketStrings output slice too short")
+	}
+	dst = dst[:len(keys)]
+	for i, k := range keys {
+		dst[i] = int(BucketFor(Sum64String(k), uint64(n)))
+	}
+}
@@ -0,0 +1,186 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSum64Records(t *testing.T) {
+	buf := make([]byte, 40*37)
+	for i := range buf {
+		buf[i] = byte(i * 13)
+	}
+	for _, size := range []int{1, 5, 37, 40, 74, len(buf)} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			n := len(buf) / size
+			out := make([]uint64, n+1)
+			out[n] = 12345
+			if err := Sum64Records(buf, size, out); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < n; i++ {
+				if want := Sum64(buf[i*size : (i+1)*size]); out[i] != want {
+					t.Fatalf("record %d: got 0x%x; want 0x%x", i, out[i], want)
+				}
+			}
+			if out[n] != 12345 {
+				t.Fatalf("wrote past the last record: out[%d] = 0x%x", n, out[n])
+			}
+		})
+	}
+	if err := Sum64Records(nil, 8, nil); err != nil {
+		t.Errorf("empty buffer: got error %v", err)
+	}
+}
+
+func TestSum64RecordsErrors(t *testing.T) {
+	buf := make([]byte, 32)
+	for _, tt := range []struct {
+		name       string
+		recordSize int
+		outLen     int
+	}{
+		{"zero size", 0, 10},
+		{"negative size", -4, 10},
+		{"uneven", 5, 10},
+		{"short out", 8, 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out := make([]uint64, tt.outLen)
+			if err := Sum64Records(buf, tt.recordSize, out); err == nil {
+				t.Fatal("got nil error")
+			}
+			for i, h := range out {
+				if h != 0 {
+					t.Fatalf("out[%d] was written on error", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSum64Batch(t *testing.T) {
+	buf := make([]byte, 1000)
+	for i := range buf {
+		buf[i] = byte(i * 13)
+	}
+	var srcs [][]byte
+	for off, n := 0, 0; off+n <= len(buf); off, n = off+n, n+1 {
+		srcs = append(srcs, buf[off:off+n])
+	}
+	dst := make([]uint64, len(srcs)+1)
+	dst[len(srcs)] = 12345
+	Sum64Batch(dst, srcs)
+	for i, b := range srcs {
+		if want := Sum64(b); dst[i] != want {
+			t.Fatalf("input %d: got 0x%x; want 0x%x", i, dst[i], want)
+		}
+	}
+	if dst[len(srcs)] != 12345 {
+		t.Fatal("wrote past the last input")
+	}
+}
+
+func TestSum64BatchShortDst(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	Sum64Batch(make([]uint64, 1), make([][]byte, 2))
+}
+
+func TestSum64Strings(t *testing.T) {
+	keys := []string{"", "a", "hello", "a somewhat longer key, past 32 bytes"}
+	dst := make([]uint64, len(keys))
+	Sum64Strings(dst, keys)
+	for i, k := range keys {
+		if want := Sum64String(k); dst[i] != want {
+			t.Fatalf("key %q: got 0x%x; want 0x%x", k, dst[i], want)
+		}
+	}
+}
+
+func TestBucketStrings(t *testing.T) {
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = fmt.Sprint("key", i)
+	}
+	for _, n := range []int{1, 7, 64} {
+		dst := make([]int, len(keys))
+		BucketStrings(dst, keys, n)
+		counts := make([]int, n)
+		for i, b := range dst {
+			if b < 0 || b >= n {
+				t.Fatalf("n=%d: key %d in bucket %d", n, i, b)
+			}
+			counts[b]++
+		}
+		// Every bucket should get roughly len(keys)/n keys.
+		for b, c := range counts {
+			if want := len(keys) / n; c < want/2 || c > want*2 {
+				t.Fatalf("n=%d: bucket %d has %d keys; want about %d", n, b, c, want)
+			}
+		}
+	}
+}
+
+func TestBucketStringsPanics(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		dstLen int
+		n      int
+	}{
+		{"zero buckets", 1, 0},
+		{"short dst", 0, 10},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic")
+				}
+			}()
+			BucketStrings(make([]int, tt.dstLen), []string{"a"}, tt.n)
+		})
+	}
+}
+
+func TestBucketFor(t *testing.T) {
+	for _, tt := range []struct {
+		hash, n, want uint64
+	}{
+		{0, 1, 0},
+		{1<<64 - 1, 1, 0},
+		{0, 10, 0},
+		{1<<64 - 1, 10, 9},
+		{1 << 63, 10, 5},
+		{1 << 63, 1<<64 - 1, 1<<63 - 1},
+	} {
+		if got := BucketFor(tt.hash, tt.n); got != tt.want {
+			t.Errorf("BucketFor(%#x, %d) = %d; want %d", tt.hash, tt.n, got, tt.want)
+		}
+	}
+
+	// Consecutive runs of hash values map to each bucket in turn, with
+	// sizes differing by at most one.
+	const n = 7
+	var counts [n]int
+	step := uint64(1<<64-1) / 7000
+	for h, i := uint64(0), 0; i < 7000; h, i = h+step, i+1 {
+		counts[BucketFor(h, n)]++
+	}
+	for b, c := range counts {
+		if c < 999 || c > 1001 {
+			t.Errorf("bucket %d has %d of 7000 evenly spaced hashes", b, c)
+		}
+	}
+}
+
+func TestBucketForZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	BucketFor(1, 0)
+}
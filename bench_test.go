@@ -1,6 +1,11 @@
 package xxhash
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 )
@@ -72,3 +77,169 @@ func BenchmarkDigestString(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkReadSum64(b *testing.B) {
+	// A mixed workload of small, medium, and large files, read with real
+	// syscalls.
+	sizes := []int{100, 64 << 10, 8 << 20}
+	var files []*io.SectionReader
+	for _, size := range sizes {
+		f, err := ioutil.TempFile("", "xxhash-bench")
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+		if _, err := f.Write(make([]byte, size)); err != nil {
+			b.Fatal(err)
+		}
+		files = append(files, io.NewSectionReader(f, 0, int64(size)))
+	}
+	for _, bb := range []struct {
+		name             string
+		minSize, maxSize int
+	}{
+		{"adaptive", minReadBufSize, maxReadBufSize},
+		{"fixed4KB", 4 << 10, 4 << 10},
+		{"fixed32KB", 32 << 10, 32 << 10},
+		{"fixed1MB", 1 << 20, 1 << 20},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			var total int64
+			for _, size := range sizes {
+				total += int64(size)
+			}
+			b.SetBytes(total)
+			for i := 0; i < b.N; i++ {
+				for _, f := range files {
+					f.Seek(0, io.SeekStart)
+					d := New()
+					readAdaptive(f, bb.minSize, bb.maxSize, func(b []byte) error {
+						d.Write(b)
+						return nil
+					})
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSum32(b *testing.B) {
+	for _, bb := range benchmarks {
+		in := make([]byte, bb.n)
+		for i := range in {
+			in[i] = byte(i)
+		}
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(bb.n)
+			for i := 0; i < b.N; i++ {
+				_ = Sum32(in)
+			}
+		})
+	}
+}
+
+func BenchmarkSumXXH3_64(b *testing.B) {
+	for _, bb := range benchmarks {
+		in := make([]byte, bb.n)
+		for i := range in {
+			in[i] = byte(i)
+		}
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(bb.n)
+			for i := 0; i < b.N; i++ {
+				_ = SumXXH3_64(in)
+			}
+		})
+	}
+}
+
+func BenchmarkSumXXH3_128(b *testing.B) {
+	for _, bb := range benchmarks {
+		in := make([]byte, bb.n)
+		for i := range in {
+			in[i] = byte(i)
+		}
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(bb.n)
+			for i := 0; i < b.N; i++ {
+				_ = SumXXH3_128(in)
+			}
+		})
+	}
+}
+
+func BenchmarkDigestXXH3Bytes(b *testing.B) {
+	for _, bb := range benchmarks {
+		in := make([]byte, bb.n)
+		for i := range in {
+			in[i] = byte(i)
+		}
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(bb.n)
+			h := NewXXH3()
+			for i := 0; i < b.N; i++ {
+				h.Reset()
+				h.Write(in)
+				_ = h.Sum64()
+			}
+		})
+	}
+}
+
+func BenchmarkSumTree64(b *testing.B) {
+	in := make([]byte, 64<<20)
+	for i := range in {
+		in[i] = byte(i)
+	}
+	r := strings.NewReader(string(in))
+	b.SetBytes(int64(len(in)))
+	for i := 0; i < b.N; i++ {
+		if _, err := SumTree64(r, int64(len(in)), 1<<20); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSum64Batch(b *testing.B) {
+	for _, size := range []int{8, 16, 64} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			const n = 1024
+			buf := make([]byte, n*size)
+			srcs := make([][]byte, n)
+			for i := range srcs {
+				srcs[i] = buf[i*size : (i+1)*size]
+			}
+			dst := make([]uint64, n)
+			b.SetBytes(int64(len(buf)))
+			for i := 0; i < b.N; i++ {
+				Sum64Batch(dst, srcs)
+			}
+		})
+	}
+}
+
+func BenchmarkChunker(b *testing.B) {
+	in := chunkTestData(10 << 20)
+	b.SetBytes(int64(len(in)))
+	for i := 0; i < b.N; i++ {
+		c := NewChunker(bytes.NewReader(in), 2<<10, 8<<10, 64<<10)
+		for {
+			if _, err := c.Next(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkSum64Parallel(b *testing.B) {
+	in := make([]byte, 256<<20)
+	for _, workers := range []int{1, 4, 0} {
+		b.Run(fmt.Sprint("workers=", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(in)))
+			for i := 0; i < b.N; i++ {
+				Sum64Parallel(in, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package xxhash
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A BlockIndex records the XXH64 digest of each fixed-size block of some data,
+// along with the digest of the data as a whole. It can be stored alongside the
+// data (see MarshalBinary) and used later to verify any byte range of it
+// without reading the rest, for example after an HTTP range request or a
+// partial restore.
+type BlockIndex struct {
+	BlockSize int64    // size of each block but the last, which may be shorter
+	Size      int64    // total size of the data
+	Sum       uint64   // XXH64 digest of the whole data
+	Blocks    []uint64 // XXH64 digest of each block, in order
+}
+
+// NewBlockIndex reads r until EOF and returns the BlockIndex of its contents.
+// It returns an error if blockSize is not positive or if reading fails.
+func NewBlockIndex(r io.Reader, blockSize int64) (*BlockIndex, error) {
+	if blockSize <= 0 {
+		return nil, errors.New("xxhash: block size must be positive")
+	}
+	x := &BlockIndex{BlockSize: blockSize}
+	d := New()
+	buf := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			x.Blocks = append(x.Blocks, Sum64(buf[:n]))
+			d.Write(buf[:n])
+			x.Size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	x.Sum = d.Sum64()
+	return x, nil
+}
+
+// A BlockMismatchError reports that a block did not match its digest in a
+// BlockIndex.
+type BlockMismatchError struct {
+	Block int    // index of the block
+	Got   uint64 // the digest of the block that was actually read
+	Want  uint64 // the digest recorded in the index
+}
+
+func (e *BlockMismatchError) Error() string {
+	return fmt.Sprintf("xxhash: checksum mismatch in block %d: got %016x, want %016x", e.Block, e.Got, e.Want)
+}
+
+// VerifyRange checks the n bytes of r starting at off against x. Since digests
+// are per block, it reads and checks every block that overlaps the range, so
+// r must provide the data of those whole blocks (at the same offsets as the
+// original data). It returns a *BlockMismatchError for the first block that
+// does not match, or an error if the range is out of bounds or reading fails.
+func (x *BlockIndex) VerifyRange(r io.ReaderAt, off, n int64) error {
+	if off < 0 || n < 0 || off+n > x.Size {
+		return errors.New("xxhash: range out of bounds")
+	}
+	if n == 0 {
+		return nil
+	}
+	first := off / x.BlockSize
+	last := (off + n - 1) / x.BlockSize
+	buf := make([]byte, x.BlockSize)
+	for i := first; i <= last; i++ {
+		start := i * x.BlockSize
+		b := buf
+		if x.Size-start < x.BlockSize {
+			b = buf[:x.Size-start]
+		}
+		if k, err := r.ReadAt(b, start); k < len(b) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if got, want := Sum64(b), x.Blocks[i]; got != want {
+			return &BlockMismatchError{Block: int(i), Got: got, Want: want}
+		}
+	}
+	return nil
+}
+
+// TreeSum returns the combined hash of x's blocks, which is the same as
+// SumTree64 of the data with a chunk size of BlockSize. Unlike Sum, it can be
+// kept up to date by Update without rereading unchanged blocks.
+func (x *BlockIndex) TreeSum() uint64 {
+	return treeRoot(x.BlockSize, x.Size, x.Blocks)
+}
+
+// A ByteRange is the range of Len bytes starting at offset Off.
+type ByteRange struct {
+	Off, Len int64
+}
+
+// Update brings x up to date after the data changed in the dirty ranges and
+// its size became size, and returns the new TreeSum. It rereads from r only
+// the blocks that overlap a dirty range and, if the size changed, the blocks
+// from the old or new end of the data (whichever is smaller) to the new end,
+// so r must provide the whole of those blocks. Dirty ranges may overlap and
+// be in any order.
+//
+// Since Sum is the digest of the whole data, Update cannot recompute it
+// without reading everything; it sets Sum to 0, and TreeSum takes its place
+// as the hash of the data. Update returns an error if a range is out of the
+// bounds of the new data or if reading fails, in which case x may be left
+// partly updated.
+func (x *BlockIndex) Update(r io.ReaderAt, size int64, dirty []ByteRange) (uint64, error) {
+	if size < 0 {
+		return 0, errors.New("xxhash: negative block index size")
+	}
+	type span struct{ first, last int64 } // inclusive block numbers
+	spans := make([]span, 0, len(dirty)+1)
+	for _, d := range dirty {
+		if d.Off < 0 || d.Len < 0 || d.Off+d.Len > size {
+			return 0, errors.New("xxhash: range out of bounds")
+		}
+		if d.Len > 0 {
+			spans = append(spans, span{d.Off / x.BlockSize, (d.Off + d.Len - 1) / x.BlockSize})
+		}
+	}
+	nblocks := (size + x.BlockSize - 1) / x.BlockSize
+	if size != x.Size {
+		end := x.Size
+		if size < end {
+			end = size
+		}
+		if first := end / x.BlockSize; first < nblocks {
+			spans = append(spans, span{first, nblocks - 1})
+		}
+		if int64(len(x.Blocks)) > nblocks {
+			x.Blocks = x.Blocks[:nblocks]
+		}
+		for int64(len(x.Blocks)) < nblocks {
+			x.Blocks = append(x.Blocks, 0)
+		}
+		x.Size = size
+	}
+	x.Sum = 0
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].first < spans[j].first })
+	buf := make([]byte, x.BlockSize)
+	next := int64(0) // the first block not yet rehashed by an earlier span
+	for _, sp := range spans {
+		if sp.first < next {
+			sp.first = next
+		}
+		for i := sp.first; i <= sp.last; i++ {
+			start := i * x.BlockSize
+			b := buf
+			if size-start < x.BlockSize {
+				b = buf[:size-start]
+			}
+			if k, err := r.ReadAt(b, start); k < len(b) {
+				if err == nil || err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return 0, err
+			}
+			x.Blocks[i] = Sum64(b)
+		}
+		if sp.last >= next {
+			next = sp.last + 1
+		}
+	}
+	return x.TreeSum(), nil
+}
+
+const (
+	blockIndexMagic      = "xxhi\x01"
+	blockIndexHeaderSize = len(blockIndexMagic) + 8*3
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The format is the 5-byte identifier "xxhi\x01" (whose last byte acts as a
+// version number), followed by BlockSize, Size, Sum, and each of the block
+// digests as little-endian uint64s.
+func (x *BlockIndex) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, blockIndexHeaderSize+8*len(x.Blocks))
+	b = append(b, blockIndexMagic...)
+	b = appendUint64(b, uint64(x.BlockSize))
+	b = appendUint64(b, uint64(x.Size))
+	b = appendUint64(b, x.Sum)
+	for _, h := range x.Blocks {
+		b = appendUint64(b, h)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (x *BlockIndex) UnmarshalBinary(b []byte) error {
+	if len(b) < len(blockIndexMagic) || string(b[:len(blockIndexMagic)]) != blockIndexMagic {
+		return errors.New("xxhash: invalid block index identifier")
+	}
+	if len(b) < blockIndexHeaderSize {
+		return errors.New("xxhash: invalid block index size")
+	}
+	b = b[len(blockIndexMagic):]
+	var blockSize, size uint64
+	b, blockSize = consumeUint64(b)
+	b, size = consumeUint64(b)
+	if blockSize == 0 || blockSize > 1<<62 || size > 1<<62 {
+		return errors.New("xxhash: invalid block index header")
+	}
+	nblocks := (size + blockSize - 1) / blockSize
+	if rest := uint64(len(b) - 8); rest%8 != 0 || rest/8 != nblocks {
+		return errors.New("xxhash: invalid block index size")
+	}
+	x.BlockSize, x.Size = int64(blockSize), int64(size)
+	b, x.Sum = consumeUint64(b)
+	x.Blocks = make([]uint64, nblocks)
+	for i := range x.Blocks {
+		b, x.Blocks[i] = consumeUint64(b)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBlockIndex(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, n := range []int{0, 1, 999, 1000, 1001, 10000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			x, err := NewBlockIndex(iotest.HalfReader(bytes.NewReader(data[:n])), 1000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x.Size != int64(n) || x.Sum != Sum64(data[:n]) {
+				t.Fatalf("got Size=%d, Sum=0x%x; want %d, 0x%x", x.Size, x.Sum, n, Sum64(data[:n]))
+			}
+			if want := (n + 999) / 1000; len(x.Blocks) != want {
+				t.Fatalf("got %d blocks; want %d", len(x.Blocks), want)
+			}
+			for i, h := range x.Blocks {
+				end := (i + 1) * 1000
+				if end > n {
+					end = n
+				}
+				if want := Sum64(data[i*1000 : end]); h != want {
+					t.Fatalf("block %d: got 0x%x; want 0x%x", i, h, want)
+				}
+			}
+
+			b, err := x.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var y BlockIndex
+			if err := y.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if y.BlockSize != x.BlockSize || y.Size != x.Size || y.Sum != x.Sum || len(y.Blocks) != len(x.Blocks) {
+				t.Fatalf("round trip: got %+v; want %+v", y, *x)
+			}
+			for i := range x.Blocks {
+				if y.Blocks[i] != x.Blocks[i] {
+					t.Fatalf("round trip: block %d differs", i)
+				}
+			}
+		})
+	}
+}
+
+func TestBlockIndexVerifyRange(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	x, err := NewBlockIndex(bytes.NewReader(data), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range [][2]int64{{0, 0}, {0, 10000}, {999, 2}, {5500, 10}, {9999, 1}} {
+		if err := x.VerifyRange(bytes.NewReader(data), r[0], r[1]); err != nil {
+			t.Errorf("VerifyRange(%d, %d): %v", r[0], r[1], err)
+		}
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[4321] ^= 1
+	// Ranges that don't touch block 4 still verify.
+	if err := x.VerifyRange(bytes.NewReader(corrupt), 0, 4000); err != nil {
+		t.Errorf("range before the corrupt block: %v", err)
+	}
+	err = x.VerifyRange(bytes.NewReader(corrupt), 3990, 20)
+	if e, ok := err.(*BlockMismatchError); !ok || e.Block != 4 || e.Want != x.Blocks[4] {
+		t.Errorf("range over the corrupt block: got %v; want a mismatch in block 4", err)
+	}
+
+	for _, r := range [][2]int64{{-1, 1}, {0, -1}, {9990, 11}} {
+		if err := x.VerifyRange(bytes.NewReader(data), r[0], r[1]); err == nil {
+			t.Errorf("VerifyRange(%d, %d): got nil error", r[0], r[1])
+		}
+	}
+	if err := x.VerifyRange(bytes.NewReader(data[:5000]), 5000, 10); err != io.ErrUnexpectedEOF {
+		t.Errorf("short data: got %v; want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestBlockIndexErrors(t *testing.T) {
+	if _, err := NewBlockIndex(bytes.NewReader(nil), 0); err == nil {
+		t.Error("zero block size: got nil error")
+	}
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 10)), errReader{errRead})
+	if _, err := NewBlockIndex(r, 4); err != errRead {
+		t.Errorf("read error: got %v; want %v", err, errRead)
+	}
+
+	x, err := NewBlockIndex(bytes.NewReader(make([]byte, 10)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := x.MarshalBinary()
+	var y BlockIndex
+	for _, bad := range [][]byte{
+		nil,
+		[]byte("xxhi\x02"),
+		b[:len(b)-1],
+		append(b[:len(b):len(b)], 0, 0, 0, 0, 0, 0, 0, 0),
+	} {
+		if err := y.UnmarshalBinary(bad); err == nil {
+			t.Errorf("UnmarshalBinary(%x): got nil error", bad)
+		}
+	}
+}
+
+func TestBlockIndexFormat(t *testing.T) {
+	x, err := NewBlockIndex(bytes.NewReader([]byte("hello, index")), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := x.MarshalBinary()
+	want := "7878686901" +
+		"0800000000000000" + // BlockSize
+		"0c00000000000000" + // Size
+		hex.EncodeToString(appendUint64(nil, Sum64([]byte("hello, index")))) +
+		hex.EncodeToString(appendUint64(nil, Sum64([]byte("hello, i")))) +
+		hex.EncodeToString(appendUint64(nil, Sum64([]byte("ndex"))))
+	if got := hex.EncodeToString(b); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+// countingReaderAt records the offsets of the reads made through it.
+type countingReaderAt struct {
+	r    io.ReaderAt
+	offs []int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.offs = append(c.offs, off)
+	return c.r.ReadAt(p, off)
+}
+
+func TestBlockIndexUpdate(t *testing.T) {
+	const blockSize = 100
+	data := make([]byte, 1050)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, tt := range []struct {
+		name  string
+		size  int
+		dirty []ByteRange
+		reads []int64 // offsets of the blocks that must be reread
+	}{
+		{"none", 1050, nil, nil},
+		{"one byte", 1050, []ByteRange{{250, 1}}, []int64{200}},
+		{"across blocks", 1050, []ByteRange{{190, 20}}, []int64{100, 200}},
+		{"overlapping", 1050, []ByteRange{{500, 150}, {320, 10}, {550, 200}, {0, 0}}, []int64{300, 500, 600, 700}},
+		{"last block", 1050, []ByteRange{{1049, 1}}, []int64{1000}},
+		{"grow", 1230, []ByteRange{{5, 1}}, []int64{0, 1000, 1100, 1200}},
+		{"shrink", 950, nil, []int64{900}},
+		{"shrink to boundary", 900, nil, nil},
+		{"empty", 0, nil, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := NewBlockIndex(bytes.NewReader(data), blockSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			modified := make([]byte, tt.size)
+			copy(modified, data)
+			for i := len(data); i < tt.size; i++ {
+				modified[i] = byte(i)
+			}
+			for _, d := range tt.dirty {
+				for i := d.Off; i < d.Off+d.Len; i++ {
+					modified[i] ^= 0xff
+				}
+			}
+			r := &countingReaderAt{r: bytes.NewReader(modified)}
+			sum, err := x.Update(r, int64(tt.size), tt.dirty)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(r.offs) != fmt.Sprint(tt.reads) {
+				t.Errorf("read blocks at %v; want %v", r.offs, tt.reads)
+			}
+			want, err := NewBlockIndex(bytes.NewReader(modified), blockSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x.Size != want.Size || fmt.Sprint(x.Blocks) != fmt.Sprint(want.Blocks) || x.Sum != 0 {
+				t.Errorf("got index %+v; want %+v with Sum 0", x, want)
+			}
+			tree, err := SumTree64(bytes.NewReader(modified), int64(tt.size), blockSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sum != tree || want.TreeSum() != tree {
+				t.Errorf("Update = 0x%x, TreeSum = 0x%x; want SumTree64 = 0x%x", sum, want.TreeSum(), tree)
+			}
+		})
+	}
+}
+
+func TestBlockIndexUpdateErrors(t *testing.T) {
+	data := make([]byte, 1000)
+	x, err := NewBlockIndex(bytes.NewReader(data), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := bytes.NewReader(data)
+	for _, dirty := range [][]ByteRange{{{-1, 2}}, {{0, -1}}, {{990, 11}}} {
+		if _, err := x.Update(r, 1000, dirty); err == nil {
+			t.Errorf("Update(%v): got nil error", dirty)
+		}
+	}
+	if _, err := x.Update(r, -1, nil); err == nil {
+		t.Error("negative size: got nil error")
+	}
+	errRead := errors.New("read failed")
+	if _, err := x.Update(errReaderAt{errRead}, 1000, []ByteRange{{0, 1}}); err != errRead {
+		t.Errorf("read error: got %v; want %v", err, errRead)
+	}
+	if _, err := x.Update(bytes.NewReader(data[:500]), 1000, []ByteRange{{600, 1}}); err != io.ErrUnexpectedEOF {
+		t.Errorf("short data: got %v; want %v", err, io.ErrUnexpectedEOF)
+	}
+}
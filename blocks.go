@@ -0,0 +1,36 @@
+package xxhash
+
+// This file exposes the building blocks of XXH64 for constructions that need
+// to drive the algorithm directly, such as custom tree or multi-lane hashes.
+// Ordinary hashing should use Sum64 or Digest instead.
+
+// Round is the XXH64 round function. It mixes one 8-byte little-endian word
+// of input into a lane accumulator.
+func Round(acc, input uint64) uint64 {
+	return round(acc, input)
+}
+
+// MergeRound folds a lane accumulator into a converged hash value, as XXH64
+// does with each of its four lanes after the last full block.
+func MergeRound(acc, val uint64) uint64 {
+	return mergeRound(acc, val)
+}
+
+// WriteBlocks mixes each full 32-byte block at the start of b into the four
+// lane accumulators in acc, using the same (assembly, where available) loop
+// as Digest, and returns the number of bytes consumed: len(b) rounded down
+// to a multiple of 32. Bytes of b beyond the last full block are ignored.
+//
+// For XXH64 with a given seed, acc starts as {seed + prime1 + prime2,
+// seed + prime2, seed, seed - prime1}; lane i takes the ith 8-byte word of
+// each block.
+func WriteBlocks(acc *[4]uint64, b []byte) int {
+	if len(b) < 32 {
+		return 0
+	}
+	var d Digest
+	d.v1, d.v2, d.v3, d.v4 = acc[0], acc[1], acc[2], acc[3]
+	n := writeBlocks(&d, b)
+	acc[0], acc[1], acc[2], acc[3] = d.v1, d.v2, d.v3, d.v4
+	return n
+}
@@ -0,0 +1,60 @@
+package xxhash
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+)
+
+func TestWriteBlocks(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	for _, n := range []int{0, 31, 32, 33, 64, 96, 999} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			const seed = 0x1234
+			acc := [4]uint64{seed + prime1v + prime2, seed + prime2, seed, seed - prime1v}
+			want := acc
+			for i := 0; i+32 <= n; i += 32 {
+				for j := range want {
+					want[j] = Round(want[j], u64(data[i+8*j:]))
+				}
+			}
+			if got := WriteBlocks(&acc, data[:n]); got != n/32*32 {
+				t.Fatalf("WriteBlocks consumed %d bytes; want %d", got, n/32*32)
+			}
+			if acc != want {
+				t.Fatalf("got accumulators %x; want %x", acc, want)
+			}
+		})
+	}
+}
+
+func TestBlocksSum64(t *testing.T) {
+	// Rebuild XXH64 from the exported pieces for input that is a whole
+	// number of blocks.
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	acc := [4]uint64{prime1v + prime2, prime2, 0, -prime1v}
+	WriteBlocks(&acc, data)
+	h := bits.RotateLeft64(acc[0], 1) + bits.RotateLeft64(acc[1], 7) +
+		bits.RotateLeft64(acc[2], 12) + bits.RotateLeft64(acc[3], 18)
+	for _, v := range acc {
+		h = MergeRound(h, v)
+	}
+	h = Mix64(h + uint64(len(data)))
+	if want := Sum64(data); h != want {
+		t.Fatalf("got 0x%x; want 0x%x", h, want)
+	}
+}
+
+func TestWriteBlocksAllocs(t *testing.T) {
+	data := make([]byte, 1024)
+	var acc [4]uint64
+	testAllocs(t, func() {
+		WriteBlocks(&acc, data)
+	})
+}
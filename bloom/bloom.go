@@ -0,0 +1,180 @@
+// Package bloom implements Bloom filters using XXH64.
+//
+// An element is hashed once, with xxhash.Sum64WithSeed and the filter's seed,
+// and its k probes are the indices xxhash.DeriveIndices derives from that
+// digest. Since those indices are built from 32-bit halves of the digest,
+// filters are limited to 2^32 bits.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// A Filter is a Bloom filter of m bits with k probes per element. It reports
+// whether an element may have been added: never falsely no, but falsely yes
+// with a probability that grows with the number of elements added.
+type Filter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+	seed uint64
+}
+
+// Limits on the filter parameters. They keep the encoding from describing
+// filters that cannot be allocated or probed evenly.
+const (
+	maxBits   = 1 << 32
+	maxProbes = 64
+)
+
+// New returns an empty Filter of m bits with k probes per element and a seed
+// of zero. It panics unless 0 < m <= 2^32 and 0 < k <= 64.
+func New(m, k uint64) *Filter {
+	return NewWithSeed(m, k, 0)
+}
+
+// NewWithSeed is like New, but it hashes elements with the given seed.
+// Filters with different seeds set different bits for the same elements, so
+// that, for example, filters built for different tenants fail differently.
+func NewWithSeed(m, k, seed uint64) *Filter {
+	if m == 0 || m > maxBits || k == 0 || k > maxProbes {
+		panic("bloom: invalid filter size or probe count")
+	}
+	return &Filter{bits: make([]uint64, (m+63)/64), m: m, k: k, seed: seed}
+}
+
+// NewWithEstimates returns an empty Filter sized to hold n elements with a
+// false positive rate of about p, using the optimal m = -n*ln(p)/ln(2)^2 bits
+// and k = (m/n)*ln(2) probes, at most 64. It panics unless n > 0 and
+// 0 < p < 1, or if the filter would need more than 2^32 bits.
+func NewWithEstimates(n uint64, p float64) *Filter {
+	if n == 0 || !(p > 0 && p < 1) {
+		panic("bloom: invalid estimates")
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	if m > maxBits {
+		panic("bloom: invalid estimates")
+	}
+	k := math.Min(maxProbes, math.Max(1, math.Round(m/float64(n)*math.Ln2)))
+	return New(uint64(m), uint64(k))
+}
+
+// M returns the number of bits in f.
+func (f *Filter) M() uint64 { return f.m }
+
+// K returns the number of probes per element.
+func (f *Filter) K() uint64 { return f.k }
+
+// Seed returns the seed that f hashes elements with.
+func (f *Filter) Seed() uint64 { return f.seed }
+
+// Add adds b to f.
+func (f *Filter) Add(b []byte) { f.add(xxhash.Sum64WithSeed(b, f.seed)) }
+
+// AddString adds s to f.
+func (f *Filter) AddString(s string) { f.add(xxhash.Sum64StringWithSeed(s, f.seed)) }
+
+// Test reports whether b may have been added to f.
+func (f *Filter) Test(b []byte) bool { return f.test(xxhash.Sum64WithSeed(b, f.seed)) }
+
+// TestString reports whether s may have been added to f.
+func (f *Filter) TestString(s string) bool {
+	return f.test(xxhash.Sum64StringWithSeed(s, f.seed))
+}
+
+func (f *Filter) add(h uint64) {
+	var probes [maxProbes]uint64
+	xxhash.DeriveIndices(h, f.k, f.m, probes[:])
+	for _, j := range probes[:f.k] {
+		f.bits[j/64] |= 1 << (j % 64)
+	}
+}
+
+func (f *Filter) test(h uint64) bool {
+	var probes [maxProbes]uint64
+	xxhash.DeriveIndices(h, f.k, f.m, probes[:])
+	for _, j := range probes[:f.k] {
+		if f.bits[j/64]&(1<<(j%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimateFalsePositiveRate returns the expected false positive rate of f
+// after n distinct elements have been added, (1 - e^(-k*n/m))^k.
+func (f *Filter) EstimateFalsePositiveRate(n uint64) float64 {
+	k, m := float64(f.k), float64(f.m)
+	return math.Pow(1-math.Exp(-k*float64(n)/m), k)
+}
+
+// Union adds all the elements of o to f. It returns an error if o does not
+// have the same number of bits, probes, and seed as f.
+func (f *Filter) Union(o *Filter) error {
+	if f.m != o.m || f.k != o.k || f.seed != o.seed {
+		return errors.New("bloom: union of filters with different parameters")
+	}
+	for i, w := range o.bits {
+		f.bits[i] |= w
+	}
+	return nil
+}
+
+// Reset removes all elements from f.
+func (f *Filter) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+}
+
+const (
+	magic      = "xbf\x02"
+	headerSize = len(magic) + 8 + 8 + 8
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// format is stable: the 4-byte identifier "xbf\x02", m, k, and the seed as
+// 8-byte little-endian integers, and then the bits as little-endian 64-bit
+// words, bit j of the filter being bit j%64 of word j/64.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	b := make([]byte, headerSize+8*len(f.bits))
+	copy(b, magic)
+	binary.LittleEndian.PutUint64(b[4:], f.m)
+	binary.LittleEndian.PutUint64(b[12:], f.k)
+	binary.LittleEndian.PutUint64(b[20:], f.seed)
+	for i, w := range f.bits {
+		binary.LittleEndian.PutUint64(b[headerSize+8*i:], w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// replacing f with the filter encoded in b.
+func (f *Filter) UnmarshalBinary(b []byte) error {
+	if len(b) < headerSize || string(b[:len(magic)]) != magic {
+		return errors.New("bloom: invalid filter encoding")
+	}
+	m := binary.LittleEndian.Uint64(b[4:])
+	k := binary.LittleEndian.Uint64(b[12:])
+	seed := binary.LittleEndian.Uint64(b[20:])
+	if m == 0 || m > maxBits || k == 0 || k > maxProbes {
+		return errors.New("bloom: invalid filter encoding")
+	}
+	words := (m + 63) / 64
+	if n := uint64(len(b) - headerSize); n%8 != 0 || n/8 != words {
+		return errors.New("bloom: invalid filter encoding")
+	}
+	bits := make([]uint64, words)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(b[headerSize+8*i:])
+	}
+	if r := m % 64; r != 0 && bits[words-1]>>r != 0 {
+		return errors.New("bloom: invalid filter encoding")
+	}
+	*f = Filter{bits: bits, m: m, k: k, seed: seed}
+	return nil
+}
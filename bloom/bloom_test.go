@@ -0,0 +1,165 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func TestFilter(t *testing.T) {
+	f := New(1000, 5)
+	f.Add([]byte("a"))
+	f.AddString("b")
+	for _, s := range []string{"a", "b"} {
+		if !f.TestString(s) || !f.Test([]byte(s)) {
+			t.Errorf("%q was added but tests negative", s)
+		}
+	}
+	if f.TestString("c") {
+		t.Error("\"c\" tests positive in a nearly empty filter")
+	}
+	f.Reset()
+	if f.TestString("a") {
+		t.Error("\"a\" tests positive after Reset")
+	}
+}
+
+func TestProbes(t *testing.T) {
+	// The set bits are exactly those chosen by xxhash.DeriveIndices.
+	f := New(1000, 7)
+	f.AddString("key")
+	idx := make([]uint64, 7)
+	xxhash.DeriveIndices(xxhash.Sum64String("key"), 7, 1000, idx)
+	want := New(1000, 7)
+	for _, j := range idx {
+		want.bits[j/64] |= 1 << (j % 64)
+	}
+	if fmt.Sprint(f.bits) != fmt.Sprint(want.bits) {
+		t.Errorf("got bits %x; want %x", f.bits, want.bits)
+	}
+
+	// With a seed, the probes come from the seeded digest.
+	f = NewWithSeed(1000, 7, 42)
+	f.AddString("key")
+	xxhash.DeriveIndices(xxhash.Sum64StringWithSeed("key", 42), 7, 1000, idx)
+	want = New(1000, 7)
+	for _, j := range idx {
+		want.bits[j/64] |= 1 << (j % 64)
+	}
+	if fmt.Sprint(f.bits) != fmt.Sprint(want.bits) {
+		t.Errorf("seed 42: got bits %x; want %x", f.bits, want.bits)
+	}
+}
+
+func TestFalsePositiveRate(t *testing.T) {
+	const n, p = 10000, 0.01
+	f := NewWithEstimates(n, p)
+	if f.M() != 95851 || f.K() != 7 {
+		t.Errorf("NewWithEstimates(%d, %g): got m=%d, k=%d; want m=95851, k=7", n, p, f.M(), f.K())
+	}
+	for i := 0; i < n; i++ {
+		f.AddString(fmt.Sprint("in", i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.TestString(fmt.Sprint("in", i)) {
+			t.Fatalf("element %d was added but tests negative", i)
+		}
+	}
+	var fp int
+	for i := 0; i < 100000; i++ {
+		if f.TestString(fmt.Sprint("out", i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / 100000; rate > 2*p {
+		t.Errorf("false positive rate %g; want about %g", rate, p)
+	}
+	if est := f.EstimateFalsePositiveRate(n); est < p/2 || est > 2*p {
+		t.Errorf("EstimateFalsePositiveRate(%d) = %g; want about %g", n, est, p)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	a, b := New(500, 3), New(500, 3)
+	a.AddString("a")
+	b.AddString("b")
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	if !a.TestString("a") || !a.TestString("b") {
+		t.Error("union is missing an element")
+	}
+	if err := a.Union(New(500, 4)); err == nil {
+		t.Error("union with different parameters: got nil error")
+	}
+	if err := a.Union(NewWithSeed(500, 3, 1)); err == nil {
+		t.Error("union with a different seed: got nil error")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	f := NewWithSeed(100, 3, 7)
+	f.AddString("x")
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 28+2*8 || string(b[:4]) != "xbf\x02" {
+		t.Fatalf("unexpected encoding %x", b)
+	}
+	var g Filter
+	if err := g.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if g.M() != 100 || g.K() != 3 || g.Seed() != 7 || !g.TestString("x") || fmt.Sprint(g.bits) != fmt.Sprint(f.bits) {
+		t.Errorf("round trip: got m=%d, k=%d, seed=%d, bits %x", g.M(), g.K(), g.Seed(), g.bits)
+	}
+
+	extraBit := append([]byte(nil), b...)
+	extraBit[len(extraBit)-1] = 0x80 // bit 127 of a 100-bit filter
+	header := func(m, k uint64) []byte {
+		h := append([]byte("xbf\x02"), make([]byte, 24)...)
+		binary.LittleEndian.PutUint64(h[4:], m)
+		binary.LittleEndian.PutUint64(h[12:], k)
+		return h
+	}
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("xbf\x01"), b[4:]...),
+		"short":     b[:len(b)-1],
+		"long":      append(append([]byte(nil), b...), 0),
+		"extra bit": extraBit,
+		"zero k":    append(header(100, 0), b[28:]...),
+		"huge m":    header(1<<64-1, 1), // (m+63)/64 wraps to 0 words
+		"large m":   append(header(1<<32+1, 1), make([]byte, 8)...),
+		"huge k":    append(header(100, 1<<62), b[28:]...),
+	} {
+		if err := g.UnmarshalBinary(bad); err == nil {
+			t.Errorf("%s: got nil error", name)
+		}
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	for _, fn := range []func(){
+		func() { New(0, 1) },
+		func() { New(1, 0) },
+		func() { New(1<<32+1, 1) },
+		func() { New(1, 65) },
+		func() { NewWithEstimates(1e9, 0.01) },
+		func() { NewWithEstimates(0, 0.1) },
+		func() { NewWithEstimates(10, 0) },
+		func() { NewWithEstimates(10, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,93 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Canonical64 is the canonical representation of an XXH64 digest: its 8
+// bytes in big-endian order, as defined by the xxHash specification and
+// written by the reference implementation (XXH64_canonicalFromHash) and by
+// xxhsum. It is the same as Digest.Sum's output.
+type Canonical64 [8]byte
+
+// CanonicalFromSum64 returns the canonical representation of h.
+func CanonicalFromSum64(h uint64) Canonical64 {
+	var c Canonical64
+	binary.BigEndian.PutUint64(c[:], h)
+	return c
+}
+
+// Sum64 returns the digest that c represents.
+func (c Canonical64) Sum64() uint64 {
+	return binary.BigEndian.Uint64(c[:])
+}
+
+// String returns c as 16 lowercase hexadecimal digits, as printed by xxhsum.
+func (c Canonical64) String() string {
+	var b [16]byte
+	return string(appendHex64(b[:0], c.Sum64()))
+}
+
+// ParseCanonical64 parses 16 hexadecimal digits, in either case, as printed
+// by xxhsum.
+func ParseCanonical64(s string) (Canonical64, error) {
+	h, err := parseHex64([]byte(s))
+	if err != nil {
+		return Canonical64{}, err
+	}
+	return CanonicalFromSum64(h), nil
+}
+
+// Sum64Hex returns the XXH64 digest of b as 16 lowercase hexadecimal digits,
+// as printed by xxhsum. To format a digest that has already been computed,
+// use CanonicalFromSum64(h).String().
+func Sum64Hex(b []byte) string {
+	var buf [16]byte
+	return string(appendHex64(buf[:0], Sum64(b)))
+}
+
+// AppendSum64Hex appends the XXH64 digest of b to dst as 16 lowercase
+// hexadecimal digits and returns the extended slice.
+func AppendSum64Hex(dst, b []byte) []byte {
+	return appendHex64(dst, Sum64(b))
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendHex64 appends h to b as 16 lowercase hexadecimal digits.
+func appendHex64(b []byte, h uint64) []byte {
+	for shift := 60; shift >= 0; shift -= 4 {
+		b = append(b, hexDigits[h>>uint(shift)&0xf])
+	}
+	return b
+}
+
+// parseHex64 parses exactly 16 hexadecimal digits, in either case.
+func parseHex64(b []byte) (uint64, error) {
+	if len(b) != 16 {
+		return 0, errors.New("xxhash: hex digest must be 16 digits")
+	}
+	var h uint64
+	for _, c := range b {
+		v := unhex(c)
+		if v < 0 {
+			return 0, errors.New("xxhash: invalid digit in hex digest")
+		}
+		h = h<<4 | uint64(v)
+	}
+	return h, nil
+}
+
+// unhex returns the value of the hexadecimal digit c, or -1 if c is not one.
+func unhex(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c - 'a' + 10)
+	case 'A' <= c && c <= 'F':
+		return int(c - 'A' + 10)
+	}
+	return -1
+}
@@ -0,0 +1,62 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestCanonical64(t *testing.T) {
+	// xxhsum prints ef46db3751d8e999 for the empty input.
+	h := Sum64(nil)
+	c := CanonicalFromSum64(h)
+	if got, want := c.String(), "ef46db3751d8e999"; got != want {
+		t.Fatalf("String: got %q; want %q", got, want)
+	}
+	if c.Sum64() != h {
+		t.Fatalf("Sum64: got 0x%x; want 0x%x", c.Sum64(), h)
+	}
+	if !bytes.Equal(c[:], New().Sum(nil)) {
+		t.Fatalf("canonical bytes %x differ from Digest.Sum", c[:])
+	}
+	for _, s := range []string{"ef46db3751d8e999", "EF46DB3751D8E999"} {
+		p, err := ParseCanonical64(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != c {
+			t.Fatalf("ParseCanonical64(%q) = %x; want %x", s, p, c)
+		}
+	}
+}
+
+func TestParseCanonical64Errors(t *testing.T) {
+	for _, s := range []string{"", "ef46db3751d8e99", "ef46db3751d8e9999", "ef46db3751d8e99g", "0x46db3751d8e999"} {
+		if _, err := ParseCanonical64(s); err == nil {
+			t.Errorf("ParseCanonical64(%q): got nil error", s)
+		}
+	}
+}
+
+func TestSum64Hex(t *testing.T) {
+	for _, s := range []string{"", "a", "hello, world", string(make([]byte, 100))} {
+		want := fmt.Sprintf("%016x", Sum64String(s))
+		if got := Sum64Hex([]byte(s)); got != want {
+			t.Errorf("Sum64Hex(%q) = %q; want %q", s, got, want)
+		}
+		if got := string(AppendSum64Hex([]byte("x:"), []byte(s))); got != "x:"+want {
+			t.Errorf("AppendSum64Hex(%q) = %q; want %q", s, got, "x:"+want)
+		}
+	}
+}
+
+func TestSum64HexAllocs(t *testing.T) {
+	b := []byte("hello")
+	dst := make([]byte, 0, 16)
+	testAllocs(t, func() {
+		dst = AppendSum64Hex(dst[:0], b)
+	})
+	if n := testing.AllocsPerRun(10, func() { _ = Sum64Hex(b) }); n > 1 {
+		t.Errorf("Sum64Hex: got %v allocations; want at most 1", n)
+	}
+}
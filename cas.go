@@ -0,0 +1,105 @@
+package xxhash
+
+import (
+	"errors"
+	"strings"
+)
+
+// A CASLayout describes how a content-addressed store names objects by
+// their digests. An object's key is Prefix, then Levels shard directories
+// named by successive Width-digit runs from the start of the digest in
+// lowercase hexadecimal, each followed by a slash, then the whole digest in
+// fixed-width lowercase hexadecimal. For example, with Levels 2 and Width 2,
+// the XXH64 digest 0x44bc2cf5ad770999 has the key
+//
+//	44/bc/44bc2cf5ad770999
+//
+// The zero CASLayout puts every object at the top level under its digest.
+type CASLayout struct {
+	Prefix string // prepended to every key, such as "blobs/"
+	Levels int    // number of shard directory levels
+	Width  int    // hexadecimal digits per shard directory name
+}
+
+// Key64 returns the key of the object with XXH64 (or other 64-bit) digest h.
+// It panics if the layout is invalid for 16-digit digests.
+func (l CASLayout) Key64(h uint64) string {
+	var a [16]byte
+	return l.key(appendHex64(a[:0], h))
+}
+
+// Key128 returns the key of the object with 128-bit digest h, whose 32
+// hexadecimal digits are as in h.Hex. It panics if the layout is invalid
+// for 32-digit digests.
+func (l CASLayout) Key128(h Uint128) string {
+	var a [32]byte
+	return l.key(appendHex64(appendHex64(a[:0], h.Hi), h.Lo))
+}
+
+// Parse64 parses a key in the form produced by Key64 and returns the
+// digest. It returns an error unless key is exactly Key64 of that digest.
+func (l CASLayout) Parse64(key string) (uint64, error) {
+	hex, err := l.parse(key, 16)
+	if err != nil {
+		return 0, err
+	}
+	return parseHex64([]byte(hex))
+}
+
+// Parse128 parses a key in the form produced by Key128 and returns the
+// digest. It returns an error unless key is exactly Key128 of that digest.
+func (l CASLayout) Parse128(key string) (Uint128, error) {
+	hex, err := l.parse(key, 32)
+	if err != nil {
+		return Uint128{}, err
+	}
+	hi, err := parseHex64([]byte(hex[:16]))
+	if err != nil {
+		return Uint128{}, err
+	}
+	lo, err := parseHex64([]byte(hex[16:]))
+	if err != nil {
+		return Uint128{}, err
+	}
+	return Uint128{Hi: hi, Lo: lo}, nil
+}
+
+func (l CASLayout) check(digits int) {
+	if l.Levels < 0 || l.Width < 0 || (l.Levels > 0 && l.Width == 0) || l.Levels*l.Width > digits {
+		panic("xxhash: invalid CAS layout")
+	}
+}
+
+func (l CASLayout) key(hex []byte) string {
+	l.check(len(hex))
+	b := make([]byte, 0, len(l.Prefix)+l.Levels*(l.Width+1)+len(hex))
+	b = append(b, l.Prefix...)
+	for i := 0; i < l.Levels; i++ {
+		b = append(b, hex[i*l.Width:(i+1)*l.Width]...)
+		b = append(b, '/')
+	}
+	return string(append(b, hex...))
+}
+
+// parse returns the digest part of key, having checked that the rest of
+// key is consistent with it.
+func (l CASLayout) parse(key string, digits int) (string, error) {
+	l.check(digits)
+	if !strings.HasPrefix(key, l.Prefix) || len(key) != len(l.Prefix)+l.Levels*(l.Width+1)+digits {
+		return "", errors.New("xxhash: malformed CAS key")
+	}
+	hex := key[len(key)-digits:]
+	shards := key[len(l.Prefix) : len(key)-digits]
+	for i := 0; i < l.Levels; i++ {
+		dir := shards[i*(l.Width+1) : (i+1)*(l.Width+1)]
+		if dir[:l.Width] != hex[i*l.Width:(i+1)*l.Width] || dir[l.Width] != '/' {
+			return "", errors.New("xxhash: malformed CAS key")
+		}
+	}
+	for i := 0; i < len(hex); i++ {
+		if c := hex[i]; 'A' <= c && c <= 'F' {
+			return "", errors.New("xxhash: malformed CAS key")
+		}
+	}
+	return hex, nil
+}
@@ -0,0 +1,64 @@
+package xxhash
+
+import "testing"
+
+func TestCASLayout(t *testing.T) {
+	h := Sum64String("abc")
+	h128 := SumXXH3_128([]byte("abc"))
+	for _, tt := range []struct {
+		l             CASLayout
+		want, want128 string
+	}{
+		{CASLayout{}, "44bc2cf5ad770999", "06b05ab6733a618578af5f94892f3950"},
+		{CASLayout{Levels: 2, Width: 2}, "44/bc/44bc2cf5ad770999", "06/b0/06b05ab6733a618578af5f94892f3950"},
+		{CASLayout{Prefix: "blobs/", Levels: 1, Width: 3}, "blobs/44b/44bc2cf5ad770999", "blobs/06b/06b05ab6733a618578af5f94892f3950"},
+		{CASLayout{Prefix: "x-", Levels: 4, Width: 4}, "x-44bc/2cf5/ad77/0999/44bc2cf5ad770999", "x-06b0/5ab6/733a/6185/06b05ab6733a618578af5f94892f3950"},
+	} {
+		if got := tt.l.Key64(h); got != tt.want {
+			t.Errorf("%+v: Key64 = %q; want %q", tt.l, got, tt.want)
+		}
+		if got, err := tt.l.Parse64(tt.want); err != nil || got != h {
+			t.Errorf("%+v: Parse64(%q) = (0x%x, %v); want (0x%x, nil)", tt.l, tt.want, got, err, h)
+		}
+		if got := tt.l.Key128(h128); got != tt.want128 {
+			t.Errorf("%+v: Key128 = %q; want %q", tt.l, got, tt.want128)
+		}
+		if got, err := tt.l.Parse128(tt.want128); err != nil || got != h128 {
+			t.Errorf("%+v: Parse128(%q) = (%v, %v); want (%v, nil)", tt.l, tt.want128, got, err, h128)
+		}
+		if _, err := tt.l.Parse64(tt.want128); err == nil {
+			t.Errorf("%+v: Parse64 of a 128-bit key: got nil error", tt.l)
+		}
+	}
+
+	l := CASLayout{Prefix: "blobs/", Levels: 2, Width: 2}
+	for _, key := range []string{
+		"",
+		"44/bc/44bc2cf5ad770999",
+		"blobs/44/bc/44bc2cf5ad77099",
+		"blobs/44/bd/44bc2cf5ad770999",
+		"blobs/44_bc/44bc2cf5ad770999",
+		"blobs/44/bc/44bc2cf5ad77099z",
+		"blobs/44/BC/44BC2CF5AD770999",
+		"blobs/44/bc/44bc2cf5ad770999/",
+	} {
+		if _, err := l.Parse64(key); err == nil {
+			t.Errorf("Parse64(%q): got nil error", key)
+		}
+	}
+}
+
+func TestCASLayoutPanics(t *testing.T) {
+	for _, l := range []CASLayout{{Levels: -1, Width: 2}, {Levels: 1}, {Width: -1}, {Levels: 3, Width: 6}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v: no panic", l)
+				}
+			}()
+			l.Key64(0)
+		}()
+	}
+	// Wide layouts are valid for 128-bit digests.
+	CASLayout{Levels: 3, Width: 6}.Key128(Uint128{})
+}
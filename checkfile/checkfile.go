@@ -0,0 +1,316 @@
+// Package checkfile reads and writes checksum files in the formats used by
+// xxhsum: the default GNU style,
+//
+//	44bc2cf5ad770999  file.txt
+//
+// and the BSD style printed by xxhsum --tag,
+//
+//	XXH64 (file.txt) = 44bc2cf5ad770999
+//
+// As in the reference xxhsum and GNU coreutils, a name that contains a
+// backslash, newline, or carriage return is escaped (as \\, \n, and \r) and
+// its line is marked with a leading backslash.
+package checkfile
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// An Algorithm is a hash function that may appear in a checksum file.
+type Algorithm int
+
+// The algorithms, numbered as by the -H option of xxhsum.
+const (
+	XXH32 Algorithm = iota
+	XXH64
+	XXH128
+	XXH3
+)
+
+var algorithmNames = [...]string{
+	XXH32:  "XXH32",
+	XXH64:  "XXH64",
+	XXH128: "XXH128",
+	XXH3:   "XXH3",
+}
+
+var algorithmSizes = [...]int{
+	XXH32:  4,
+	XXH64:  8,
+	XXH128: 16,
+	XXH3:   8,
+}
+
+func (a Algorithm) valid() bool { return a >= 0 && int(a) < len(algorithmNames) }
+
+// String returns the name of a as used in BSD-style lines, such as "XXH64".
+func (a Algorithm) String() string {
+	if !a.valid() {
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+	return algorithmNames[a]
+}
+
+// Size returns the length in bytes of a digest computed by a.
+func (a Algorithm) Size() int {
+	if !a.valid() {
+		return 0
+	}
+	return algorithmSizes[a]
+}
+
+// gnuPrefix returns the prefix that distinguishes a's digests in GNU-style
+// lines from other algorithms' digests of the same length.
+func (a Algorithm) gnuPrefix() string {
+	if a == XXH3 {
+		return "XXH3_"
+	}
+	return ""
+}
+
+// Sum computes the digest of the data in r using a, in the canonical
+// (big-endian) byte order in which digests are printed.
+func Sum(a Algorithm, r io.Reader) ([]byte, error) {
+	var sum []byte
+	var err error
+	switch a {
+	case XXH32:
+		d := xxhash.New32()
+		_, err = io.Copy(d, r)
+		sum = d.Sum(nil)
+	case XXH64:
+		d := xxhash.New()
+		_, err = io.Copy(d, r)
+		sum = d.Sum(nil)
+	case XXH128:
+		d := xxhash.NewXXH3()
+		_, err = io.Copy(d, r)
+		b := d.Sum128().Bytes()
+		sum = b[:]
+	case XXH3:
+		d := xxhash.NewXXH3()
+		_, err = io.Copy(d, r)
+		sum = d.Sum(nil)
+	default:
+		return nil, fmt.Errorf("checkfile: unknown algorithm %v", a)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// A Style is one of the two line formats.
+type Style int
+
+const (
+	GNU Style = iota // "<digest>  <name>"
+	BSD              // "<algorithm> (<name>) = <digest>"
+)
+
+// An Entry is one line of a checksum file: the digest of a named file.
+type Entry struct {
+	Algorithm Algorithm
+	Sum       []byte // in canonical byte order, Algorithm.Size() bytes long
+	Name      string
+}
+
+// Format returns e as a line in the given style, without a line terminator.
+func (e Entry) Format(style Style) string {
+	name, escaped := escape(e.Name)
+	var b strings.Builder
+	if escaped {
+		b.WriteByte('\\')
+	}
+	sum := hex.EncodeToString(e.Sum)
+	if style == BSD {
+		fmt.Fprintf(&b, "%s (%s) = %s", e.Algorithm, name, sum)
+	} else {
+		fmt.Fprintf(&b, "%s%s  %s", e.Algorithm.gnuPrefix(), sum, name)
+	}
+	return b.String()
+}
+
+// ErrSyntax is returned by ParseLine for a line that is not in either
+// format.
+var ErrSyntax = errors.New("checkfile: improperly formatted checksum line")
+
+// ParseLine parses a line, without its line terminator, in either style. In
+// GNU-style lines the algorithm is identified by the XXH3_ prefix or the
+// length of the digest. Hex digits may be upper or lower case.
+func ParseLine(line string) (Entry, Style, error) {
+	line = strings.TrimSuffix(line, "\r")
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	e, style, ok := parseBSD(line)
+	if !ok {
+		e, ok = parseGNU(line)
+		style = GNU
+	}
+	if !ok {
+		return Entry{}, 0, ErrSyntax
+	}
+	if escaped {
+		if e.Name, ok = unescape(e.Name); !ok {
+			return Entry{}, 0, ErrSyntax
+		}
+	}
+	return e, style, nil
+}
+
+func parseBSD(line string) (Entry, Style, bool) {
+	i := strings.Index(line, " (")
+	j := strings.LastIndex(line, ") = ")
+	if i <= 0 || j < i {
+		return Entry{}, 0, false
+	}
+	for a, name := range algorithmNames {
+		if line[:i] == name {
+			sum, ok := decodeSum(Algorithm(a), line[j+4:])
+			if !ok {
+				return Entry{}, 0, false
+			}
+			return Entry{Algorithm(a), sum, line[i+2 : j]}, BSD, true
+		}
+	}
+	return Entry{}, 0, false
+}
+
+func parseGNU(line string) (Entry, bool) {
+	i := strings.Index(line, "  ")
+	if i < 0 {
+		return Entry{}, false
+	}
+	digest, name := line[:i], line[i+2:]
+	if strings.HasPrefix(digest, XXH3.gnuPrefix()) {
+		sum, ok := decodeSum(XXH3, digest[len(XXH3.gnuPrefix()):])
+		return Entry{XXH3, sum, name}, ok
+	}
+	for _, a := range []Algorithm{XXH32, XXH64, XXH128} {
+		if len(digest) == 2*a.Size() {
+			sum, ok := decodeSum(a, digest)
+			return Entry{a, sum, name}, ok
+		}
+	}
+	return Entry{}, false
+}
+
+func decodeSum(a Algorithm, s string) ([]byte, bool) {
+	if len(s) != 2*a.Size() {
+		return nil, false
+	}
+	sum, err := hex.DecodeString(s)
+	return sum, err == nil
+}
+
+func escape(name string) (string, bool) {
+	if !strings.ContainsAny(name, "\\\n\r") {
+		return name, false
+	}
+	r := strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+	return r.Replace(name), true
+}
+
+func unescape(name string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i == len(name) {
+			return "", false
+		}
+		switch name[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// A ParseError reports a line that could not be parsed.
+type ParseError struct {
+	Line int    // 1-based line number
+	Text string // the line, without its terminator
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("checkfile: line %d: improperly formatted checksum line", e.Line)
+}
+
+// A Reader reads the entries of a checksum file.
+type Reader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewReader returns a Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{s: bufio.NewScanner(r)}
+}
+
+// Read returns the next entry and its style. It skips empty lines. At the
+// end of the input it returns io.EOF. If a line cannot be parsed, Read
+// returns a *ParseError; reading may continue with the next line.
+func (r *Reader) Read() (Entry, Style, error) {
+	for r.s.Scan() {
+		r.line++
+		text := r.s.Text()
+		if strings.TrimSuffix(text, "\r") == "" {
+			continue
+		}
+		e, style, err := ParseLine(text)
+		if err != nil {
+			return Entry{}, 0, &ParseError{Line: r.line, Text: text}
+		}
+		return e, style, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return Entry{}, 0, err
+	}
+	return Entry{}, 0, io.EOF
+}
+
+// A Writer writes entries to a checksum file in one style. Output is
+// buffered; call Flush when done.
+type Writer struct {
+	Style Style
+
+	w *bufio.Writer
+}
+
+// NewWriter returns a Writer that writes GNU-style lines to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes e as one line.
+func (w *Writer) Write(e Entry) error {
+	if !e.Algorithm.valid() || len(e.Sum) != e.Algorithm.Size() {
+		return fmt.Errorf("checkfile: %d-byte digest is invalid for %v", len(e.Sum), e.Algorithm)
+	}
+	w.w.WriteString(e.Format(w.Style))
+	return w.w.WriteByte('\n')
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
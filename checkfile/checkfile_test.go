@@ -0,0 +1,145 @@
+package checkfile
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestSum(t *testing.T) {
+	// Digests of "abc" from the reference xxhsum.
+	for _, tt := range []struct {
+		a    Algorithm
+		want string
+	}{
+		{XXH32, "32d153ff"},
+		{XXH64, "44bc2cf5ad770999"},
+		{XXH128, "06b05ab6733a618578af5f94892f3950"},
+		{XXH3, "78af5f94892f3950"},
+	} {
+		got, err := Sum(tt.a, strings.NewReader("abc"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want || len(got) != tt.a.Size() {
+			t.Errorf("%v: got %x; want %s", tt.a, got, tt.want)
+		}
+	}
+	if _, err := Sum(Algorithm(9), strings.NewReader("")); err == nil {
+		t.Error("unknown algorithm: got nil error")
+	}
+}
+
+var lineTests = []struct {
+	e     Entry
+	style Style
+	line  string
+}{
+	{Entry{XXH64, mustHex("44bc2cf5ad770999"), "abc.txt"}, GNU, "44bc2cf5ad770999  abc.txt"},
+	{Entry{XXH32, mustHex("32d153ff"), "a b"}, GNU, "32d153ff  a b"},
+	{Entry{XXH128, mustHex("06b05ab6733a618578af5f94892f3950"), "x"}, GNU, "06b05ab6733a618578af5f94892f3950  x"},
+	{Entry{XXH3, mustHex("78af5f94892f3950"), "x"}, GNU, "XXH3_78af5f94892f3950  x"},
+	{Entry{XXH64, mustHex("44bc2cf5ad770999"), "f (1)"}, BSD, "XXH64 (f (1)) = 44bc2cf5ad770999"},
+	{Entry{XXH3, mustHex("78af5f94892f3950"), "x"}, BSD, "XXH3 (x) = 78af5f94892f3950"},
+	{Entry{XXH64, mustHex("44bc2cf5ad770999"), "a\nb\\c\rd"}, GNU, `\44bc2cf5ad770999  a\nb\\c\rd`},
+	{Entry{XXH32, mustHex("32d153ff"), "a\nb"}, BSD, `\XXH32 (a\nb) = 32d153ff`},
+}
+
+func TestFormat(t *testing.T) {
+	for _, tt := range lineTests {
+		if got := tt.e.Format(tt.style); got != tt.line {
+			t.Errorf("Format(%v): got %q; want %q", tt.style, got, tt.line)
+		}
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	for _, tt := range lineTests {
+		e, style, err := ParseLine(tt.line)
+		if err != nil {
+			t.Errorf("ParseLine(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(e, tt.e) || style != tt.style {
+			t.Errorf("ParseLine(%q): got (%+v, %v); want (%+v, %v)", tt.line, e, style, tt.e, tt.style)
+		}
+	}
+	e, _, err := ParseLine("44BC2CF5AD770999  abc\r")
+	if err != nil || e.Name != "abc" || !bytes.Equal(e.Sum, mustHex("44bc2cf5ad770999")) {
+		t.Errorf("uppercase with CR: got (%+v, %v)", e, err)
+	}
+	for _, line := range []string{
+		"",
+		"44bc2cf5ad770999 abc",            // one space
+		"44bc2cf5ad77099  abc",            // odd length
+		"44bc2cf5ad77099z  abc",           // not hex
+		"XXH3_32d153ff  abc",              // wrong length for XXH3
+		"XXH64 (abc) = 32d153ff",          // wrong length for XXH64
+		"SHA256 (abc) = 44bc2cf5ad770999", // unknown algorithm
+		`\44bc2cf5ad770999  a\tb`,         // bad escape
+		`\44bc2cf5ad770999  a\`,
+	} {
+		if _, _, err := ParseLine(line); err != ErrSyntax {
+			t.Errorf("ParseLine(%q): got %v; want ErrSyntax", line, err)
+		}
+	}
+}
+
+func TestReaderWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, tt := range lineTests {
+		w.Style = tt.style
+		if err := w.Write(tt.e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write(Entry{XXH64, mustHex("32d153ff"), "x"}); err == nil {
+		t.Error("Write with the wrong digest length: got nil error")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	input := "\n" + strings.Replace(buf.String(), "\n", "\nbad line\n", 1)
+	r := NewReader(strings.NewReader(input))
+	var got []Entry
+	for {
+		e, style, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if pe, ok := err.(*ParseError); ok {
+			if pe.Line != 3 || pe.Text != "bad line" {
+				t.Errorf("got %+v; want line 3", pe)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := lineTests[len(got)].style; style != want {
+			t.Errorf("entry %d: got style %v; want %v", len(got), style, want)
+		}
+		got = append(got, e)
+	}
+	if len(got) != len(lineTests) {
+		t.Fatalf("got %d entries; want %d", len(got), len(lineTests))
+	}
+	for i, e := range got {
+		if !reflect.DeepEqual(e, lineTests[i].e) {
+			t.Errorf("entry %d: got %+v; want %+v", i, e, lineTests[i].e)
+		}
+	}
+}
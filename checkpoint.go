@@ -0,0 +1,82 @@
+package xxhash
+
+// A CheckpointDigest computes the XXH64 digest of everything written to it,
+// like Digest, and also records the running digest each time another interval
+// bytes have been written. Checkpoint i is the digest of the first
+// (i+1)*interval bytes.
+//
+// Since each checkpoint covers everything before it, comparing the
+// checkpoints of two nearly identical streams locates where they first
+// diverge: if checkpoint i is the first that differs, the first differing
+// byte is in [i*interval, (i+1)*interval). To hash each interval
+// independently instead, use Sum64Windows.
+//
+// CheckpointDigest implements hash.Hash64. The zero value is not ready to
+// use; create a CheckpointDigest with NewCheckpointDigest.
+type CheckpointDigest struct {
+	d           Digest
+	interval    uint64
+	next        uint64 // d.total at which the next checkpoint is taken
+	checkpoints []uint64
+}
+
+// NewCheckpointDigest creates a CheckpointDigest that records a checkpoint
+// every interval bytes. It panics if interval is not positive.
+func NewCheckpointDigest(interval int) *CheckpointDigest {
+	if interval <= 0 {
+		panic("xxhash: checkpoint interval must be positive")
+	}
+	c := &CheckpointDigest{interval: uint64(interval)}
+	c.Reset()
+	return c
+}
+
+// Reset clears c's state, including its checkpoints, so that it can be
+// reused. It keeps the interval.
+func (c *CheckpointDigest) Reset() {
+	c.d.Reset()
+	c.next = c.interval
+	c.checkpoints = c.checkpoints[:0]
+}
+
+// Size always returns 8 bytes.
+func (c *CheckpointDigest) Size() int { return 8 }
+
+// BlockSize always returns 32 bytes.
+func (c *CheckpointDigest) BlockSize() int { return 32 }
+
+// Write adds more data to c, recording any checkpoints it passes. It always
+// returns len(b), nil. It panics if c was not created by
+// NewCheckpointDigest.
+func (c *CheckpointDigest) Write(b []byte) (n int, err error) {
+	if c.interval == 0 {
+		panic("xxhash: CheckpointDigest not created by NewCheckpointDigest")
+	}
+	n = len(b)
+	for uint64(len(b)) >= c.next-c.d.total {
+		k := c.next - c.d.total
+		c.d.Write(b[:k])
+		b = b[k:]
+		c.checkpoints = append(c.checkpoints, c.d.Sum64())
+		c.next += c.interval
+	}
+	c.d.Write(b)
+	return n, nil
+}
+
+// WriteString adds more data to c. It always returns len(s), nil.
+func (c *CheckpointDigest) WriteString(s string) (n int, err error) {
+	return c.Write([]byte(s))
+}
+
+// Checkpoints returns the checkpoints recorded so far. The slice is only
+// valid until the next call to Write, WriteString, or Reset.
+func (c *CheckpointDigest) Checkpoints() []uint64 {
+	return c.checkpoints
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (c *CheckpointDigest) Sum(b []byte) []byte { return c.d.Sum(b) }
+
+// Sum64 returns the current hash.
+func (c *CheckpointDigest) Sum64() uint64 { return c.d.Sum64() }
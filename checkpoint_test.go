@@ -0,0 +1,85 @@
+package xxhash
+
+import (
+	"fmt"
+	"hash"
+	"testing"
+)
+
+var _ hash.Hash64 = (*CheckpointDigest)(nil)
+
+func TestCheckpointDigest(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, interval := range []int{1, 7, 100, 1000, 2000} {
+		for _, chunk := range []int{1, 3, 100, 1000} {
+			t.Run(fmt.Sprintf("interval=%d,chunk=%d", interval, chunk), func(t *testing.T) {
+				c := NewCheckpointDigest(interval)
+				for i := 0; i < len(data); i += chunk {
+					end := i + chunk
+					if end > len(data) {
+						end = len(data)
+					}
+					c.Write(data[i:end])
+				}
+				if got, want := c.Sum64(), Sum64(data); got != want {
+					t.Fatalf("Sum64: got 0x%x; want 0x%x", got, want)
+				}
+				cps := c.Checkpoints()
+				if len(cps) != len(data)/interval {
+					t.Fatalf("got %d checkpoints; want %d", len(cps), len(data)/interval)
+				}
+				for i, h := range cps {
+					if want := Sum64(data[:(i+1)*interval]); h != want {
+						t.Fatalf("checkpoint %d: got 0x%x; want 0x%x", i, h, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCheckpointDigestDivergence(t *testing.T) {
+	a := make([]byte, 1000)
+	b := make([]byte, 1000)
+	b[567] = 1
+	ca := NewCheckpointDigest(100)
+	ca.Write(a)
+	cb := NewCheckpointDigest(100)
+	cb.WriteString(string(b))
+	first := -1
+	for i := range ca.Checkpoints() {
+		if ca.Checkpoints()[i] != cb.Checkpoints()[i] {
+			first = i
+			break
+		}
+	}
+	if first != 5 {
+		t.Fatalf("first differing checkpoint: got %d; want 5", first)
+	}
+}
+
+func TestCheckpointDigestReset(t *testing.T) {
+	c := NewCheckpointDigest(10)
+	c.Write(make([]byte, 25))
+	c.Reset()
+	if len(c.Checkpoints()) != 0 {
+		t.Fatal("Reset kept checkpoints")
+	}
+	c.Write([]byte("0123456789abc"))
+	if cps := c.Checkpoints(); len(cps) != 1 || cps[0] != Sum64([]byte("0123456789")) {
+		t.Fatalf("after Reset: got checkpoints %x", cps)
+	}
+}
+
+func TestCheckpointDigestZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Write on a zero CheckpointDigest did not panic")
+		}
+	}()
+	var c CheckpointDigest
+	c.Write([]byte("abc"))
+}
@@ -0,0 +1,98 @@
+package xxhash
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Checksum64 is an XXH64 digest that is formatted as 16 lowercase hexadecimal
+// digits, the canonical form printed by xxhsum, in text and JSON. Parsing
+// accepts either case.
+type Checksum64 uint64
+
+// String returns c as 16 lowercase hexadecimal digits.
+func (c Checksum64) String() string {
+	var b [16]byte
+	return string(appendHex64(b[:0], uint64(c)))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Checksum64) MarshalText() ([]byte, error) {
+	return appendHex64(make([]byte, 0, 16), uint64(c)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Checksum64) UnmarshalText(text []byte) error {
+	h, err := parseHex64(text)
+	if err != nil {
+		return err
+	}
+	*c = Checksum64(h)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The checksum is a JSON string.
+func (c Checksum64) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 18)
+	b = append(b, '"')
+	b = appendHex64(b, uint64(c))
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON string in the
+// format written by MarshalJSON. As is conventional, null leaves c unchanged.
+func (c *Checksum64) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return errors.New("xxhash: checksum must be a JSON string")
+	}
+	return c.UnmarshalText(data[1 : len(data)-1])
+}
+
+// Value implements driver.Valuer, storing c in a database as a string of 16
+// lowercase hexadecimal digits. Use Checksum64Blob to store it as bytes
+// instead.
+func (c Checksum64) Value() (driver.Value, error) {
+	return c.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts the hex string stored by
+// Checksum64.Value, the 8-byte big-endian blob stored by
+// Checksum64Blob.Value, or an integer holding the digest's bits.
+func (c *Checksum64) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == 8 {
+			*c = Checksum64(binary.BigEndian.Uint64(v))
+			return nil
+		}
+		return c.UnmarshalText(v)
+	case int64:
+		*c = Checksum64(v)
+		return nil
+	}
+	return fmt.Errorf("xxhash: cannot scan %T into a checksum", src)
+}
+
+// Checksum64Blob is a Checksum64 that is stored in a database as its 8-byte
+// canonical (big-endian) representation, which suits binary columns such as
+// BYTEA or BINARY(8).
+type Checksum64Blob Checksum64
+
+// Value implements driver.Valuer.
+func (c Checksum64Blob) Value() (driver.Value, error) {
+	b := CanonicalFromSum64(uint64(c))
+	return b[:], nil
+}
+
+// Scan implements sql.Scanner. It accepts the same values as
+// Checksum64.Scan.
+func (c *Checksum64Blob) Scan(src interface{}) error {
+	return (*Checksum64)(c).Scan(src)
+}
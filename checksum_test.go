@@ -0,0 +1,89 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestChecksum64Text(t *testing.T) {
+	c := Checksum64(0x00ab)
+	if got, want := c.String(), "00000000000000ab"; got != want {
+		t.Fatalf("String: got %q; want %q", got, want)
+	}
+	text, err := c.MarshalText()
+	if err != nil || string(text) != "00000000000000ab" {
+		t.Fatalf("MarshalText: got (%q, %v)", text, err)
+	}
+	var p Checksum64
+	if err := p.UnmarshalText([]byte("00000000000000AB")); err != nil || p != c {
+		t.Fatalf("UnmarshalText: got (%v, %v); want %v", p, err, c)
+	}
+	for _, s := range []string{"", "ab", "00000000000000abc", "000000000000000g"} {
+		if err := p.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("UnmarshalText(%q): got nil error", s)
+		}
+	}
+}
+
+func TestChecksum64JSON(t *testing.T) {
+	type manifest struct {
+		Sum  Checksum64  `json:"sum"`
+		Opt  *Checksum64 `json:"opt"`
+		Keys map[Checksum64]int
+	}
+	m := manifest{Sum: Checksum64(Sum64String("x")), Keys: map[Checksum64]int{1: 2}}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"sum":"` + m.Sum.String() + `","opt":null,"Keys":{"0000000000000001":2}}`
+	if string(b) != want {
+		t.Fatalf("got %s; want %s", b, want)
+	}
+	var got manifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Sum != m.Sum || got.Opt != nil || got.Keys[1] != 2 {
+		t.Fatalf("round trip: got %+v; want %+v", got, m)
+	}
+	for _, s := range []string{`{"sum":123}`, `{"sum":"123"}`} {
+		if err := json.Unmarshal([]byte(s), &got); err == nil {
+			t.Errorf("Unmarshal(%s): got nil error", s)
+		}
+	}
+}
+
+func TestChecksum64SQL(t *testing.T) {
+	c := Checksum64(0x0123456789abcdef)
+	v, err := c.Value()
+	if err != nil || v != "0123456789abcdef" {
+		t.Fatalf("Value: got (%#v, %v)", v, err)
+	}
+	v, err = Checksum64Blob(c).Value()
+	if err != nil || !bytes.Equal(v.([]byte), []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}) {
+		t.Fatalf("blob Value: got (%#v, %v)", v, err)
+	}
+	for _, src := range []interface{}{
+		"0123456789abcdef",
+		[]byte("0123456789ABCDEF"),
+		[]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		int64(0x0123456789abcdef),
+	} {
+		var got Checksum64
+		if err := got.Scan(src); err != nil || got != c {
+			t.Errorf("Scan(%#v): got (%v, %v); want %v", src, got, err, c)
+		}
+		var blob Checksum64Blob
+		if err := blob.Scan(src); err != nil || Checksum64(blob) != c {
+			t.Errorf("blob Scan(%#v): got (%v, %v); want %v", src, Checksum64(blob), err, c)
+		}
+	}
+	var got Checksum64
+	for _, src := range []interface{}{nil, 1.5, "xyz", []byte{1, 2, 3}} {
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v): got nil error", src)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package xxhash
+
+import (
+	"io"
+	"math/bits"
+)
+
+// A RollingHash is a buzhash of the last window bytes rolled into it: the
+// bytes are mapped through a table of 256 random values, derived from the
+// seed as table[c] = Sum64WithSeed([]byte{c}, seed), and combined with
+// rotations and xor, so that each new byte updates the hash in constant time.
+// Until window bytes have been rolled in, it is the hash of all of them.
+type RollingHash struct {
+	table  [256]uint64
+	window []byte
+	pos    int
+	full   bool
+	h      uint64
+}
+
+// NewRollingHash returns a RollingHash over a window of window bytes, with
+// its table derived from seed. It panics if window is not positive.
+func NewRollingHash(window int, seed uint64) *RollingHash {
+	if window <= 0 {
+		panic("xxhash: rolling hash window must be positive")
+	}
+	return &RollingHash{table: rollingTable(seed), window: make([]byte, window)}
+}
+
+func rollingTable(seed uint64) [256]uint64 {
+	var t [256]uint64
+	for c := range t {
+		t[c] = Sum64WithSeed([]byte{byte(c)}, seed)
+	}
+	return t
+}
+
+// Roll adds c to the window, dropping the oldest byte if the window is full,
+// and returns the updated hash.
+func (rh *RollingHash) Roll(c byte) uint64 {
+	rh.h = bits.RotateLeft64(rh.h, 1) ^ rh.table[c]
+	if rh.full {
+		out := rh.window[rh.pos]
+		rh.h ^= bits.RotateLeft64(rh.table[out], len(rh.window))
+	}
+	rh.window[rh.pos] = c
+	rh.pos++
+	if rh.pos == len(rh.window) {
+		rh.pos, rh.full = 0, true
+	}
+	return rh.h
+}
+
+// Sum64 returns the hash of the current window.
+func (rh *RollingHash) Sum64() uint64 { return rh.h }
+
+// Reset empties the window.
+func (rh *RollingHash) Reset() {
+	rh.pos, rh.full, rh.h = 0, false, 0
+}
+
+// chunkWindow is the RollingHash window used by a Chunker.
+const chunkWindow = 64
+
+// A Chunk is a content-defined section of a Chunker's input.
+type Chunk struct {
+	Offset int64  // offset of the chunk in the input
+	Data   []byte // only valid until the next call to Next
+	Sum    uint64 // XXH64 digest of Data
+}
+
+// A Chunker splits a stream into content-defined chunks, so that an edit to
+// the stream only changes the chunks around it, and computes the XXH64
+// digest of each chunk in the same pass.
+//
+// A chunk ends after a byte at which it is at least minSize bytes long and
+// the low log2(avgSize) bits of the RollingHash (seed 0) of its last 64 bytes
+// are zero, with avgSize rounded down to a power of two; or when it reaches
+// maxSize bytes; or at the end of the stream. Before a chunk is 64 bytes
+// long, the RollingHash covers the whole chunk.
+type Chunker struct {
+	r          io.Reader
+	table      [256]uint64
+	min, max   int
+	mask       uint64
+	buf        []byte
+	start, end int
+	off        int64
+	err        error
+}
+
+// NewChunker returns a Chunker that reads from r. It panics unless
+// 0 < minSize <= avgSize <= maxSize.
+func NewChunker(r io.Reader, minSize, avgSize, maxSize int) *Chunker {
+	if minSize <= 0 || minSize > avgSize || avgSize > maxSize {
+		panic("xxhash: invalid chunk sizes")
+	}
+	return &Chunker{
+		r:     r,
+		table: rollingTable(0),
+		min:   minSize,
+		max:   maxSize,
+		mask:  1<<uint(bits.Len(uint(avgSize))-1) - 1,
+		buf:   make([]byte, 2*maxSize),
+	}
+}
+
+// Next returns the next chunk of the stream. At the end of the stream it
+// returns io.EOF. If reading fails, Next returns the error; once Next has
+// returned an error, all later calls return the same error.
+func (c *Chunker) Next() (Chunk, error) {
+	if c.end-c.start < c.max && c.err == nil {
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+		c.fill()
+	}
+	data := c.buf[c.start:c.end]
+	if len(data) > c.max {
+		data = data[:c.max]
+	}
+	n, cut := len(data), len(data) == c.max
+	// A boundary depends only on the chunkWindow bytes before it, so start
+	// rolling just before the first possible boundary, and find the byte
+	// leaving the window in data itself.
+	var h uint64
+	start := c.min - chunkWindow
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(data); i++ {
+		h = bits.RotateLeft64(h, 1) ^ c.table[data[i]]
+		if i-chunkWindow >= start {
+			h ^= c.table[data[i-chunkWindow]] // rotated by chunkWindow, a no-op
+		}
+		if i+1 >= c.min && h&c.mask == 0 {
+			n, cut = i+1, true
+			break
+		}
+	}
+	if !cut && c.err != io.EOF || n == 0 {
+		// The last chunk could have continued past a read error.
+		c.start = c.end
+		return Chunk{}, c.err
+	}
+	chunk := Chunk{Offset: c.off, Data: data[:n:n], Sum: Sum64(data[:n])}
+	c.start += n
+	c.off += int64(n)
+	return chunk, nil
+}
+
+func (c *Chunker) fill() {
+	for c.end < len(c.buf) {
+		n, err := c.r.Read(c.buf[c.end:])
+		c.end += n
+		if err != nil {
+			c.err = err
+			return
+		}
+	}
+}
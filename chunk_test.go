@@ -0,0 +1,204 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// chunkTestData returns n deterministic pseudorandom bytes.
+func chunkTestData(n int) []byte {
+	var b []byte
+	for i := uint64(0); len(b) < n; i++ {
+		b = appendUint64(b, Sum64Uint64(i))
+	}
+	return b[:n]
+}
+
+func TestRollingHash(t *testing.T) {
+	data := chunkTestData(1000)
+	for _, window := range []int{1, 7, 64, 100} {
+		rh := NewRollingHash(window, 1)
+		for i, c := range data {
+			got := rh.Roll(c)
+			// Rolling only the bytes in the window gives the same hash.
+			start := i + 1 - window
+			if start < 0 {
+				start = 0
+			}
+			fresh := NewRollingHash(window, 1)
+			var want uint64
+			for _, c := range data[start : i+1] {
+				want = fresh.Roll(c)
+			}
+			if got != want || rh.Sum64() != got {
+				t.Fatalf("window=%d, i=%d: got 0x%x; want 0x%x", window, i, got, want)
+			}
+		}
+		rh.Reset()
+		if rh.Sum64() != 0 || rh.Roll(data[0]) != NewRollingHash(window, 1).Roll(data[0]) {
+			t.Fatalf("window=%d: Reset did not empty the window", window)
+		}
+	}
+	if NewRollingHash(8, 1).Roll('a') == NewRollingHash(8, 2).Roll('a') {
+		t.Error("different seeds give the same hash")
+	}
+}
+
+func chunkAll(t *testing.T, r io.Reader, minSize, avgSize, maxSize int) []Chunk {
+	t.Helper()
+	c := NewChunker(r, minSize, avgSize, maxSize)
+	var chunks []Chunk
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			return chunks
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunk.Data = append([]byte(nil), chunk.Data...)
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	data := chunkTestData(1 << 20)
+	for _, r := range []struct {
+		name string
+		r    io.Reader
+	}{
+		{"whole", bytes.NewReader(data)},
+		{"onebyte", iotest.OneByteReader(bytes.NewReader(data))},
+	} {
+		chunks := chunkAll(t, r.r, 1024, 4096, 16384)
+		var off int64
+		var sawShort bool
+		for i, c := range chunks {
+			if c.Offset != off {
+				t.Fatalf("%s: chunk %d at offset %d; want %d", r.name, i, c.Offset, off)
+			}
+			if !bytes.Equal(c.Data, data[off:off+int64(len(c.Data))]) {
+				t.Fatalf("%s: chunk %d has the wrong data", r.name, i)
+			}
+			if c.Sum != Sum64(c.Data) {
+				t.Fatalf("%s: chunk %d has sum 0x%x; want 0x%x", r.name, i, c.Sum, Sum64(c.Data))
+			}
+			last := i == len(chunks)-1
+			if len(c.Data) > 16384 || len(c.Data) < 1024 && !last {
+				t.Fatalf("%s: chunk %d has %d bytes", r.name, i, len(c.Data))
+			}
+			sawShort = sawShort || len(c.Data) < 16384
+			off += int64(len(c.Data))
+		}
+		if off != int64(len(data)) {
+			t.Fatalf("%s: chunks cover %d bytes; want %d", r.name, off, len(data))
+		}
+		if !sawShort {
+			t.Fatalf("%s: every chunk was cut at the maximum size", r.name)
+		}
+		if n := len(data) / len(chunks); n < 2048 || n > 8192 {
+			t.Errorf("%s: average chunk size %d; want about 1024+4096", r.name, n)
+		}
+	}
+
+	if chunks := chunkAll(t, bytes.NewReader(nil), 1, 1, 1); len(chunks) != 0 {
+		t.Errorf("empty input: got %d chunks", len(chunks))
+	}
+}
+
+func TestChunkerResync(t *testing.T) {
+	// Inserting bytes near the start of the stream only changes the chunks
+	// around the insertion.
+	data := chunkTestData(256 << 10)
+	edited := append([]byte("inserted"), data...)
+	sums := make(map[uint64]bool)
+	for _, c := range chunkAll(t, bytes.NewReader(data), 512, 2048, 8192) {
+		sums[c.Sum] = true
+	}
+	chunks := chunkAll(t, bytes.NewReader(edited), 512, 2048, 8192)
+	changed := 0
+	for _, c := range chunks {
+		if !sums[c.Sum] {
+			changed++
+		}
+	}
+	if changed > 2 {
+		t.Errorf("%d of %d chunks changed", changed, len(chunks))
+	}
+}
+
+func TestChunkerError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(chunkTestData(100000)), errReader{errRead})
+	c := NewChunker(r, 64, 256, 1024)
+	var n int
+	for {
+		chunk, err := c.Next()
+		if err != nil {
+			if err != errRead {
+				t.Fatalf("got error %v; want %v", err, errRead)
+			}
+			break
+		}
+		n += len(chunk.Data)
+	}
+	if n > 100000 {
+		t.Fatalf("got %d bytes of chunks from 100000 bytes of input", n)
+	}
+	if _, err := c.Next(); err != errRead {
+		t.Fatalf("after error: got %v; want %v", err, errRead)
+	}
+}
+
+func TestNewChunkerPanics(t *testing.T) {
+	for _, sizes := range [][3]int{{0, 1, 1}, {2, 1, 4}, {1, 4, 2}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewChunker%v did not panic", sizes)
+				}
+			}()
+			NewChunker(bytes.NewReader(nil), sizes[0], sizes[1], sizes[2])
+		}()
+	}
+}
+
+func TestChunkerBoundaries(t *testing.T) {
+	// Check every chunk against a direct transcription of the definition.
+	data := chunkTestData(16 << 10)
+	for _, sizes := range [][3]int{{16, 256, 4096}, {200, 1000, 2000}, {1, 1, 1}} {
+		minSize, avgSize, maxSize := sizes[0], sizes[1], sizes[2]
+		mask := uint64(1)
+		for mask*2 <= uint64(avgSize) {
+			mask *= 2
+		}
+		mask--
+		isBoundary := func(chunk []byte) bool {
+			if len(chunk) < minSize {
+				return false
+			}
+			if len(chunk) > chunkWindow {
+				chunk = chunk[len(chunk)-chunkWindow:]
+			}
+			rh := NewRollingHash(chunkWindow, 0)
+			for _, c := range chunk {
+				rh.Roll(c)
+			}
+			return rh.Sum64()&mask == 0
+		}
+		for i, c := range chunkAll(t, bytes.NewReader(data), minSize, avgSize, maxSize) {
+			for n := 1; n < len(c.Data); n++ {
+				if isBoundary(c.Data[:n]) {
+					t.Fatalf("sizes %v: chunk %d has a boundary after %d bytes", sizes, i, n)
+				}
+			}
+			end := c.Offset+int64(len(c.Data)) == int64(len(data))
+			if !isBoundary(c.Data) && len(c.Data) != maxSize && !end {
+				t.Fatalf("sizes %v: chunk %d does not end at a boundary", sizes, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package xxhash
+
+// Combine returns the identity of a sequence of segments extended by one
+// more segment, given the identity h1 of the sequence so far and the XXH64
+// digest h2 and length len2 of the new segment. It lets an append-only log
+// maintain a whole-log hash without rereading old segments:
+//
+//	var id uint64
+//	for _, seg := range segments {
+//		id = Combine(id, Sum64(seg), int64(len(seg)))
+//	}
+//
+// The result is not the XXH64 digest of the concatenated data: it depends on
+// how the data is split into segments. It is defined as the XXH64 digest
+// (seed 0) of h1, h2, and len2, each encoded as a little-endian uint64, so
+// other implementations can reproduce it. Combine panics if len2 is negative.
+func Combine(h1, h2 uint64, len2 int64) uint64 {
+	if len2 < 0 {
+		panic("xxhash: Combine length must not be negative")
+	}
+	h := prime5 + 24
+	h ^= round(0, h1)
+	h = rol27(h)*prime1 + prime4
+	h ^= round(0, h2)
+	h = rol27(h)*prime1 + prime4
+	h ^= round(0, uint64(len2))
+	h = rol27(h)*prime1 + prime4
+	return avalanche(h)
+}
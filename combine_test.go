@@ -0,0 +1,60 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	for _, tt := range []struct {
+		h1, h2 uint64
+		len2   int64
+	}{
+		{0, 0, 0},
+		{1, 2, 3},
+		{0xef46db3751d8e999, 0x44bc2cf5ad770999, 3},
+		{1<<64 - 1, 1<<64 - 1, 1<<63 - 1},
+	} {
+		want := Sum64(appendUint64(appendUint64(appendUint64(nil, tt.h1), tt.h2), uint64(tt.len2)))
+		if got := Combine(tt.h1, tt.h2, tt.len2); got != want {
+			t.Errorf("Combine(0x%x, 0x%x, %d) = 0x%x; want 0x%x", tt.h1, tt.h2, tt.len2, got, want)
+		}
+	}
+}
+
+func TestCombineSegments(t *testing.T) {
+	// Chaining the same segments gives the same identity; splitting the same
+	// data differently, or reordering segments, does not.
+	chain := func(segs ...string) uint64 {
+		var id uint64
+		for _, s := range segs {
+			id = Combine(id, Sum64String(s), int64(len(s)))
+		}
+		return id
+	}
+	a := chain("abc", "def", "ghi")
+	ids := map[uint64]string{a: "abc,def,ghi"}
+	for _, segs := range [][]string{
+		{"abc", "defghi"},
+		{"abc", "ghi", "def"},
+		{"abc", "def", "ghi", ""},
+	} {
+		id := chain(segs...)
+		if prev, ok := ids[id]; ok {
+			t.Errorf("%q and %s have the same identity 0x%x", segs, prev, id)
+		}
+		ids[id] = fmt.Sprint(segs)
+	}
+	if got := chain("abc", "def", "ghi"); got != a {
+		t.Errorf("repeated chain: got 0x%x; want 0x%x", got, a)
+	}
+}
+
+func TestCombineNegativeLength(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	Combine(0, 0, -1)
+}
@@ -0,0 +1,58 @@
+package xxhash
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// A ProgressFunc is called by the Context hashing functions with the number
+// of bytes hashed so far.
+type ProgressFunc func(hashed int64)
+
+// Sum64ReaderContext is like Sum64Reader, but it stops early with ctx.Err()
+// once ctx is done, and it reports its progress.
+//
+// If progress is non-nil, it is called each time at least interval more bytes
+// have been hashed since the last call (or since the start). An interval of
+// zero or less calls progress after every read. Reading stops with ctx.Err()
+// as soon as ctx is done; the context is checked before the first read and
+// after each read, so a read that blocks is not interrupted.
+//
+// On error, Sum64ReaderContext returns a zero digest, the number of bytes
+// read, and the error.
+func Sum64ReaderContext(ctx context.Context, r io.Reader, interval int64, progress ProgressFunc) (uint64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	d := GetDigest()
+	defer PutDigest(d)
+	var hashed int64
+	next := interval
+	total, err := readAdaptive(r, minReadBufSize, maxReadBufSize, func(b []byte) error {
+		d.Write(b)
+		hashed += int64(len(b))
+		if progress != nil && len(b) > 0 && hashed >= next {
+			progress(hashed)
+			next = hashed + interval
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return 0, total, err
+	}
+	return d.Sum64(), total, nil
+}
+
+// Sum64FileContext is like Sum64File, but it reads the file (rather than
+// memory-mapping it) so that it can stop early once ctx is done and report
+// its progress as described for Sum64ReaderContext.
+func Sum64FileContext(ctx context.Context, path string, interval int64, progress ProgressFunc) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	sum, _, err := Sum64ReaderContext(ctx, f, interval, progress)
+	return sum, err
+}
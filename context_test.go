@@ -0,0 +1,106 @@
+package xxhash
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSum64ReaderContext(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	every := make([]int64, len(data))
+	for i := range every {
+		every[i] = int64(i + 1)
+	}
+	for _, tt := range []struct {
+		interval int64
+		want     []int64
+	}{
+		{300, []int64{300, 600, 900}},
+		{1000, []int64{1000}},
+		{10000, nil},
+		{0, every},
+	} {
+		var calls []int64
+		progress := func(n int64) { calls = append(calls, n) }
+		r := iotest.OneByteReader(bytes.NewReader(data))
+		got, n, err := Sum64ReaderContext(context.Background(), r, tt.interval, progress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := Sum64(data); got != want || n != int64(len(data)) {
+			t.Fatalf("got (0x%x, %d); want (0x%x, %d)", got, n, want, len(data))
+		}
+		if fmt.Sprint(calls) != fmt.Sprint(tt.want) {
+			t.Errorf("interval %d: progress called with %v; want %v", tt.interval, calls, tt.want)
+		}
+	}
+}
+
+// cancelReader cancels a context after returning the first n bytes of r.
+type cancelReader struct {
+	r      io.Reader
+	n      int64
+	cancel context.CancelFunc
+}
+
+func (c *cancelReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > c.n {
+		p = p[:c.n]
+	}
+	n, err := c.r.Read(p)
+	c.n -= int64(n)
+	if c.n == 0 {
+		c.cancel()
+	}
+	return n, err
+}
+
+func TestSum64ReaderContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := &cancelReader{r: bytes.NewReader(make([]byte, 1000)), n: 100, cancel: cancel}
+	h, n, err := Sum64ReaderContext(ctx, r, 0, nil)
+	if h != 0 || n != 100 || err != context.Canceled {
+		t.Fatalf("got (0x%x, %d, %v); want (0, 100, %v)", h, n, err, context.Canceled)
+	}
+}
+
+func TestSum64ReaderContextError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 10)), errReader{errRead})
+	h, n, err := Sum64ReaderContext(context.Background(), r, 0, nil)
+	if h != 0 || n != 10 || err != errRead {
+		t.Fatalf("got (0x%x, %d, %v); want (0, 10, %v)", h, n, err, errRead)
+	}
+}
+
+func TestSum64FileContext(t *testing.T) {
+	b := bytes.Repeat([]byte("context "), 10000)
+	f := writeTempFile(t, b)
+	defer removeTempFile(f)
+	var last int64
+	got, err := Sum64FileContext(context.Background(), f.Name(), 1<<10, func(n int64) { last = n })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Sum64(b); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+	if last != int64(len(b)) {
+		t.Fatalf("last progress call reported %d bytes; want %d", last, len(b))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Sum64FileContext(ctx, f.Name(), 0, nil); err != context.Canceled {
+		t.Fatalf("canceled: got err=%v; want %v", err, context.Canceled)
+	}
+}
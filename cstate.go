@@ -0,0 +1,53 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// XXH64StateSize is the size of XXH64_state_t in the C reference
+// implementation (xxHash v0.7 and later).
+const XXH64StateSize = 88
+
+// ExportXXH64State appends d's state to b in the memory layout of the C
+// reference implementation's XXH64_state_t on a little-endian machine, and
+// returns the extended slice. The C library can resume hashing from the
+// result after it is copied into an XXH64_state_t with memcpy.
+//
+// The layout is the total length, the four accumulators, the 32-byte block
+// buffer, the number of buffered bytes as a uint32, and 12 reserved zero
+// bytes, all little-endian.
+func (d *Digest) ExportXXH64State(b []byte) []byte {
+	v1, v2, v3, v4 := d.accs()
+	b = appendUint64(b, d.total)
+	b = appendUint64(b, v1)
+	b = appendUint64(b, v2)
+	b = appendUint64(b, v3)
+	b = appendUint64(b, v4)
+	b = append(b, d.mem[:]...)
+	var tail [16]byte
+	binary.LittleEndian.PutUint32(tail[:4], uint32(d.n))
+	return append(b, tail[:]...)
+}
+
+// ImportXXH64State sets d's state from state, which must be an XXH64_state_t
+// in the layout described by ExportXXH64State, such as one copied out of the
+// C library on a little-endian machine.
+func (d *Digest) ImportXXH64State(state []byte) error {
+	if len(state) != XXH64StateSize {
+		return errors.New("xxhash: invalid XXH64_state_t size")
+	}
+	total := u64(state[0:8])
+	n := u32(state[72:76])
+	if n >= uint32(len(d.mem)) || uint64(n) != total%uint64(len(d.mem)) {
+		return errors.New("xxhash: inconsistent XXH64_state_t buffer size")
+	}
+	d.total = total
+	d.v1 = u64(state[8:16])
+	d.v2 = u64(state[16:24])
+	d.v3 = u64(state[24:32])
+	d.v4 = u64(state[32:40])
+	copy(d.mem[:], state[40:72])
+	d.n = int(n)
+	return nil
+}
@@ -0,0 +1,85 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// These states were dumped from the C reference implementation (v0.8) on
+// amd64 after hashing the first n bytes of "abc...xyzabc..." in chunks.
+var cStateTests = []struct {
+	n     int
+	chunk int
+	seed  uint64
+	sum   uint64
+	state string
+}{
+	{
+		3, 3, 0, 0x44bc2cf5ad770999,
+		"0300000000000000d6b5c0adee27ea604febd4273daeb2c20000000000000000" +
+			"7935147a4e86c861616263000000000000000000000000000000000000000000" +
+			"000000000000000003000000000000000000000000000000",
+	},
+	{
+		32, 32, 0, 0x4da6cbe536cf55c7,
+		"2000000000000000b5394b20461c20247d00a3ff3f2c3ef35b3081a8f6a091fa" +
+			"401083d1b6d26caf000000000000000000000000000000000000000000000000" +
+			"000000000000000000000000000000000000000000000000",
+	},
+	{
+		100, 7, 42, 0x64ae6df2d9c9bb5c,
+		"6400000000000000aec872093c2ebfbb5a6d6d80f61e7c9728450a295ce0bb50" +
+			"8c878f3a2544a762737475767172737475767778797a6162636465666768696a" +
+			"6b6c6d6e6f70717204000000000000000000000000000000",
+	},
+}
+
+func TestXXH64State(t *testing.T) {
+	input := make([]byte, 100)
+	for i := range input {
+		input[i] = byte('a' + i%26)
+	}
+	for _, tt := range cStateTests {
+		state, err := hex.DecodeString(tt.state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Digest
+		if err := d.ImportXXH64State(state); err != nil {
+			t.Fatalf("n=%d: ImportXXH64State: %s", tt.n, err)
+		}
+		if got := d.Sum64(); got != tt.sum {
+			t.Errorf("n=%d: after import, got 0x%016x; want 0x%016x", tt.n, got, tt.sum)
+		}
+		d.Write([]byte("more"))
+		if got, want := d.Sum64(), Sum64WithSeed(append(input[:tt.n:tt.n], "more"...), tt.seed); got != want {
+			t.Errorf("n=%d: after import and Write, got 0x%016x; want 0x%016x", tt.n, got, want)
+		}
+
+		// Hashing the same chunks in Go leaves a byte-for-byte identical state.
+		d2 := NewWithSeed(tt.seed)
+		for i := 0; i < tt.n; i += tt.chunk {
+			end := i + tt.chunk
+			if end > tt.n {
+				end = tt.n
+			}
+			d2.Write(input[i:end])
+		}
+		if got := d2.ExportXXH64State(nil); !bytes.Equal(got, state) {
+			t.Errorf("n=%d: ExportXXH64State: got\n%x\nwant\n%x", tt.n, got, state)
+		}
+	}
+}
+
+func TestImportXXH64StateErrors(t *testing.T) {
+	var d Digest
+	if err := d.ImportXXH64State(make([]byte, XXH64StateSize-1)); err == nil {
+		t.Error("short state: got nil error")
+	}
+	state := New().ExportXXH64State(nil)
+	state[72] = 5 // buffered size disagrees with total length
+	if err := d.ImportXXH64State(state); err == nil {
+		t.Error("inconsistent state: got nil error")
+	}
+}
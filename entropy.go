@@ -0,0 +1,65 @@
+package xxhash
+
+import "math"
+
+// An EntropyDigest computes the XXH64 digest of its input together with a
+// byte-frequency histogram, from which it estimates the input's Shannon
+// entropy. The digest is identical to that computed by Digest.
+//
+// EntropyDigest implements hash.Hash64.
+type EntropyDigest struct {
+	d      Digest
+	counts [256]uint64
+}
+
+// NewEntropyDigest creates a new EntropyDigest.
+func NewEntropyDigest() *EntropyDigest {
+	var e EntropyDigest
+	e.Reset()
+	return &e
+}
+
+// Reset clears the EntropyDigest's state so that it can be reused.
+func (e *EntropyDigest) Reset() {
+	e.d.Reset()
+	e.counts = [256]uint64{}
+}
+
+// Size always returns 8 bytes.
+func (e *EntropyDigest) Size() int { return 8 }
+
+// BlockSize always returns 32 bytes.
+func (e *EntropyDigest) BlockSize() int { return 32 }
+
+// Write adds more data to e. It always returns len(b), nil.
+func (e *EntropyDigest) Write(b []byte) (n int, err error) {
+	for _, c := range b {
+		e.counts[c]++
+	}
+	return e.d.Write(b)
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (e *EntropyDigest) Sum(b []byte) []byte { return e.d.Sum(b) }
+
+// Sum64 returns the current hash.
+func (e *EntropyDigest) Sum64() uint64 { return e.d.Sum64() }
+
+// ShannonEntropy returns the Shannon entropy of the byte distribution of the
+// input written so far, in bits per byte. The result ranges from 0 (empty
+// input, or a single repeated byte value) to 8 (every byte value equally
+// frequent).
+func (e *EntropyDigest) ShannonEntropy() float64 {
+	total := float64(e.d.total)
+	if total == 0 {
+		return 0
+	}
+	var h float64
+	for _, c := range e.counts {
+		if c > 0 {
+			p := float64(c) / total
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
@@ -0,0 +1,57 @@
+package xxhash
+
+import (
+	"bytes"
+	"hash"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var _ hash.Hash64 = (*EntropyDigest)(nil)
+
+func TestEntropyDigest(t *testing.T) {
+	uniform := make([]byte, 256*16)
+	for i := range uniform {
+		uniform[i] = byte(i)
+	}
+	random := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(random)
+	for _, tt := range []struct {
+		name     string
+		input    []byte
+		min, max float64
+	}{
+		{"empty", nil, 0, 0},
+		{"zeros", make([]byte, 1000), 0, 0},
+		{"two values", bytes.Repeat([]byte("ab"), 500), 1, 1},
+		{"uniform", uniform, 8, 8},
+		{"random", random, 7.99, 8},
+		{"text", []byte("Call me Ishmael. Some years ago--never mind how long precisely-"), 3, 5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEntropyDigest()
+			e.Write(tt.input[:len(tt.input)/2])
+			e.Write(tt.input[len(tt.input)/2:])
+			if got, want := e.Sum64(), Sum64(tt.input); got != want {
+				t.Fatalf("Sum64: got 0x%x; want 0x%x", got, want)
+			}
+			if got := e.ShannonEntropy(); got < tt.min-1e-9 || got > tt.max+1e-9 || math.IsNaN(got) {
+				t.Fatalf("ShannonEntropy: got %f; want in [%f, %f]", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestEntropyDigestReset(t *testing.T) {
+	e := NewEntropyDigest()
+	e.Write([]byte("some varied input"))
+	e.Reset()
+	e.Write(make([]byte, 10))
+	if got := e.ShannonEntropy(); got != 0 {
+		t.Fatalf("after Reset: got entropy %f; want 0", got)
+	}
+	if got, want := e.Sum64(), Sum64(make([]byte, 10)); got != want {
+		t.Fatalf("after Reset: got 0x%x; want 0x%x", got, want)
+	}
+}
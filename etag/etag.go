@@ -0,0 +1,190 @@
+// Package etag computes strong HTTP entity tags (ETags) from content with
+// XXH64, for cheap validators on static artifacts and other content served
+// by net/http handlers.
+//
+// An ETag produced by this package is the XXH64 digest of the content as 16
+// lower-case hexadecimal digits in double quotes, such as
+// "44bc2cf5ad770999". Since it depends only on the bytes served, it is a
+// strong validator (RFC 9110, section 8.8.1) and is the same on every
+// server serving the same content.
+package etag
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Format returns the quoted ETag for the digest sum.
+func Format(sum uint64) string {
+	b := make([]byte, 0, 18)
+	b = append(b, '"')
+	b = appendHex(b, sum)
+	return string(append(b, '"'))
+}
+
+func appendHex(b []byte, v uint64) []byte {
+	const digits = "0123456789abcdef"
+	for shift := 60; shift >= 0; shift -= 4 {
+		b = append(b, digits[v>>uint(shift)&0xf])
+	}
+	return b
+}
+
+// Parse parses an ETag in the form produced by Format, or its weak form
+// with a W/ prefix, and returns the digest and whether the tag was weak.
+func Parse(s string) (sum uint64, weak bool, err error) {
+	if strings.HasPrefix(s, "W/") {
+		weak = true
+		s = s[2:]
+	}
+	if len(s) != 18 || s[0] != '"' || s[17] != '"' {
+		return 0, false, errors.New("etag: invalid ETag")
+	}
+	sum, err = strconv.ParseUint(s[1:17], 16, 64)
+	if err != nil {
+		return 0, false, errors.New("etag: invalid ETag")
+	}
+	return sum, weak, nil
+}
+
+// FromReader reads r until EOF and returns the ETag of its contents.
+func FromReader(r io.Reader) (string, error) {
+	sum, _, err := xxhash.Sum64Reader(r)
+	if err != nil {
+		return "", err
+	}
+	return Format(sum), nil
+}
+
+// Match reports whether the value of an If-None-Match (or If-Match) header
+// matches etag, using the weak comparison that RFC 9110 specifies for
+// If-None-Match: a header of "*" matches any tag, and otherwise the header
+// matches if any tag in its comma-separated list has the same opaque
+// string as etag, ignoring W/ prefixes. A handler that has computed the
+// ETag of a response can use it to answer with 304 Not Modified before
+// producing the body.
+func Match(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for header != "" {
+		header = strings.TrimLeft(header, " \t,")
+		header = strings.TrimPrefix(header, "W/")
+		if !strings.HasPrefix(header, `"`) {
+			return false
+		}
+		end := strings.IndexByte(header[1:], '"')
+		if end < 0 {
+			return false
+		}
+		if header[:end+2] == etag {
+			return true
+		}
+		header = header[end+2:]
+	}
+	return false
+}
+
+// A Cache remembers the ETags of files, keyed by path and revalidated by
+// size and modification time, so that each file is hashed only when it
+// changes. It is meant for a bounded set of files, such as the static
+// artifacts of a server: entries are replaced when files change but never
+// evicted. The zero Cache is ready to use, and a Cache is safe for
+// concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+// File returns the ETag of the file at path.
+func (c *Cache) File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return c.file(path, f, fi)
+}
+
+// file returns the ETag of the open file f at path, whose FileInfo is fi,
+// leaving f's offset unspecified.
+func (c *Cache) file(path string, f *os.File, fi os.FileInfo) (string, error) {
+	c.mu.Lock()
+	e, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && e.size == fi.Size() && e.modTime.Equal(fi.ModTime()) {
+		return e.etag, nil
+	}
+	tag, err := FromReader(f)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]cacheEntry)
+	}
+	c.entries[path] = cacheEntry{size: fi.Size(), modTime: fi.ModTime(), etag: tag}
+	c.mu.Unlock()
+	return tag, nil
+}
+
+// ServeFile replies to the request with the contents of the regular file at
+// path, like http.ServeFile, with an ETag header from c. Conditional
+// requests (If-None-Match, If-Match, and so on) and range requests are
+// handled by http.ServeContent. It replies 404 if the file does not exist or
+// is a directory, and 500 if it cannot be read.
+func (c *Cache) ServeFile(w http.ResponseWriter, r *http.Request, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		serveError(w, err)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		serveError(w, err)
+		return
+	}
+	if fi.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	tag, err := c.file(path, f, fi)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		serveError(w, err)
+		return
+	}
+	w.Header().Set("Etag", tag)
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+}
+
+func serveError(w http.ResponseWriter, err error) {
+	if os.IsNotExist(err) {
+		http.Error(w, "404 page not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+}
@@ -0,0 +1,147 @@
+package etag
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func TestFormatParse(t *testing.T) {
+	if got, want := Format(xxhash.Sum64String("abc")), `"44bc2cf5ad770999"`; got != want {
+		t.Errorf("Format = %s; want %s", got, want)
+	}
+	for _, sum := range []uint64{0, 1, 0x44bc2cf5ad770999, 1<<64 - 1} {
+		for _, weak := range []bool{false, true} {
+			s := Format(sum)
+			if weak {
+				s = "W/" + s
+			}
+			gotSum, gotWeak, err := Parse(s)
+			if err != nil || gotSum != sum || gotWeak != weak {
+				t.Errorf("Parse(%s) = (%x, %t, %v); want (%x, %t, nil)", s, gotSum, gotWeak, err, sum, weak)
+			}
+		}
+	}
+	for _, s := range []string{"", `""`, "44bc2cf5ad770999", `"44bc2cf5ad77099"`, `"44bc2cf5ad77099x"`, `"+4bc2cf5ad770999"`, `w/"44bc2cf5ad770999"`} {
+		if _, _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%s): got nil error", s)
+		}
+	}
+}
+
+func TestFromReader(t *testing.T) {
+	s := strings.Repeat("artifact ", 10000)
+	tag, err := FromReader(strings.NewReader(s))
+	if err != nil || tag != Format(xxhash.Sum64String(s)) {
+		t.Errorf("got (%s, %v); want (%s, nil)", tag, err, Format(xxhash.Sum64String(s)))
+	}
+}
+
+func TestMatch(t *testing.T) {
+	const tag = `"44bc2cf5ad770999"`
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"*", true},
+		{tag, true},
+		{"W/" + tag, true},
+		{`"0000000000000000", ` + tag, true},
+		{`"0000000000000000",W/` + tag, true},
+		{`"0000000000000000"`, false},
+		{`"44bc2cf5ad770999`, false},
+		{`44bc2cf5ad770999`, false},
+	} {
+		if got := Match(tt.header, tag); got != tt.want {
+			t.Errorf("Match(%q) = %t; want %t", tt.header, got, tt.want)
+		}
+	}
+	if !Match(tag, "W/"+tag) {
+		t.Error("weak etag does not match")
+	}
+}
+
+func writeFile(t *testing.T, path, data string, mtime time.Time) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a")
+	mtime := time.Unix(1e9, 0)
+	writeFile(t, path, "abc", mtime)
+
+	var c Cache
+	tag, err := c.File(path)
+	if err != nil || tag != Format(xxhash.Sum64String("abc")) {
+		t.Fatalf("got (%s, %v)", tag, err)
+	}
+	// With the same size and modification time, the cached tag is used.
+	writeFile(t, path, "xyz", mtime)
+	if got, _ := c.File(path); got != tag {
+		t.Errorf("got %s; want cached %s", got, tag)
+	}
+	writeFile(t, path, "xyz", mtime.Add(time.Second))
+	if got, _ := c.File(path); got != Format(xxhash.Sum64String("xyz")) {
+		t.Errorf("after modification: got %s", got)
+	}
+	if _, err := c.File(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing file: got nil error")
+	}
+}
+
+func TestServeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.txt")
+	writeFile(t, path, "hello, etag", time.Unix(1e9, 0))
+	tag := Format(xxhash.Sum64String("hello, etag"))
+
+	var c Cache
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/a.txt", nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		c.ServeFile(w, req, path)
+		return w
+	}
+
+	w := serve(path, nil)
+	if w.Code != 200 || w.Body.String() != "hello, etag" || w.Header().Get("Etag") != tag {
+		t.Errorf("GET: got %d %q with ETag %s", w.Code, w.Body, w.Header().Get("Etag"))
+	}
+	if w := serve(path, http.Header{"If-None-Match": {tag}}); w.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got %d; want 304", w.Code)
+	}
+	if w := serve(path, http.Header{"Range": {"bytes=7-"}}); w.Code != http.StatusPartialContent || w.Body.String() != "etag" {
+		t.Errorf("Range: got %d %q", w.Code, w.Body)
+	}
+	for _, p := range []string{filepath.Join(dir, "missing"), dir} {
+		if w := serve(p, nil); w.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d; want 404", p, w.Code)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// Sum64Fields computes a fingerprint of a set of key/value fields, ignoring
+// any field whose key is in exclude. Passing volatile fields such as
+// timestamps or request IDs in exclude makes records that differ only in those
+// fields hash identically. The order in which the map is iterated does not
+// affect the result.
+//
+// The result is the XXH64 digest of the included fields, sorted by key in
+// byte order, with each field written as:
+//
+//	len(key)   as an 8-byte little-endian integer
+//	key
+//	len(value) as an 8-byte little-endian integer
+//	value
+//
+// This framing is unambiguous, and other implementations can reproduce it
+// exactly.
+func Sum64Fields(fields map[string]string, exclude map[string]struct{}) uint64 {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if _, ok := exclude[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	d := New()
+	for _, k := range keys {
+		writeLenPrefixed(d, k)
+		writeLenPrefixed(d, fields[k])
+	}
+	return d.Sum64()
+}
+
+// writeLenPrefixed writes s to d preceded by its length as an 8-byte
+// little-endian integer.
+func writeLenPrefixed(d *Digest, s string) {
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(s)))
+	d.Write(n[:])
+	d.WriteString(s)
+}
+
+// Sum64Varint computes the XXH64 digest of items, each preceded by its length
+// encoded as a protocol buffers style unsigned varint: seven bits per byte,
+// least significant group first, with the high bit of each byte set if more
+// bytes follow (as written by encoding/binary.PutUvarint).
+//
+// This framing is unambiguous and, for small items, much more compact than
+// the 8-byte length prefixes used by Sum64Fields.
+func Sum64Varint(items [][]byte) uint64 {
+	var d Digest
+	d.Reset()
+	var n [binary.MaxVarintLen64]byte
+	for _, item := range items {
+		d.Write(n[:binary.PutUvarint(n[:], uint64(len(item)))])
+		d.Write(item)
+	}
+	return d.Sum64()
+}
@@ -0,0 +1,99 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestSum64Fields(t *testing.T) {
+	exclude := map[string]struct{}{"ts": {}, "request_id": {}}
+	r0 := map[string]string{"msg": "disk full", "host": "a", "ts": "12:00:00", "request_id": "r1"}
+	r1 := map[string]string{"msg": "disk full", "host": "a", "ts": "12:00:05", "request_id": "r2"}
+	r2 := map[string]string{"msg": "disk full", "host": "b", "ts": "12:00:00", "request_id": "r1"}
+	r3 := map[string]string{"msg": "disk full", "host": "a"}
+
+	h0 := Sum64Fields(r0, exclude)
+	if h1 := Sum64Fields(r1, exclude); h0 != h1 {
+		t.Errorf("records differing only in excluded fields: 0x%x != 0x%x", h0, h1)
+	}
+	if h3 := Sum64Fields(r3, exclude); h0 != h3 {
+		t.Errorf("record without excluded fields: 0x%x != 0x%x", h0, h3)
+	}
+	if h2 := Sum64Fields(r2, exclude); h0 == h2 {
+		t.Errorf("records differing in an included field both hashed to 0x%x", h0)
+	}
+	if Sum64Fields(r0, nil) == Sum64Fields(r1, nil) {
+		t.Error("records differing in unexcluded fields hashed the same")
+	}
+}
+
+func TestSum64FieldsFraming(t *testing.T) {
+	fields := map[string]string{"b": "2", "a": "1"}
+	var want []byte
+	for _, s := range []string{"a", "1", "b", "2"} {
+		var n [8]byte
+		binary.LittleEndian.PutUint64(n[:], uint64(len(s)))
+		want = append(want, n[:]...)
+		want = append(want, s...)
+	}
+	if got := Sum64Fields(fields, nil); got != Sum64(want) {
+		t.Fatalf("got 0x%x; want 0x%x", got, Sum64(want))
+	}
+
+	// Moving bytes between a key and its value must change the hash.
+	if Sum64Fields(map[string]string{"ab": "c"}, nil) == Sum64Fields(map[string]string{"a": "bc"}, nil) {
+		t.Fatal("ambiguous framing")
+	}
+}
+
+func TestSum64Varint(t *testing.T) {
+	items := [][]byte{
+		nil,
+		[]byte("a"),
+		bytes.Repeat([]byte("b"), 127),
+		bytes.Repeat([]byte("c"), 128),
+		bytes.Repeat([]byte("d"), 300),
+	}
+	// Reference framing, built by hand: 300 = 0b10_0101100 encodes as
+	// 0xac 0x02.
+	var want []byte
+	want = append(want, 0x00)
+	want = append(want, 0x01, 'a')
+	want = append(want, 0x7f)
+	want = append(want, items[2]...)
+	want = append(want, 0x80, 0x01)
+	want = append(want, items[3]...)
+	want = append(want, 0xac, 0x02)
+	want = append(want, items[4]...)
+	if got := Sum64Varint(items); got != Sum64(want) {
+		t.Fatalf("got 0x%x; want 0x%x", got, Sum64(want))
+	}
+}
+
+func TestSum64VarintDistinct(t *testing.T) {
+	seen := make(map[uint64][]string)
+	for _, split := range [][]string{
+		{},
+		{""},
+		{"", ""},
+		{"abc"},
+		{"ab", "c"},
+		{"a", "bc"},
+		{"a", "b", "c"},
+		{"", "abc"},
+		{"abc", ""},
+		{"\x01a"},
+		{"a", ""},
+	} {
+		items := make([][]byte, len(split))
+		for i, s := range split {
+			items[i] = []byte(s)
+		}
+		h := Sum64Varint(items)
+		if prev, ok := seen[h]; ok {
+			t.Errorf("%q and %q both hash to 0x%x", prev, split, h)
+		}
+		seen[h] = split
+	}
+}
@@ -0,0 +1,98 @@
+package xxhash
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// SumFold64 computes the 64-bit xxHash digest of s with the ASCII letters
+// A-Z mapped to a-z, without allocating, for tables of HTTP header names,
+// hostnames, and other keys that are compared ASCII case-insensitively. The
+// result is the same as Sum64String of the lowered string, and other bytes,
+// including non-ASCII UTF-8, are hashed unchanged.
+func SumFold64(s string) uint64 {
+	i := 0
+	for i < len(s) && !isASCIIUpper(s[i]) {
+		i++
+	}
+	if i == len(s) {
+		return Sum64String(s)
+	}
+	var d Digest
+	d.Reset()
+	d.WriteString(s[:i])
+	var buf [64]byte
+	for s = s[i:]; len(s) > 0; {
+		n := copy(buf[:], s)
+		for j, c := range buf[:n] {
+			if isASCIIUpper(c) {
+				buf[j] = c + 'a' - 'A'
+			}
+		}
+		d.Write(buf[:n])
+		s = s[n:]
+	}
+	return d.Sum64()
+}
+
+// SumEqualFold64 computes a 64-bit hash of s that is equal for strings equal
+// under strings.EqualFold, that is, under Unicode simple case folding. It
+// does not allocate.
+//
+// The result is the XXH64 digest of the UTF-8 encoding of s with each rune
+// replaced by the smallest rune it folds to (its fold orbit under
+// unicode.SimpleFold), or by the lower-case letter for the ASCII letters, and
+// with each invalid byte replaced by utf8.RuneError. For ASCII strings, it
+// equals SumFold64.
+func SumEqualFold64(s string) uint64 {
+	i := 0
+	for i < len(s) && s[i] < utf8.RuneSelf && !isASCIIUpper(s[i]) {
+		i++
+	}
+	if i == len(s) {
+		return Sum64String(s)
+	}
+	var d Digest
+	d.Reset()
+	d.WriteString(s[:i])
+	var buf [64]byte
+	n := 0
+	for _, r := range s[i:] {
+		if n > len(buf)-utf8.UTFMax {
+			d.Write(buf[:n])
+			n = 0
+		}
+		r = foldRune(r)
+		if r < utf8.RuneSelf {
+			buf[n] = byte(r)
+			n++
+		} else {
+			n += utf8.EncodeRune(buf[n:], r)
+		}
+	}
+	d.Write(buf[:n])
+	return d.Sum64()
+}
+
+// foldRune returns the canonical member of r's simple case folding orbit:
+// the smallest, or for the ASCII letters, the lower-case one.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		return r
+	}
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < m {
+			m = f
+		}
+	}
+	if 'A' <= m && m <= 'Z' {
+		m += 'a' - 'A'
+	}
+	return m
+}
+
+func isASCIIUpper(c byte) bool { return 'A' <= c && c <= 'Z' }
@@ -0,0 +1,80 @@
+package xxhash
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func TestSumFold64(t *testing.T) {
+	long := strings.Repeat("X-Forwarded-For ", 20)
+	for _, s := range []string{"", "a", "A", "content-type", "Content-Type", "CONTENT-TYPE", "Éé", "ß", long} {
+		if got, want := SumFold64(s), Sum64String(asciiLower(s)); got != want {
+			t.Errorf("SumFold64(%q) = 0x%x; want 0x%x", s, got, want)
+		}
+	}
+	if SumFold64("Host") != SumFold64("hOST") {
+		t.Error("SumFold64 is case-sensitive")
+	}
+	if SumFold64("É") == SumFold64("é") {
+		t.Error("SumFold64 folds non-ASCII letters")
+	}
+	testAllocs(t, func() {
+		sink = SumFold64("Content-Type") + SumFold64(long)
+	})
+}
+
+func TestSumEqualFold64(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"Content-Type", "content-TYPE"},
+		{"Éclair", "éCLAIR"},
+		{"k", "\u212a"}, // Kelvin sign
+		{"\u017f", "S"}, // long s
+		{"ΣΑΣ", "σας"},
+		{"\xff", "�"},
+		{strings.Repeat("Ǆ", 100), strings.Repeat("ǅ", 100)},
+	} {
+		if !strings.EqualFold(tt.a, tt.b) {
+			t.Fatalf("bad test: %q and %q are not EqualFold", tt.a, tt.b)
+		}
+		if got, want := SumEqualFold64(tt.a), SumEqualFold64(tt.b); got != want {
+			t.Errorf("SumEqualFold64(%q) = 0x%x, SumEqualFold64(%q) = 0x%x", tt.a, got, tt.b, want)
+		}
+	}
+	for _, s := range []string{"", "abc", "Content-Type", strings.Repeat("HeLLo", 30)} {
+		if got, want := SumEqualFold64(s), SumFold64(s); got != want {
+			t.Errorf("ASCII %q: SumEqualFold64 = 0x%x; want SumFold64 = 0x%x", s, got, want)
+		}
+	}
+	if SumEqualFold64("ß") == SumEqualFold64("ss") {
+		t.Error("SumEqualFold64 applies full case folding")
+	}
+	testAllocs(t, func() {
+		sink = SumEqualFold64("Éclair Kelvin \u212a")
+	})
+}
+
+func TestFoldRune(t *testing.T) {
+	// foldRune is constant on every orbit of unicode.SimpleFold.
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		c := foldRune(r)
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if foldRune(f) != c {
+				t.Fatalf("foldRune(%U) = %U but foldRune(%U) = %U", r, c, f, foldRune(f))
+			}
+		}
+	}
+}
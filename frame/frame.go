@@ -0,0 +1,191 @@
+// Package frame implements a framed record format for append-only logs,
+// such as write-ahead logs, in which each record's payload is protected by
+// an xxhash trailer.
+//
+// Each record is a 4-byte big-endian payload length, the payload, and a
+// trailer of either 8 or 4 bytes: the XXH64 digest of the payload, or its
+// low 32 bits, stored in big-endian order. With 8-byte trailers, the format
+// is the one read by xxhash.NewLengthPrefixedRecordReader. The trailer
+// covers only the payload; a corrupted length is caught because the bytes it
+// frames then fail verification.
+//
+// A crash while appending can leave an incomplete or garbled record at the
+// end of a log. The Reader treats such a torn final record as the end of the
+// log and reports where the valid records end, so that the log can be
+// truncated there before appending resumes.
+package frame
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// A Writer writes framed records to an underlying io.Writer.
+type Writer struct {
+	w       io.Writer
+	trailer int
+	buf     []byte
+}
+
+// NewWriter returns a Writer that writes records to w with trailers of
+// trailerSize bytes. It panics unless trailerSize is 4 or 8.
+func NewWriter(w io.Writer, trailerSize int) *Writer {
+	checkTrailerSize(trailerSize)
+	return &Writer{w: w, trailer: trailerSize}
+}
+
+// WriteRecord writes a record with the given payload. The whole record is
+// written with a single Write call on the underlying writer, so that, for
+// example, records appended to a file opened with O_APPEND are not
+// interleaved. It returns an error if the payload is longer than 2^32-1
+// bytes or if writing fails.
+func (w *Writer) WriteRecord(payload []byte) error {
+	if uint64(len(payload)) > math.MaxUint32 {
+		return errors.New("frame: record payload too long")
+	}
+	b := w.buf[:0]
+	b = append(b, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b, uint32(len(payload)))
+	b = append(b, payload...)
+	b = appendTrailer(b, xxhash.Sum64(payload), w.trailer)
+	w.buf = b
+	_, err := w.w.Write(b)
+	return err
+}
+
+// A ChecksumError reports a record, other than a torn final record, whose
+// payload does not match its trailer.
+type ChecksumError struct {
+	Offset int64 // offset of the record in the stream
+	xxhash.MismatchError
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("frame: record at offset %d: checksum mismatch: got %x, want %x", e.Offset, e.Got, e.Want)
+}
+
+// Unwrap returns the underlying *xxhash.MismatchError.
+func (e *ChecksumError) Unwrap() error { return &e.MismatchError }
+
+// A Reader reads and verifies framed records.
+type Reader struct {
+	r       *bufio.Reader
+	trailer int
+	max     int
+	buf     []byte
+	off     int64 // offset just past the last valid record
+	torn    bool
+	err     error
+}
+
+// NewReader returns a Reader that reads records with trailers of trailerSize
+// bytes from r. Records whose declared payload length is greater than
+// maxPayload are rejected with an error rather than allocated. It panics
+// unless trailerSize is 4 or 8.
+func NewReader(r io.Reader, trailerSize, maxPayload int) *Reader {
+	checkTrailerSize(trailerSize)
+	return &Reader{r: bufio.NewReader(r), trailer: trailerSize, max: maxPayload}
+}
+
+// Next reads and verifies the next record and returns its payload. The
+// returned slice is only valid until the next call to Next.
+//
+// At the end of the stream Next returns io.EOF. A final record that is
+// incomplete, or that fails verification and is followed by the end of the
+// stream, is torn: Next discards it, returns io.EOF, and Torn reports true.
+// Any other record that fails verification makes Next return a
+// *ChecksumError. Once Next has returned an error, all later calls return
+// the same error.
+func (r *Reader) Next() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	p, err := r.next()
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *Reader) next() ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		return nil, r.endOrTorn(err)
+	}
+	size := binary.BigEndian.Uint32(hdr[:])
+	if uint64(size) > uint64(r.max) {
+		return nil, fmt.Errorf("frame: record at offset %d: payload length %d exceeds maximum %d", r.off, size, r.max)
+	}
+	n := int(size) + r.trailer
+	if cap(r.buf) < n {
+		r.buf = make([]byte, n)
+	}
+	b := r.buf[:n]
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		if err == io.EOF {
+			// We already consumed this record's length prefix.
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, r.endOrTorn(err)
+	}
+	payload := b[:size]
+	got, want := xxhash.Sum64(payload), trailerValue(b[size:])
+	if r.trailer == 4 {
+		got &= math.MaxUint32
+	}
+	if got != want {
+		if _, err := r.r.Peek(1); err == io.EOF {
+			return nil, r.endOrTorn(io.ErrUnexpectedEOF)
+		}
+		return nil, &ChecksumError{Offset: r.off, MismatchError: xxhash.MismatchError{Got: got, Want: want}}
+	}
+	r.off += int64(len(hdr) + n)
+	return payload, nil
+}
+
+// endOrTorn converts the error from reading a record into Next's result: a
+// clean io.EOF stays io.EOF, io.ErrUnexpectedEOF marks the record as torn,
+// and other errors are returned as they are.
+func (r *Reader) endOrTorn(err error) error {
+	if err == io.ErrUnexpectedEOF {
+		r.torn = true
+		return io.EOF
+	}
+	return err
+}
+
+// Torn reports whether Next discarded a torn final record.
+func (r *Reader) Torn() bool { return r.torn }
+
+// Offset returns the offset in the stream just past the last record that
+// Next returned. After Next returns io.EOF, it is the length of the valid
+// part of the log, at which a log with a torn final record can be truncated.
+func (r *Reader) Offset() int64 { return r.off }
+
+func checkTrailerSize(n int) {
+	if n != 4 && n != 8 {
+		panic("frame: trailer size must be 4 or 8")
+	}
+}
+
+func appendTrailer(b []byte, sum uint64, size int) []byte {
+	if size == 4 {
+		return append(b, byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+	}
+	return append(b, byte(sum>>56), byte(sum>>48), byte(sum>>40), byte(sum>>32),
+		byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+}
+
+func trailerValue(b []byte) uint64 {
+	if len(b) == 4 {
+		return uint64(binary.BigEndian.Uint32(b))
+	}
+	return binary.BigEndian.Uint64(b)
+}
@@ -0,0 +1,181 @@
+package frame
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+var testPayloads = [][]byte{[]byte("first"), nil, []byte("third record"), bytes.Repeat([]byte{'x'}, 5000)}
+
+func writeLog(t *testing.T, trailer int, payloads [][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf, trailer)
+	for _, p := range payloads {
+		if err := w.WriteRecord(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// readLog reads all records of log, returning them and the final error.
+func readLog(log []byte, trailer int) (*Reader, []string, error) {
+	r := NewReader(bytes.NewReader(log), trailer, 1<<20)
+	var got []string
+	for {
+		p, err := r.Next()
+		if err != nil {
+			return r, got, err
+		}
+		got = append(got, string(p))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, trailer := range []int{4, 8} {
+		log := writeLog(t, trailer, testPayloads)
+		r, got, err := readLog(log, trailer)
+		if err != io.EOF {
+			t.Fatalf("trailer %d: got error %v; want io.EOF", trailer, err)
+		}
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", testPayloads) {
+			t.Errorf("trailer %d: got records %q; want %q", trailer, got, testPayloads)
+		}
+		if r.Torn() || r.Offset() != int64(len(log)) {
+			t.Errorf("trailer %d: Torn = %t, Offset = %d; want false, %d", trailer, r.Torn(), r.Offset(), len(log))
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	sum := xxhash.Sum64String("abc")
+	d := xxhash.New()
+	d.WriteString("abc")
+	want8 := append([]byte{0, 0, 0, 3, 'a', 'b', 'c'}, d.Sum(nil)...)
+	if got := writeLog(t, 8, [][]byte{[]byte("abc")}); !bytes.Equal(got, want8) {
+		t.Errorf("8-byte trailer: got %x; want %x", got, want8)
+	}
+	want4 := []byte{0, 0, 0, 3, 'a', 'b', 'c', byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)}
+	if got := writeLog(t, 4, [][]byte{[]byte("abc")}); !bytes.Equal(got, want4) {
+		t.Errorf("4-byte trailer: got %x; want %x", got, want4)
+	}
+
+	// Logs with 8-byte trailers can be read by the xxhash package.
+	rr := xxhash.NewLengthPrefixedRecordReader(bytes.NewReader(writeLog(t, 8, testPayloads)), 1<<20)
+	for i, want := range testPayloads {
+		if got, err := rr.Next(); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("record %d: got (%q, %v); want (%q, nil)", i, got, err, want)
+		}
+	}
+}
+
+// countingWriter counts the calls to Write.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestWriterSingleWrite(t *testing.T) {
+	var cw countingWriter
+	w := NewWriter(&cw, 8)
+	for _, p := range testPayloads {
+		w.WriteRecord(p)
+	}
+	if cw.writes != len(testPayloads) {
+		t.Errorf("got %d writes for %d records", cw.writes, len(testPayloads))
+	}
+}
+
+func TestTorn(t *testing.T) {
+	for _, trailer := range []int{4, 8} {
+		log := writeLog(t, trailer, testPayloads)
+		valid := len(writeLog(t, trailer, testPayloads[:3]))
+		want := fmt.Sprintf("%q", testPayloads[:3])
+
+		// Every truncation inside the final record is torn.
+		for n := valid + 1; n < len(log); n += 97 {
+			r, got, err := readLog(log[:n], trailer)
+			if err != io.EOF || fmt.Sprintf("%q", got) != want || !r.Torn() || r.Offset() != int64(valid) {
+				t.Fatalf("trailer %d, truncated to %d: got (%q, %v, torn %t, offset %d); want (%s, EOF, torn, %d)",
+					trailer, n, got, err, r.Torn(), r.Offset(), want, valid)
+			}
+		}
+
+		// So is a complete final record with a garbled payload, and a final
+		// record zeroed by a crash.
+		garbled := append([]byte(nil), log...)
+		garbled[len(garbled)-trailer-1] ^= 1
+		zeroed := append(append([]byte(nil), log[:valid]...), make([]byte, 4+trailer)...)
+		for _, b := range [][]byte{garbled, zeroed} {
+			r, got, err := readLog(b, trailer)
+			if err != io.EOF || fmt.Sprintf("%q", got) != want || !r.Torn() || r.Offset() != int64(valid) {
+				t.Errorf("trailer %d: got (%q, %v, torn %t, offset %d); want (%s, EOF, torn, %d)",
+					trailer, got, err, r.Torn(), r.Offset(), want, valid)
+			}
+		}
+	}
+}
+
+func TestChecksumError(t *testing.T) {
+	log := writeLog(t, 8, testPayloads)
+	log[4] ^= 1 // corrupt the first payload
+	r, got, err := readLog(log, 8)
+	cerr, ok := err.(*ChecksumError)
+	if !ok || cerr.Offset != 0 || len(got) != 0 || r.Torn() {
+		t.Fatalf("got (%q, %v, torn %t); want a *ChecksumError at offset 0", got, err, r.Torn())
+	}
+	if cerr.Want != xxhash.Sum64String("first") || cerr.Got != xxhash.Sum64(log[4:9]) {
+		t.Errorf("got %+v", cerr)
+	}
+	if _, err2 := r.Next(); err2 != err {
+		t.Errorf("error is not sticky: got %v, then %v", err, err2)
+	}
+
+	log = writeLog(t, 4, testPayloads)
+	third := len(writeLog(t, 4, testPayloads[:2]))
+	log[third+5] ^= 1
+	_, got, err = readLog(log, 4)
+	if cerr, ok := err.(*ChecksumError); !ok || cerr.Offset != int64(third) || len(got) != 2 || cerr.Got > 0xffffffff {
+		t.Fatalf("got (%q, %v); want 2 records and a *ChecksumError at offset %d", got, err, third)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	log := writeLog(t, 8, [][]byte{make([]byte, 100)})
+	if _, err := NewReader(bytes.NewReader(log), 8, 99).Next(); err == nil || err == io.EOF {
+		t.Errorf("oversized record: got %v", err)
+	}
+	errRead := errors.New("read failed")
+	r := NewReader(io.MultiReader(bytes.NewReader(log[:10]), errorReader{errRead}), 8, 1000)
+	if _, err := r.Next(); err != errRead {
+		t.Errorf("read error: got %v; want %v", err, errRead)
+	}
+	for _, fn := range []func(){
+		func() { NewWriter(nil, 2) },
+		func() { NewReader(nil, 16, 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+type errorReader struct{ err error }
+
+func (r errorReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,97 @@
+//go:build go1.16
+// +build go1.16
+
+package xxhash
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// An FSEntry describes one file in the tree hashed by ManifestFS.
+type FSEntry struct {
+	Path string // slash-separated path relative to the root of the tree
+	Size int64
+	Sum  uint64 // XXH64 digest of the file's contents
+}
+
+// SumFS computes a digest of the files in fsys, so that two trees can be
+// compared by a single value. The digest depends only on the paths and
+// contents of the files, not on their modification times or permissions, and
+// it is defined as follows so that other implementations can reproduce it:
+//
+// The tree is walked in lexical order, as by fs.WalkDir. Each file other
+// than a directory is opened (following symbolic links, if fsys does) and
+// must be a regular file. For each file, the length of its path, the path,
+// its size, and the XXH64 digest (seed 0) of its contents are appended to a
+// record, with the integers encoded as little-endian uint64s. The result is
+// the XXH64 digest (seed 0) of the record. Directories contribute only
+// through the paths of the files they contain, so empty directories do not
+// affect the result.
+func SumFS(fsys fs.FS) (uint64, error) {
+	var d Digest
+	err := walkFS(fsys, func(e FSEntry) { writeFSEntry(&d, e) })
+	if err != nil {
+		return 0, err
+	}
+	return d.Sum64(), nil
+}
+
+// ManifestFS is like SumFS, but it also returns an entry for each file that
+// contributed to the digest, in walk order.
+func ManifestFS(fsys fs.FS) ([]FSEntry, uint64, error) {
+	var d Digest
+	var entries []FSEntry
+	err := walkFS(fsys, func(e FSEntry) {
+		writeFSEntry(&d, e)
+		entries = append(entries, e)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, d.Sum64(), nil
+}
+
+func writeFSEntry(d *Digest, e FSEntry) {
+	d.WriteUint64(uint64(len(e.Path)))
+	d.WriteString(e.Path)
+	d.WriteUint64(uint64(e.Size))
+	d.WriteUint64(e.Sum)
+}
+
+func walkFS(fsys fs.FS, fn func(FSEntry)) error {
+	return fs.WalkDir(fsys, ".", func(path string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if de.IsDir() {
+			return nil
+		}
+		e, err := sumFSFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		fn(e)
+		return nil
+	})
+}
+
+func sumFSFile(fsys fs.FS, path string) (FSEntry, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return FSEntry{}, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return FSEntry{}, err
+	}
+	if !fi.Mode().IsRegular() {
+		return FSEntry{}, fmt.Errorf("xxhash: %s is not a regular file", path)
+	}
+	sum, n, err := Sum64Reader(f)
+	if err != nil {
+		return FSEntry{}, err
+	}
+	return FSEntry{Path: path, Size: n, Sum: sum}, nil
+}
@@ -0,0 +1,80 @@
+//go:build go1.16
+// +build go1.16
+
+package xxhash
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSumFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":       {Data: []byte("hello")},
+		"dir/b.txt":   {Data: []byte("world")},
+		"dir/c/d.bin": {Data: make([]byte, 1000)},
+		"empty":       {Mode: fs.ModeDir},
+	}
+	entries, sum, err := ManifestFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEntries := []FSEntry{
+		{"a.txt", 5, Sum64String("hello")},
+		{"dir/b.txt", 5, Sum64String("world")},
+		{"dir/c/d.bin", 1000, Sum64(make([]byte, 1000))},
+	}
+	if !reflect.DeepEqual(entries, wantEntries) {
+		t.Fatalf("got entries %v; want %v", entries, wantEntries)
+	}
+
+	// A direct transcription of the definition.
+	var rec []byte
+	for _, e := range wantEntries {
+		rec = appendUint64(rec, uint64(len(e.Path)))
+		rec = append(rec, e.Path...)
+		rec = appendUint64(rec, uint64(e.Size))
+		rec = appendUint64(rec, e.Sum)
+	}
+	if want := Sum64(rec); sum != want {
+		t.Fatalf("ManifestFS: got 0x%x; want 0x%x", sum, want)
+	}
+	if got, err := SumFS(fsys); err != nil || got != sum {
+		t.Fatalf("SumFS: got (0x%x, %v); want (0x%x, nil)", got, err, sum)
+	}
+
+	// Moving content between files changes the digest.
+	moved := fstest.MapFS{
+		"a.txt":     {Data: []byte("hellow")},
+		"dir/b.txt": {Data: []byte("orld")},
+	}
+	orig := fstest.MapFS{
+		"a.txt":     {Data: []byte("hello")},
+		"dir/b.txt": {Data: []byte("world")},
+	}
+	s1, _ := SumFS(moved)
+	s2, _ := SumFS(orig)
+	if s1 == s2 {
+		t.Fatal("trees with different files have the same digest")
+	}
+	if s, _ := SumFS(fstest.MapFS{}); s != Sum64(nil) {
+		t.Fatalf("empty tree: got 0x%x; want 0x%x", s, Sum64(nil))
+	}
+}
+
+func TestSumFSErrors(t *testing.T) {
+	if _, err := SumFS(fstest.MapFS{"link": {Mode: fs.ModeSymlink}}); err == nil {
+		t.Error("non-regular file: got nil error")
+	}
+	errOpen := errors.New("open failed")
+	if _, err := SumFS(errFS{errOpen}); err == nil {
+		t.Error("open error: got nil error")
+	}
+}
+
+type errFS struct{ err error }
+
+func (f errFS) Open(name string) (fs.File, error) { return nil, f.err }
@@ -0,0 +1,46 @@
+//go:build go1.18
+// +build go1.18
+
+package xxhash
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Hasher returns a function that computes a seeded 64-bit hash of a K, for
+// use by generic hash tables and sharding libraries. Equal keys (according to
+// ==) have equal hashes.
+//
+// The function is specialized for K when Hasher is called, so hashing does
+// not use reflection, except on the dynamic values of interfaces within K.
+// Strings are hashed as by Sum64StringWithSeed; other keys are hashed by
+// their in-memory representation, so hashes of pointers and channels, as well
+// as hashes on platforms of different byte orders, differ: hashes are only
+// meant to be compared within one process.
+//
+// In builds that avoid unsafe (with the nounsafe or appengine tag, or under
+// TinyGo), keys other than strings are hashed by walking them with reflection
+// instead, which is slower and may allocate.
+//
+// Calling the returned function panics if K holds an interface whose dynamic
+// value is not comparable, just as comparing it would.
+func Hasher[K comparable]() func(key K, seed uint64) uint64 {
+	return newHasher[K]()
+}
+
+// Sum64Comparable computes a seeded 64-bit hash of v that is consistent with
+// ==, as described for Hasher. It is equivalent to Hasher[T]()(v, seed), but
+// it specializes for T only once per process. Like Hasher's functions, it
+// panics if v holds an interface whose dynamic value is not comparable.
+func Sum64Comparable[T comparable](seed uint64, v T) uint64 {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	h, ok := hashers.Load(t)
+	if !ok {
+		h, _ = hashers.LoadOrStore(t, Hasher[T]())
+	}
+	return h.(func(T, uint64) uint64)(v, seed)
+}
+
+// hashers caches the functions created by Sum64Comparable, keyed by type.
+var hashers sync.Map
@@ -0,0 +1,78 @@
+//go:build go1.18 && (appengine || tinygo || nounsafe)
+// +build go1.18
+// +build appengine tinygo nounsafe
+
+package xxhash
+
+import "reflect"
+
+// newHasher returns Hasher[K]() for builds that avoid unsafe, which walks
+// keys with reflection.
+func newHasher[K comparable]() func(key K, seed uint64) uint64 {
+	if reflect.TypeOf((*K)(nil)).Elem().Kind() == reflect.String {
+		return func(k K, seed uint64) uint64 {
+			return Sum64StringWithSeed(reflect.ValueOf(k).String(), seed)
+		}
+	}
+	return func(k K, seed uint64) uint64 {
+		var d Digest
+		d.ResetWithSeed(seed)
+		// Going through &k keeps an interface K an interface Value.
+		hashValue(&d, reflect.ValueOf(&k).Elem())
+		return d.Sum64()
+	}
+}
+
+// hashValue writes the comparable value v to d such that equal values are
+// written identically.
+func hashValue(d *Digest, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			d.WriteUint64(1)
+		} else {
+			d.WriteUint64(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.WriteUint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.WriteUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeObjectFloat(d, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeObjectFloat(d, real(c))
+		writeObjectFloat(d, imag(c))
+	case reflect.String:
+		writeLenPrefixed(d, v.String())
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		d.WriteUint64(uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(d, v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).Name != "_" {
+				hashValue(d, v.Field(i))
+			}
+		}
+	case reflect.Interface:
+		// Writing the dynamic type separates equal-looking values of
+		// different types, such as 0 and "". A nil interface writes an
+		// empty type name.
+		if v.IsNil() {
+			writeLenPrefixed(d, "")
+			return
+		}
+		e := v.Elem()
+		if !e.Type().Comparable() {
+			panic("xxhash: hash of unhashable type " + e.Type().String())
+		}
+		writeLenPrefixed(d, e.Type().String())
+		hashValue(d, e)
+	default:
+		panic("xxhash: hash of unhashable type " + v.Type().String())
+	}
+}
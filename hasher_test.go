@@ -0,0 +1,109 @@
+//go:build go1.18
+// +build go1.18
+
+package xxhash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHasherString(t *testing.T) {
+	h := Hasher[string]()
+	for _, s := range []string{"", "a", "hello, world", string(make([]byte, 100))} {
+		if got, want := h(s, 7), Sum64StringWithSeed(s, 7); got != want {
+			t.Errorf("%q: got 0x%x; want 0x%x", s, got, want)
+		}
+	}
+	type name string
+	if got, want := Hasher[name]()("x", 1), Sum64StringWithSeed("x", 1); got != want {
+		t.Errorf("named string: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+// checkHasher checks that h agrees with == on all pairs of vals.
+func checkHasher[K comparable](t *testing.T, vals []K) {
+	t.Helper()
+	h := Hasher[K]()
+	for i, a := range vals {
+		for j, b := range vals {
+			ha, hb := h(a, 5), h(b, 5)
+			if a == b && ha != hb {
+				t.Errorf("%#v == %#v but hashes differ", a, b)
+			}
+			if a != b && ha == hb && i < j {
+				t.Errorf("%#v != %#v but hashes are equal", a, b)
+			}
+		}
+	}
+}
+
+func TestHasherComposite(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	checkHasher(t, []float64{0, negZero, 1, -1, math.Inf(1)})
+	checkHasher(t, []float32{0, float32(negZero), 1})
+	checkHasher(t, []complex128{0, complex(negZero, 0), complex(0, 1)})
+
+	type padded struct {
+		a byte
+		b int64 // preceded by padding
+		s string
+		f float64
+	}
+	checkHasher(t, []padded{
+		{},
+		{a: 1},
+		{b: 1},
+		{s: "x"},
+		{f: negZero},
+		{a: 1, b: 2, s: "abc", f: 1.5},
+		{a: 1, b: 2, s: "ab", f: 1.5},
+	})
+
+	type pair struct{ a, b string }
+	checkHasher(t, []pair{{"ab", "c"}, {"a", "bc"}, {"", "abc"}, {"abc", ""}})
+	checkHasher(t, [][2]string{{"ab", "c"}, {"a", "bc"}, {"ab", "c"}})
+	checkHasher(t, [][3]int32{{1, 2, 3}, {1, 2, 4}, {}})
+
+	x, y := 1, 1
+	checkHasher(t, []*int{&x, &y, nil})
+}
+
+func TestHasherInterface(t *testing.T) {
+	type key struct {
+		v any
+		n int
+	}
+	checkHasher(t, []key{
+		{nil, 0},
+		{1, 0},
+		{int8(1), 0},
+		{"a", 0},
+		{"a", 1},
+		{[2]string{"a", "b"}, 0},
+		{key{v: 1.5}, 0},
+	})
+	checkHasher(t, []any{nil, 0, "", 0.0, math.Copysign(0, -1), struct{}{}})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic hashing an unhashable dynamic value")
+		}
+	}()
+	Hasher[any]()([]int{1}, 0)
+}
+
+func TestSum64Comparable(t *testing.T) {
+	type key struct {
+		a [2]string
+		v any
+	}
+	for _, k := range []key{{}, {[2]string{"a", "b"}, 1}, {v: "x"}} {
+		if got, want := Sum64Comparable(9, k), Hasher[key]()(k, 9); got != want {
+			t.Errorf("%#v: got 0x%x; want 0x%x", k, got, want)
+		}
+	}
+	if got, want := Sum64Comparable(1, "abc"), Sum64StringWithSeed("abc", 1); got != want {
+		t.Errorf("string: got 0x%x; want 0x%x", got, want)
+	}
+}
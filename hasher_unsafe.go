@@ -0,0 +1,210 @@
+//go:build go1.18 && !appengine && !tinygo && !nounsafe
+// +build go1.18,!appengine,!tinygo,!nounsafe
+
+package xxhash
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// newHasher returns Hasher[K]() specialized by compiling K's layout into
+// hashOps run directly on the key's memory.
+func newHasher[K comparable]() func(key K, seed uint64) uint64 {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		return func(k K, seed uint64) uint64 {
+			return Sum64StringWithSeed(*(*string)(unsafe.Pointer(&k)), seed)
+		}
+	case reflect.Float64:
+		return func(k K, seed uint64) uint64 {
+			f := *(*float64)(unsafe.Pointer(&k))
+			if f == 0 {
+				f = 0 // -0 == 0
+			}
+			return sum64Uint64Seed(math.Float64bits(f), seed)
+		}
+	}
+	if isPlain(t) {
+		if t.Size() == 8 {
+			return func(k K, seed uint64) uint64 {
+				return sum64Uint64Seed(*(*uint64)(unsafe.Pointer(&k)), seed)
+			}
+		}
+		size := t.Size()
+		return func(k K, seed uint64) uint64 {
+			return Sum64WithSeed(unsafe.Slice((*byte)(unsafe.Pointer(&k)), size), seed)
+		}
+	}
+	ops := compileHash(t, 0, nil)
+	return func(k K, seed uint64) uint64 {
+		var d Digest
+		d.ResetWithSeed(seed)
+		hashOps(&d, ops, unsafe.Pointer(&k))
+		return d.Sum64()
+	}
+}
+
+// sum64Uint64Seed is Sum64Uint64 with a seed.
+func sum64Uint64Seed(v, seed uint64) uint64 {
+	h := seed + prime5 + 8
+	h ^= round(0, v)
+	h = rol27(h)*prime1 + prime4
+	return avalanche(h)
+}
+
+// A hashOp writes part of a value to a Digest such that equal values are
+// written identically. A value's hashOps are computed once per type by
+// compileHash and run by hashOps.
+type hashOp struct {
+	kind   hashOpKind
+	offset uintptr
+	size   uintptr      // opBytes: the number of bytes; opArray: the element size
+	n      int          // opArray: the number of elements
+	elem   []hashOp     // opArray: the element's ops
+	typ    reflect.Type // opInterface: the interface type
+}
+
+type hashOpKind uint8
+
+const (
+	opBytes     hashOpKind = iota // raw memory
+	opString                      // length-prefixed string
+	opFloat32                     // float32 with -0 written as 0
+	opFloat64                     // float64 with -0 written as 0
+	opArray                       // array of non-plain elements
+	opInterface                   // interface, hashed by its dynamic value
+)
+
+// isPlain reports whether values of type t are equal exactly when their
+// memory representations are, so they can be hashed as raw bytes.
+func isPlain(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Ptr, reflect.UnsafePointer, reflect.Chan:
+		return true
+	case reflect.Array:
+		return isPlain(t.Elem())
+	case reflect.Struct:
+		// Padding and blank fields don't take part in ==.
+		var size uintptr
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Name == "_" || f.Offset != size || !isPlain(f.Type) {
+				return false
+			}
+			size += f.Type.Size()
+		}
+		return size == t.Size()
+	}
+	return false
+}
+
+// compileHash appends to ops the hashOps for a value of the comparable type
+// t at offset off.
+func compileHash(t reflect.Type, off uintptr, ops []hashOp) []hashOp {
+	if isPlain(t) {
+		if t.Size() == 0 {
+			return ops
+		}
+		// Merge with the preceding op if it ends where this one starts.
+		if n := len(ops); n > 0 && ops[n-1].kind == opBytes && ops[n-1].offset+ops[n-1].size == off {
+			ops[n-1].size += t.Size()
+			return ops
+		}
+		return append(ops, hashOp{kind: opBytes, offset: off, size: t.Size()})
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return append(ops, hashOp{kind: opString, offset: off})
+	case reflect.Float32:
+		return append(ops, hashOp{kind: opFloat32, offset: off})
+	case reflect.Float64:
+		return append(ops, hashOp{kind: opFloat64, offset: off})
+	case reflect.Complex64:
+		return append(ops, hashOp{kind: opFloat32, offset: off}, hashOp{kind: opFloat32, offset: off + 4})
+	case reflect.Complex128:
+		return append(ops, hashOp{kind: opFloat64, offset: off}, hashOp{kind: opFloat64, offset: off + 8})
+	case reflect.Array:
+		if t.Len() == 0 {
+			return ops
+		}
+		return append(ops, hashOp{
+			kind:   opArray,
+			offset: off,
+			size:   t.Elem().Size(),
+			n:      t.Len(),
+			elem:   compileHash(t.Elem(), 0, nil),
+		})
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.Name != "_" {
+				ops = compileHash(f.Type, off+f.Offset, ops)
+			}
+		}
+		return ops
+	case reflect.Interface:
+		return append(ops, hashOp{kind: opInterface, offset: off, typ: t})
+	}
+	panic("xxhash: hash of unhashable type " + t.String())
+}
+
+// hashOps runs ops on the value at p.
+func hashOps(d *Digest, ops []hashOp, p unsafe.Pointer) {
+	for i := range ops {
+		op := &ops[i]
+		q := unsafe.Pointer(uintptr(p) + op.offset)
+		switch op.kind {
+		case opBytes:
+			d.Write(unsafe.Slice((*byte)(q), op.size))
+		case opString:
+			s := *(*string)(q)
+			d.WriteUint64(uint64(len(s)))
+			d.WriteString(s)
+		case opFloat32:
+			f := *(*float32)(q)
+			if f == 0 {
+				f = 0 // -0 == 0
+			}
+			d.WriteUint32(math.Float32bits(f))
+		case opFloat64:
+			f := *(*float64)(q)
+			if f == 0 {
+				f = 0
+			}
+			d.WriteUint64(math.Float64bits(f))
+		case opArray:
+			for j := 0; j < op.n; j++ {
+				hashOps(d, op.elem, unsafe.Pointer(uintptr(q)+uintptr(j)*op.size))
+			}
+		case opInterface:
+			hashInterface(d, op.typ, q)
+		}
+	}
+}
+
+// hashInterface writes the value of the interface of type t at p to d.
+func hashInterface(d *Digest, t reflect.Type, p unsafe.Pointer) {
+	// Copy the interface rather than using reflect.NewAt, which would make
+	// p, and so every key, escape.
+	v := reflect.New(t).Elem()
+	w := *(*[2]unsafe.Pointer)(p)
+	*(*[2]unsafe.Pointer)(unsafe.Pointer(v.UnsafeAddr())) = w
+	// The first word identifies the dynamic type (it is nil for a nil
+	// interface). Hashing it separates equal-looking values of different
+	// types, such as 0 and "".
+	d.WriteUint64(uint64(uintptr(w[0])))
+	if v.IsNil() {
+		return
+	}
+	e := v.Elem()
+	if !e.Type().Comparable() {
+		panic("xxhash: hash of unhashable type " + e.Type().String())
+	}
+	c := reflect.New(e.Type())
+	c.Elem().Set(e)
+	hashOps(d, compileHash(e.Type(), 0, nil), unsafe.Pointer(c.Pointer()))
+}
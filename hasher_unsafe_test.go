@@ -0,0 +1,55 @@
+//go:build go1.18 && !appengine && !tinygo && !nounsafe
+// +build go1.18,!appengine,!tinygo,!nounsafe
+
+package xxhash
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestHasherPlain(t *testing.T) {
+	h64 := Hasher[uint64]()
+	h16 := Hasher[int16]()
+	for _, v := range []uint64{0, 1, 1 << 40, ^uint64(0)} {
+		b := (*[8]byte)(unsafe.Pointer(&v))[:]
+		if got, want := h64(v, 3), Sum64WithSeed(b, 3); got != want {
+			t.Errorf("uint64 0x%x: got 0x%x; want 0x%x", v, got, want)
+		}
+		w := int16(v)
+		b = (*[2]byte)(unsafe.Pointer(&w))[:]
+		if got, want := h16(w, 3), Sum64WithSeed(b, 3); got != want {
+			t.Errorf("int16 %d: got 0x%x; want 0x%x", w, got, want)
+		}
+	}
+	if h64(1, 0) == h64(1, 1) {
+		t.Error("seed is ignored")
+	}
+}
+
+func TestHasherAllocs(t *testing.T) {
+	type key struct {
+		id   uint32
+		name string
+		w    float64
+	}
+	hs := Hasher[string]()
+	hi := Hasher[int]()
+	hk := Hasher[key]()
+	k := key{1, "user", 2.5}
+	testAllocs(t, func() {
+		sink = hs("hello", 1) + hi(42, 1) + hk(k, 1)
+	})
+}
+
+func TestSum64ComparableAllocs(t *testing.T) {
+	type key struct {
+		id   int
+		name string
+	}
+	k := key{1, "user"}
+	Sum64Comparable(0, k)
+	testAllocs(t, func() {
+		sink = Sum64Comparable(0, k)
+	})
+}
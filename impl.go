@@ -0,0 +1,55 @@
+package xxhash
+
+import "fmt"
+
+// Implementation returns the implementation this build uses for v: "asm"
+// for the assembly XXH64 used on amd64, arm64, and riscv64; "avx2" or "sse2"
+// for the XXH3 vector kernels used on amd64, depending on the CPU; and "go"
+// otherwise, including when the purego tag or XXHASH_PUREGO=1 disables the
+// assembly.
+func Implementation(v Variant) string {
+	switch v {
+	case XXH64:
+		return xxh64Implementation()
+	case XXH3_64, XXH3_128:
+		return xxh3Implementation()
+	}
+	return "go"
+}
+
+// Implementations returns the names of the implementations of v available
+// in this build on this CPU, fastest first. The first is the one selected by
+// default, unless XXHASH_PUREGO=1 is set.
+func Implementations(v Variant) []string {
+	switch v {
+	case XXH64:
+		return xxh64Implementations()
+	case XXH3_64, XXH3_128:
+		return xxh3Implementations()
+	}
+	return []string{"go"}
+}
+
+// SetImplementation selects the named implementation, one of
+// Implementations(v), for v, for example to pin the code path measured by a
+// benchmark. XXH3_64 and XXH3_128 share their implementation, so setting
+// either sets both. It returns an error, and changes nothing, if the
+// implementation is not available.
+//
+// SetImplementation is not safe to call concurrently with hashing; call it
+// during initialization, or in TestMain, before any hashing starts.
+func SetImplementation(v Variant, name string) error {
+	var ok bool
+	switch v {
+	case XXH64:
+		ok = setXXH64Implementation(name)
+	case XXH3_64, XXH3_128:
+		ok = setXXH3Implementation(name)
+	default:
+		ok = name == "go"
+	}
+	if !ok {
+		return fmt.Errorf("xxhash: implementation %q is not available for %s", name, v)
+	}
+	return nil
+}
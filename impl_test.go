@@ -0,0 +1,57 @@
+package xxhash
+
+import "testing"
+
+func TestImplementation(t *testing.T) {
+	for v := XXH64; v <= XXH3_128; v++ {
+		want := map[string]bool{"go": true}
+		switch v {
+		case XXH64:
+			want["asm"] = true
+		case XXH3_64, XXH3_128:
+			want["sse2"], want["avx2"] = true, true
+		}
+		if got := Implementation(v); !want[got] {
+			t.Errorf("Implementation(%s) = %q", v, got)
+		}
+	}
+}
+
+func TestSetImplementation(t *testing.T) {
+	data := xxh3TestInput(5000)
+	for v := XXH64; v <= XXH3_128; v++ {
+		names := Implementations(v)
+		if len(names) == 0 || names[len(names)-1] != "go" {
+			t.Errorf("Implementations(%s) = %q; want a list ending in \"go\"", v, names)
+		}
+		old := Implementation(v)
+		want := sumVariant(v, data)
+		for _, name := range names {
+			if err := SetImplementation(v, name); err != nil {
+				t.Fatalf("SetImplementation(%s, %q): %v", v, name, err)
+			}
+			if got := Implementation(v); got != name {
+				t.Errorf("after SetImplementation(%s, %q), Implementation = %q", v, name, got)
+			}
+			if got := sumVariant(v, data); got != want {
+				t.Errorf("%s with %q: got %x; want %x", v, name, got, want)
+			}
+		}
+		if err := SetImplementation(v, "neon"); err == nil {
+			t.Errorf("SetImplementation(%s, \"neon\"): got nil error", v)
+		}
+		if got := Implementation(v); got != "go" {
+			t.Errorf("failed SetImplementation changed %s to %q", v, got)
+		}
+		if err := SetImplementation(v, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// sumVariant returns the digest of data computed by v's Digest.
+func sumVariant(v Variant, data []byte) string {
+	d := NewVariant(v)
+	d.Write(data)
+	return string(d.Sum(nil))
+}
@@ -0,0 +1,27 @@
+package xxhash
+
+// DeriveIndices stores in dst[:k] k indices in [0, m) derived from the single
+// hash h by the double hashing of Kirsch and Mitzenmacher ("Less Hashing,
+// Same Performance: Building a Better Bloom Filter"): with h1 the low 32 bits
+// of h and h2 the high 32 bits with the lowest bit set, the i'th index is
+// (h1 + i*h2) mod m. For a Bloom or cuckoo filter this performs like k
+// independent hash functions at the cost of one XXH64 computation.
+//
+// Forcing h2 odd means it is never zero, which would make all k indices the
+// same, and that for m a power of two the k indices are all distinct as long
+// as k <= m.
+//
+// Because h1 and h2 have 32 bits each, the indices are best suited to m of
+// at most 2^32. DeriveIndices panics if m is 0 or if dst is shorter than k.
+func DeriveIndices(h uint64, k, m uint64, dst []uint64) {
+	if m == 0 {
+		panic("xxhash: DeriveIndices range must be positive")
+	}
+	if uint64(len(dst)) < k {
+		panic("xxhash: DeriveIndices dst too short")
+	}
+	h1, h2 := h&0xffffffff, h>>32|1
+	for i := uint64(0); i < k; i++ {
+		dst[i] = (h1 + i*h2) % m
+	}
+}
@@ -0,0 +1,58 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeriveIndices(t *testing.T) {
+	dst := make([]uint64, 5)
+	DeriveIndices(0x0000000300000007, 4, 10, dst)
+	if want := []uint64{7, 0, 3, 6, 0}; fmt.Sprint(dst) != fmt.Sprint(want) {
+		t.Errorf("got %v; want %v", dst, want)
+	}
+	// A zero high half still gives distinct indices.
+	DeriveIndices(7, 4, 10, dst)
+	if want := []uint64{7, 8, 9, 0, 0}; fmt.Sprint(dst) != fmt.Sprint(want) {
+		t.Errorf("h2 = 0: got %v; want %v", dst, want)
+	}
+
+	// The indices of many keys cover the range evenly.
+	const m, k = 100, 7
+	counts := make([]int, m)
+	dst = make([]uint64, k)
+	for i := 0; i < 10000; i++ {
+		DeriveIndices(Sum64String(fmt.Sprint("key", i)), k, m, dst)
+		for _, x := range dst {
+			if x >= m {
+				t.Fatalf("key %d: index %d out of range", i, x)
+			}
+			counts[x]++
+		}
+	}
+	for x, c := range counts {
+		if want := 10000 * k / m; c < want/2 || c > want*2 {
+			t.Fatalf("index %d chosen %d times; want about %d", x, c, want)
+		}
+	}
+}
+
+func TestDeriveIndicesPanics(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		k, m   uint64
+		dstLen int
+	}{
+		{"zero range", 1, 0, 1},
+		{"short dst", 3, 10, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic")
+				}
+			}()
+			DeriveIndices(1, tt.k, tt.m, make([]uint64, tt.dstLen))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package xxhash
+
+import "encoding/binary"
+
+// Sum64BigEndianInts computes the XXH64 digest of vals encoded as
+// consecutive 8-byte big-endian integers.
+//
+// This only determines which bytes are hashed; the hash algorithm itself is
+// unchanged. The result equals Sum64 of the big-endian (network order)
+// encoding of vals, so it matches a peer that hashes the same integers as
+// they appear on the wire.
+func Sum64BigEndianInts(vals []uint64) uint64 {
+	var d Digest
+	d.Reset()
+	var buf [256]byte
+	for len(vals) > 0 {
+		n := 0
+		for n+8 <= len(buf) && len(vals) > 0 {
+			binary.BigEndian.PutUint64(buf[n:], vals[0])
+			vals = vals[1:]
+			n += 8
+		}
+		d.Write(buf[:n])
+	}
+	return d.Sum64()
+}
+
+// WriteByte adds c to d. It always returns nil.
+func (d *Digest) WriteByte(c byte) error {
+	if d.n < len(d.mem)-1 {
+		d.mem[d.n] = c
+		d.n++
+		d.total++
+		return nil
+	}
+	d.Write([]byte{c})
+	return nil
+}
+
+// WriteUint32 adds the 4-byte little-endian encoding of v to d.
+func (d *Digest) WriteUint32(v uint32) {
+	if d.n+4 < len(d.mem) {
+		binary.LittleEndian.PutUint32(d.mem[d.n:], v)
+		d.n += 4
+		d.total += 4
+		return
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	d.Write(b[:])
+}
+
+// WriteUint64 adds the 8-byte little-endian encoding of v to d.
+func (d *Digest) WriteUint64(v uint64) {
+	if d.n+8 < len(d.mem) {
+		binary.LittleEndian.PutUint64(d.mem[d.n:], v)
+		d.n += 8
+		d.total += 8
+		return
+	}
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	d.Write(b[:])
+}
+
+// Sum64Uint64 computes the XXH64 digest of the 8-byte little-endian encoding
+// of v, without encoding it: Sum64Uint64(v) equals Sum64 of that encoding.
+func Sum64Uint64(v uint64) uint64 {
+	h := prime5 + 8
+	h ^= round(0, v)
+	h = rol27(h)*prime1 + prime4
+	return avalanche(h)
+}
+
+// Sum64Pair computes the XXH64 digest of the 16-byte encoding of a followed
+// by b, each little-endian, without encoding them.
+func Sum64Pair(a, b uint64) uint64 {
+	h := prime5 + 16
+	h ^= round(0, a)
+	h = rol27(h)*prime1 + prime4
+	h ^= round(0, b)
+	h = rol27(h)*prime1 + prime4
+	return avalanche(h)
+}
+
+// Mix64 returns x passed through the final mixing step of XXH64, a sequence
+// of xor-shifts and multiplications by the XXH64 primes. It is a bijection on
+// uint64 with good avalanche behavior, which makes it useful on its own for
+// scrambling integer keys. Mix64(0) is 0.
+func Mix64(x uint64) uint64 {
+	return avalanche(x)
+}
+
+// Fold32 reduces h to 32 bits for formats that only have room for that many.
+// It passes h through Mix64 and then folds the two halves together with xor,
+// so every bit of h affects every bit of the result. This matters for 64-bit
+// values that are not already well mixed; for an XXH64 digest, every bit is
+// already as good as any other, and uint32(h) would serve as well.
+//
+// Fold32 is not XXH32: Fold32(Sum64(b)) differs from Sum32(b).
+func Fold32(h uint64) uint32 {
+	h = avalanche(h)
+	return uint32(h ^ h>>32)
+}
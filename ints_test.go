@@ -0,0 +1,127 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"testing"
+)
+
+func TestSum64BigEndianInts(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 5, 31, 32, 33, 100} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			vals := make([]uint64, n)
+			for i := range vals {
+				vals[i] = uint64(i)*prime1 + 0x0102030405060708
+			}
+			// Reference: byte-swap each value by hand, then write the
+			// swapped values in little-endian order.
+			ref := make([]byte, 8*n)
+			for i, v := range vals {
+				binary.LittleEndian.PutUint64(ref[8*i:], bits.ReverseBytes64(v))
+			}
+			if got, want := Sum64BigEndianInts(vals), Sum64(ref); got != want {
+				t.Fatalf("got 0x%x; want 0x%x", got, want)
+			}
+		})
+	}
+}
+
+func TestTypedWrites(t *testing.T) {
+	// Mix the typed writers so that values straddle every block offset.
+	d := New()
+	var ref []byte
+	for i := 0; i < 200; i++ {
+		switch i % 3 {
+		case 0:
+			c := byte(i)
+			if err := d.WriteByte(c); err != nil {
+				t.Fatal(err)
+			}
+			ref = append(ref, c)
+		case 1:
+			v := uint32(i) * 0x9e3779b1
+			d.WriteUint32(v)
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], v)
+			ref = append(ref, b[:]...)
+		case 2:
+			v := uint64(i) * prime1
+			d.WriteUint64(v)
+			ref = appendUint64(ref, v)
+		}
+		if got, want := d.Sum64(), Sum64(ref); got != want {
+			t.Fatalf("after %d writes: got 0x%x; want 0x%x", i+1, got, want)
+		}
+	}
+	if d.Len() != uint64(len(ref)) {
+		t.Fatalf("Len: got %d; want %d", d.Len(), len(ref))
+	}
+}
+
+func TestTypedWritesAllocs(t *testing.T) {
+	testAllocs(t, func() {
+		d := New()
+		for i := 0; i < 10; i++ {
+			d.WriteByte(byte(i))
+			d.WriteUint32(uint32(i))
+			d.WriteUint64(uint64(i))
+		}
+		sink = d.Sum64()
+	})
+}
+
+func TestSum64Uint64(t *testing.T) {
+	for _, v := range []uint64{0, 1, 0x0102030405060708, ^uint64(0), prime1} {
+		if got, want := Sum64Uint64(v), Sum64(appendUint64(nil, v)); got != want {
+			t.Errorf("Sum64Uint64(0x%x) = 0x%x; want 0x%x", v, got, want)
+		}
+		for _, w := range []uint64{0, 5, ^uint64(0)} {
+			if got, want := Sum64Pair(v, w), Sum64(appendUint64(appendUint64(nil, v), w)); got != want {
+				t.Errorf("Sum64Pair(0x%x, 0x%x) = 0x%x; want 0x%x", v, w, got, want)
+			}
+		}
+	}
+}
+
+func TestMix64(t *testing.T) {
+	// Mix64 is the XXH64 finalizer: for empty input, the state before it is
+	// just prime5.
+	if got, want := Mix64(prime5), Sum64(nil); got != want {
+		t.Fatalf("Mix64(prime5): got 0x%x; want 0x%x", got, want)
+	}
+	if got := Mix64(0); got != 0 {
+		t.Fatalf("Mix64(0): got 0x%x; want 0", got)
+	}
+	// Flipping any input bit should flip about half of the output bits.
+	for bit := uint(0); bit < 64; bit++ {
+		var total int
+		for i := uint64(1); i <= 100; i++ {
+			x := i * prime1
+			total += bits.OnesCount64(Mix64(x) ^ Mix64(x^1<<bit))
+		}
+		if avg := float64(total) / 100; avg < 28 || avg > 36 {
+			t.Errorf("bit %d: flipped %.1f output bits on average", bit, avg)
+		}
+	}
+}
+
+func TestFold32(t *testing.T) {
+	if got, want := Fold32(0x0123456789abcdef), uint32(Mix64(0x0123456789abcdef)^Mix64(0x0123456789abcdef)>>32); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+	// Unlike a plain truncation, Fold32 depends on the high bits, and
+	// flipping any input bit flips about half of the output bits.
+	if Fold32(1) == Fold32(1|1<<40) {
+		t.Error("Fold32 ignores the high bits")
+	}
+	for bit := uint(0); bit < 64; bit++ {
+		var total int
+		for i := uint64(1); i <= 100; i++ {
+			total += bits.OnesCount32(Fold32(i) ^ Fold32(i^1<<bit))
+		}
+		if avg := float64(total) / 100; avg < 13 || avg > 19 {
+			t.Errorf("bit %d: flipped %.1f output bits on average", bit, avg)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package jsonhash computes XXH64 digests of JSON documents in canonical
+// form, so that semantically identical documents hash identically whatever
+// their key order, whitespace, escapes, and number spellings.
+//
+// The canonical form is the JSON Canonicalization Scheme of RFC 8785. It is
+// kept out of package xxhash so that programs that only hash bytes do not
+// link encoding/json.
+package jsonhash
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Sum64 computes the XXH64 digest of the JSON document data in canonical
+// form: it is xxhash.Sum64 of the output of AppendCanonical.
+func Sum64(data []byte) (uint64, error) {
+	b, err := AppendCanonical(nil, data)
+	if err != nil {
+		return 0, err
+	}
+	return xxhash.Sum64(b), nil
+}
+
+// AppendCanonical appends the RFC 8785 canonical form of the JSON
+// document data to dst. In that form, there is no whitespace; object
+// members are sorted by their keys compared as UTF-16 code units; numbers
+// are written as by ECMAScript's Number.prototype.toString, after rounding
+// them to float64; and strings are written with only the escapes JSON
+// requires, in their shortest forms.
+//
+// It returns an error if data is not a single valid JSON value, if a number
+// is too large for a float64, if an object has duplicate keys, or if a
+// string is not valid Unicode: one containing invalid UTF-8 or an escaped
+// surrogate that is not part of a pair. (encoding/json would replace such
+// strings' bad parts with U+FFFD, making them collide with strings that
+// really contain it.)
+func AppendCanonical(dst, data []byte) ([]byte, error) {
+	if err := checkStrings(data); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dst, err := appendValue(dst, dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("jsonhash: trailing data after value")
+	}
+	return dst, nil
+}
+
+// checkStrings returns an error if a string in the JSON text data contains
+// invalid UTF-8 or an unpaired surrogate escape. It scans the raw text, as
+// the decoded strings have already had such problems replaced; anything
+// else wrong with data is left for the decoder to report.
+func checkStrings(data []byte) error {
+	if !utf8.Valid(data) {
+		return errors.New("jsonhash: invalid UTF-8")
+	}
+	inString := false
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '"':
+			inString = !inString
+		case c == '\\' && inString && i+1 < len(data):
+			i++
+			if data[i] != 'u' {
+				continue
+			}
+			r, ok := unescapeRune(data[i+1:])
+			if !ok || !utf16.IsSurrogate(r) {
+				continue
+			}
+			i += 4
+			if r < 0xDC00 && len(data) > i+2 && data[i+1] == '\\' && data[i+2] == 'u' {
+				if r2, ok := unescapeRune(data[i+3:]); ok && 0xDC00 <= r2 && r2 <= 0xDFFF {
+					i += 6
+					continue
+				}
+			}
+			return errors.New("jsonhash: unpaired surrogate in string")
+		}
+	}
+	return nil
+}
+
+// unescapeRune decodes the four hexadecimal digits at the start of b, which
+// follow a \u escape, reporting whether there were four digits.
+func unescapeRune(b []byte) (rune, bool) {
+	if len(b) < 4 {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(string(b[:4]), 16, 16)
+	return rune(v), err == nil
+}
+
+func appendValue(b []byte, dec *json.Decoder) ([]byte, error) {
+	tok, err := dec.Token()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case bool:
+		return strconv.AppendBool(b, tok), nil
+	case json.Number:
+		f, err := strconv.ParseFloat(string(tok), 64)
+		if err != nil {
+			return nil, errors.New("jsonhash: number out of range")
+		}
+		return appendES6Number(b, f), nil
+	case string:
+		return appendString(b, tok), nil
+	case json.Delim:
+		if tok == '[' {
+			b = append(b, '[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				if b, err = appendValue(b, dec); err != nil {
+					return nil, err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return append(b, ']'), nil
+		}
+		return appendObject(b, dec)
+	}
+	panic("unreachable")
+}
+
+// appendObject appends the members of the object whose opening
+// brace has been read. Each member is first appended after the output so
+// far, and the members are then moved into sorted order.
+func appendObject(b []byte, dec *json.Decoder) ([]byte, error) {
+	type member struct {
+		key        string
+		start, end int // the member's encoding in b
+	}
+	var members []member
+	start := len(b)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		m := member{key: key, start: len(b)}
+		b = appendString(b, key)
+		b = append(b, ':')
+		if b, err = appendValue(b, dec); err != nil {
+			return nil, err
+		}
+		m.end = len(b)
+		members = append(members, m)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	sort.Slice(members, func(i, j int) bool { return lessUTF16(members[i].key, members[j].key) })
+	encoded := append([]byte(nil), b[start:]...)
+	b = append(b[:start], '{')
+	for i, m := range members {
+		if i > 0 {
+			if members[i-1].key == m.key {
+				return nil, errors.New("jsonhash: duplicate object key")
+			}
+			b = append(b, ',')
+		}
+		b = append(b, encoded[m.start-start:m.end-start]...)
+	}
+	return append(b, '}'), nil
+}
+
+// lessUTF16 reports whether s sorts before t when both are compared as
+// sequences of UTF-16 code units.
+func lessUTF16(s, t string) bool {
+	for s != "" && t != "" {
+		r1, n1 := utf8.DecodeRuneInString(s)
+		r2, n2 := utf8.DecodeRuneInString(t)
+		if r1 != r2 {
+			// Supplementary characters are surrogate pairs, which sort
+			// between U+D7FF and U+E000.
+			a1, _ := utf16.EncodeRune(r1)
+			a2, _ := utf16.EncodeRune(r2)
+			if a1 == utf8.RuneError {
+				a1 = r1
+			}
+			if a2 == utf8.RuneError {
+				a2 = r2
+			}
+			if a1 != a2 {
+				return a1 < a2
+			}
+			return r1 < r2
+		}
+		s, t = s[n1:], t[n2:]
+	}
+	return s == "" && t != ""
+}
+
+func appendString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b = append(b, '\\', c)
+		case '\b':
+			b = append(b, '\\', 'b')
+		case '\f':
+			b = append(b, '\\', 'f')
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		case '\t':
+			b = append(b, '\\', 't')
+		default:
+			if c < 0x20 {
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			} else {
+				b = append(b, c)
+			}
+		}
+	}
+	return append(b, '"')
+}
+
+// appendES6Number appends f as formatted by ECMAScript's Number::toString,
+// which RFC 8785 requires: the shortest digits that round-trip, in plain
+// notation for magnitudes from 1e-6 up to 1e21 and in exponent notation
+// otherwise.
+func appendES6Number(b []byte, f float64) []byte {
+	if f == 0 {
+		return append(b, '0') // including -0
+	}
+	if f < 0 {
+		b = append(b, '-')
+		f = -f
+	}
+	// e is d.ddde±x: split it into the digits and the decimal point position n.
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	i := 0
+	for i < len(e) && e[i] != 'e' {
+		i++
+	}
+	digits := e[:1]
+	if i > 1 {
+		digits += e[2:i]
+	}
+	exp, _ := strconv.Atoi(e[i+1:])
+	n, k := exp+1, len(digits)
+	switch {
+	case k <= n && n <= 21:
+		b = append(b, digits...)
+		for j := k; j < n; j++ {
+			b = append(b, '0')
+		}
+	case 0 < n && n <= 21:
+		b = append(b, digits[:n]...)
+		b = append(b, '.')
+		b = append(b, digits[n:]...)
+	case -6 < n && n <= 0:
+		b = append(b, '0', '.')
+		for j := n; j < 0; j++ {
+			b = append(b, '0')
+		}
+		b = append(b, digits...)
+	default:
+		b = append(b, digits[0])
+		if k > 1 {
+			b = append(b, '.')
+			b = append(b, digits[1:]...)
+		}
+		b = append(b, 'e')
+		if n-1 >= 0 {
+			b = append(b, '+')
+		}
+		b = strconv.AppendInt(b, int64(n-1), 10)
+	}
+	return b
+}
@@ -0,0 +1,121 @@
+package jsonhash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func TestAppendCanonical(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		// The example from RFC 8785, section 3.2.3.
+		{
+			`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		// Sorting by UTF-16 code units, from RFC 8785, section 3.2.3.
+		{
+			`{"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\ud83d\ude00": 5, "\u0080": 6, "\u00f6": 7, "</script>": 8}`,
+			"{\"\\r\":2,\"1\":4,\"</script>\":8,\"\u0080\":6,\"ö\":7,\"€\":1,\"\U0001F600\":5,\"\ufb33\":3}",
+		},
+		{` "x" `, `"x"`},
+		{`[]`, `[]`},
+		{`{}`, `{}`},
+		{`{"b": {"d": [1, {"f": 0, "e": -0}], "c": null}, "a": "\b\f\t\u0001"}`, `{"a":"\b\f\t\u0001","b":{"c":null,"d":[1,{"e":0,"f":0}]}}`},
+		{`[1.0, 100, 1e2, 12345678901234567890]`, `[1,100,100,12345678901234567000]`},
+		{`["\ud83d\ude00", "\\ud800", "\ufffd"]`, "[\"\U0001F600\",\"\\\\ud800\",\"\ufffd\"]"},
+	} {
+		got, err := AppendCanonical([]byte("prefix:"), []byte(tt.in))
+		if err != nil {
+			t.Errorf("AppendCanonical(%s): %v", tt.in, err)
+			continue
+		}
+		if string(got) != "prefix:"+tt.want {
+			t.Errorf("AppendCanonical(%s):\ngot  %s\nwant prefix:%s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAppendCanonicalErrors(t *testing.T) {
+	for _, in := range []string{
+		``, `{`, `[1,]`, `{"a":1} x`, `1 2`, `1e400`, `{"a":1,"a":2}`, `{"a":{"b":1,"b":1}}`, `nul`,
+		`"\ud800"`, `"\udc00"`, `"\ud800\u0041"`, `"\ud800\ud800"`, `"x\ude00y"`, `{"\ud83d": 1}`, "\"\xff\"",
+	} {
+		if got, err := AppendCanonical(nil, []byte(in)); err == nil {
+			t.Errorf("AppendCanonical(%q) = %s; want error", in, got)
+		}
+	}
+}
+
+func TestES6Number(t *testing.T) {
+	// Values from RFC 8785, appendix B.
+	for _, tt := range []struct {
+		bits uint64
+		want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	} {
+		if got := string(appendES6Number(nil, math.Float64frombits(tt.bits))); got != tt.want {
+			t.Errorf("%016x: got %s; want %s", tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestSum64(t *testing.T) {
+	a, err := Sum64([]byte(`{"id": 1, "tags": ["x", "y"], "meta": {"b": true, "a": 1.50}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Sum64([]byte("{\n\t\"meta\": {\"a\": 15e-1, \"b\": true},\n\t\"tags\": [\"\\u0078\", \"y\"],\n\t\"id\": 1.0\n}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("equivalent documents hash differently: 0x%x != 0x%x", a, b)
+	}
+	if want := xxhash.Sum64String(`{"id":1,"meta":{"a":1.5,"b":true},"tags":["x","y"]}`); a != want {
+		t.Errorf("got 0x%x; want 0x%x", a, want)
+	}
+	c, _ := Sum64([]byte(`{"id": 1, "tags": ["y", "x"], "meta": {"b": true, "a": 1.5}}`))
+	if c == a {
+		t.Error("array order is ignored")
+	}
+	if _, err := Sum64([]byte(`{`)); err == nil {
+		t.Error("invalid JSON: got nil error")
+	}
+	// encoding/json decodes a lone surrogate as U+FFFD, but the two are
+	// different strings and must not share a digest.
+	if _, err := Sum64([]byte(`"\ud800"`)); err == nil {
+		t.Error("lone surrogate: got nil error")
+	}
+}
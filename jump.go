@@ -0,0 +1,27 @@
+package xxhash
+
+// JumpHash returns the bucket, in [0, buckets), of key under the jump
+// consistent hash of Lamping and Veach (https://arxiv.org/abs/1406.2294).
+// When the number of buckets grows from n to n+1, only about 1/(n+1) of the
+// keys move, all of them to the new bucket.
+//
+// The algorithm assumes that key is already uniformly distributed, such as
+// an XXH64 digest; see JumpHashString. JumpHash panics if buckets is not
+// positive.
+func JumpHash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		panic("xxhash: JumpHash bucket count must be positive")
+	}
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// JumpHashString returns JumpHash(Sum64String(key), buckets).
+func JumpHashString(key string, buckets int) int {
+	return JumpHash(Sum64String(key), buckets)
+}
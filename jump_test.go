@@ -0,0 +1,69 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestJumpHash(t *testing.T) {
+	// Values computed with the C++ implementation given in the paper.
+	for _, tt := range []struct {
+		key     uint64
+		buckets int
+		want    int
+	}{
+		{0, 1, 0},
+		{0, 100, 0},
+		{1, 100, 55},
+		{0xdeadbeef, 100, 87},
+		{0xffffffffffffffff, 1000, 313},
+		{123456789, 7, 0},
+		{42, 1 << 20, 153897},
+	} {
+		if got := JumpHash(tt.key, tt.buckets); got != tt.want {
+			t.Errorf("JumpHash(0x%x, %d): got %d; want %d", tt.key, tt.buckets, got, tt.want)
+		}
+	}
+}
+
+func TestJumpHashConsistency(t *testing.T) {
+	const keys = 10000
+	prev := make([]int, keys)
+	for n := 1; n <= 50; n++ {
+		counts := make([]int, n)
+		for k := 0; k < keys; k++ {
+			b := JumpHash(Sum64Uint64(uint64(k)), n)
+			if b < 0 || b >= n {
+				t.Fatalf("n=%d: bucket %d out of range", n, b)
+			}
+			if n > 1 && b != prev[k] && b != n-1 {
+				t.Fatalf("n=%d: key %d moved from bucket %d to %d", n, k, prev[k], b)
+			}
+			prev[k] = b
+			counts[b]++
+		}
+		for b, c := range counts {
+			if want := keys / n; c < want*3/4 || c > want*5/4 {
+				t.Errorf("n=%d: bucket %d has %d keys; want about %d", n, b, c, want)
+			}
+		}
+	}
+}
+
+func TestJumpHashString(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint("key", i)
+		if got, want := JumpHashString(key, 37), JumpHash(Sum64String(key), 37); got != want {
+			t.Fatalf("JumpHashString(%q, 37): got %d; want %d", key, got, want)
+		}
+	}
+}
+
+func TestJumpHashPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("JumpHash(0, 0) did not panic")
+		}
+	}()
+	JumpHash(0, 0)
+}
@@ -0,0 +1,46 @@
+package xxhash
+
+import "testing"
+
+func TestKeyBuilder(t *testing.T) {
+	k := NewKeyBuilder().AddString("ab").AddBytes([]byte("c")).AddUint64(7).AddInt64(-1).AddBool(true)
+	var ref []byte
+	ref = appendUint64(ref, 2)
+	ref = append(ref, "ab"...)
+	ref = appendUint64(ref, 1)
+	ref = append(ref, 'c')
+	ref = appendUint64(ref, 7)
+	ref = appendUint64(ref, ^uint64(0))
+	ref = append(ref, 1)
+	if got, want := k.Hash(), Sum64(ref); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := k.Reset().AddString("abc").Hash(), NewKeyBuilder().AddBytes([]byte("abc")).Hash(); got != want {
+		t.Fatalf("after Reset: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestKeyBuilderFraming(t *testing.T) {
+	for _, pair := range [][2][]string{
+		{{"ab", "c"}, {"a", "bc"}},
+		{{"", "a"}, {"a", ""}},
+		{{"abc"}, {"abc", ""}},
+	} {
+		h := func(parts []string) uint64 {
+			k := NewKeyBuilder()
+			for _, p := range parts {
+				k.AddString(p)
+			}
+			return k.Hash()
+		}
+		if h(pair[0]) == h(pair[1]) {
+			t.Errorf("%q and %q hash identically", pair[0], pair[1])
+		}
+	}
+}
+
+func TestKeyBuilderAllocs(t *testing.T) {
+	testAllocs(t, func() {
+		sink = NewKeyBuilder().AddString("user").AddUint64(42).AddBool(false).Hash()
+	})
+}
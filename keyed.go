@@ -0,0 +1,19 @@
+package xxhash
+
+// NewKeyed creates a new DigestXXH3 keyed by key, which may be of any
+// length, for per-tenant or per-service hashing where a 64-bit seed is too
+// small. The scheme is XXH3 with a custom secret derived from the key, so
+// other implementations can reproduce it: the digest is the same as
+// NewXXH3WithSecret(GenerateXXH3Secret(key, 192)), that is,
+// XXH3_64bits_withSecret (or XXH3_128bits_withSecret for Sum128) with a
+// secret from XXH3_generateSecret(key, 192) in the reference implementation.
+// Every byte of the key affects the secret.
+//
+// Deriving the secret costs about as much as hashing a few hundred bytes;
+// to hash many short inputs with one key, generate the secret once and use
+// NewXXH3WithSecret or SumXXH3_64WithSecret. Keyed XXH3 is not a MAC: it
+// makes hashes unpredictable without the key, but it is not designed to
+// resist an attacker who can choose inputs and observe hashes.
+func NewKeyed(key []byte) *DigestXXH3 {
+	return NewXXH3WithSecret(GenerateXXH3Secret(key, len(xxh3Secret)))
+}
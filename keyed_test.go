@@ -0,0 +1,40 @@
+package xxhash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewKeyed(t *testing.T) {
+	key := []byte("tenant-1234")
+	secret := GenerateXXH3Secret(key, 192)
+	for _, n := range []int{0, 1, 16, 17, 128, 129, 240, 241, 1000, 5000} {
+		data := xxh3TestInput(n)
+		d := NewKeyed(key)
+		d.Write(data)
+		if got, want := d.Sum64(), SumXXH3_64WithSecret(data, secret); got != want {
+			t.Errorf("n=%d: Sum64 = 0x%x; want 0x%x", n, got, want)
+		}
+		if got, want := d.Sum128(), SumXXH3_128WithSecret(data, secret); got != want {
+			t.Errorf("n=%d: Sum128 = %v; want %v", n, got, want)
+		}
+	}
+}
+
+func TestNewKeyedKeys(t *testing.T) {
+	long := bytes.Repeat([]byte{'k'}, 500)
+	longer := append(append([]byte(nil), long...), 'x')
+	other := append([]byte(nil), long...)
+	other[400] = 'x' // beyond the length of the secret
+	data := xxh3TestInput(100)
+	seen := make(map[uint64][]byte)
+	for _, key := range [][]byte{nil, []byte("a"), []byte("b"), []byte("ab"), long, longer, other} {
+		d := NewKeyed(key)
+		d.Write(data)
+		h := d.Sum64()
+		if prev, ok := seen[h]; ok {
+			t.Errorf("keys %q and %q give the same hash", prev, key)
+		}
+		seen[h] = key
+	}
+}
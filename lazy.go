@@ -0,0 +1,32 @@
+package xxhash
+
+import "sync"
+
+// Lazy returns a function that computes the XXH64 digest of the data returned
+// by produce, calling produce at most once.
+//
+// The first call to the returned function invokes produce and hashes its
+// result; every later call returns the same digest without calling produce
+// again. If produce returns an error, that error is cached as well: produce
+// is not retried, and every call returns 0 and the same error.
+//
+// The returned function is safe for concurrent use. Concurrent callers that
+// arrive while produce is running block until it completes.
+func Lazy(produce func() ([]byte, error)) func() (uint64, error) {
+	var (
+		once sync.Once
+		sum  uint64
+		err  error
+	)
+	return func() (uint64, error) {
+		once.Do(func() {
+			var b []byte
+			b, err = produce()
+			if err == nil {
+				sum = Sum64(b)
+			}
+			produce = nil // allow the producer and its captures to be collected
+		})
+		return sum, err
+	}
+}
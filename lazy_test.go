@@ -0,0 +1,57 @@
+package xxhash
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	var calls int32
+	data := []byte("expensive value")
+	f := Lazy(func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return data, nil
+	})
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatalf("produce called %d times before first use", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := f()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if want := Sum64(data); got != want {
+				t.Errorf("got 0x%x; want 0x%x", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("produce called %d times; want 1", n)
+	}
+}
+
+func TestLazyError(t *testing.T) {
+	var calls int32
+	errProduce := errors.New("produce failed")
+	f := Lazy(func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errProduce
+	})
+	for i := 0; i < 3; i++ {
+		if got, err := f(); got != 0 || err != errProduce {
+			t.Fatalf("call %d: got (0x%x, %v); want (0, %v)", i, got, err, errProduce)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("produce called %d times; want 1 (errors are cached)", n)
+	}
+}
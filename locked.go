@@ -0,0 +1,64 @@
+package xxhash
+
+import "sync"
+
+// A SafeDigest is a Digest guarded by a mutex, so that several goroutines
+// can write to it concurrently. Each Write is added atomically, but writes
+// from different goroutines are hashed in whatever order they acquire the
+// lock; callers that need a particular order must arrange it themselves.
+//
+// A plain Digest is faster and should be preferred when it is only used by
+// one goroutine at a time.
+type SafeDigest struct {
+	mu sync.Mutex
+	d  Digest
+}
+
+// NewSafe creates a new SafeDigest that computes the 64-bit xxHash algorithm.
+func NewSafe() *SafeDigest {
+	return new(SafeDigest)
+}
+
+// Reset clears the digest's state so that it can be reused.
+// It uses a seed value of zero.
+func (s *SafeDigest) Reset() {
+	s.mu.Lock()
+	s.d.Reset()
+	s.mu.Unlock()
+}
+
+// Size always returns 8 bytes.
+func (s *SafeDigest) Size() int { return 8 }
+
+// BlockSize always returns 32 bytes.
+func (s *SafeDigest) BlockSize() int { return 32 }
+
+// Write adds more data to s. It always returns len(b), nil.
+func (s *SafeDigest) Write(b []byte) (n int, err error) {
+	s.mu.Lock()
+	s.d.Write(b)
+	s.mu.Unlock()
+	return len(b), nil
+}
+
+// WriteString adds more data to s. It always returns len(str), nil.
+func (s *SafeDigest) WriteString(str string) (n int, err error) {
+	s.mu.Lock()
+	s.d.WriteString(str)
+	s.mu.Unlock()
+	return len(str), nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (s *SafeDigest) Sum(b []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Sum(b)
+}
+
+// Sum64 returns the current hash.
+func (s *SafeDigest) Sum64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Sum64()
+}
@@ -0,0 +1,50 @@
+package xxhash
+
+import (
+	"hash"
+	"sync"
+	"testing"
+)
+
+var _ hash.Hash64 = (*SafeDigest)(nil)
+
+func TestSafeDigest(t *testing.T) {
+	s := NewSafe()
+	s.WriteString("hello, ")
+	s.Write([]byte("world"))
+	if got, want := s.Sum64(), Sum64String("hello, world"); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+	s.Reset()
+	if got, want := s.Sum64(), Sum64(nil); got != want {
+		t.Fatalf("after Reset: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestSafeDigestConcurrent(t *testing.T) {
+	// The result doesn't depend on the order of the writes when they are
+	// all the same.
+	const goroutines, writes = 8, 1000
+	chunk := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	s := NewSafe()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < writes; j++ {
+				s.Write(chunk)
+				s.Sum64()
+			}
+		}()
+	}
+	wg.Wait()
+
+	d := New()
+	for i := 0; i < goroutines*writes; i++ {
+		d.Write(chunk)
+	}
+	if got, want := s.Sum64(), d.Sum64(); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+}
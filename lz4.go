@@ -0,0 +1,52 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The LZ4 frame format (https://github.com/lz4/lz4/blob/dev/doc/lz4_Frame_format.md)
+// has three checksums, all XXH32 with a seed of zero:
+//
+//   - the header checksum (HC), the second byte of the XXH32 of the frame
+//     descriptor from FLG to the end of the optional fields;
+//   - the optional block checksum, the XXH32 of each block's data as stored,
+//     compressed or not, in 4 little-endian bytes after the block;
+//   - the optional content checksum, the XXH32 of the decompressed content
+//     in 4 little-endian bytes after the end mark.
+//
+// A streaming content checksum can be computed with New32 and written with
+// Digest32.AppendSumLE.
+
+// LZ4HeaderChecksum returns the HC byte for an LZ4 frame descriptor, which
+// starts at FLG and excludes the magic number and the HC byte itself.
+func LZ4HeaderChecksum(descriptor []byte) byte {
+	return byte(Sum32(descriptor) >> 8)
+}
+
+// LZ4Checksum returns the LZ4 block or content checksum of b.
+func LZ4Checksum(b []byte) uint32 {
+	return Sum32(b)
+}
+
+// AppendLZ4Checksum appends the LZ4 block or content checksum of data to b,
+// in little-endian byte order, and returns the resulting slice.
+func AppendLZ4Checksum(b, data []byte) []byte {
+	var s [4]byte
+	binary.LittleEndian.PutUint32(s[:], Sum32(data))
+	return append(b, s[:]...)
+}
+
+// VerifyLZ4Checksum checks data against field, the 4-byte LZ4 block or
+// content checksum stored after it. If they do not match, it returns a
+// *MismatchError.
+func VerifyLZ4Checksum(data, field []byte) error {
+	if len(field) != 4 {
+		return errors.New("xxhash: LZ4 checksum field must be 4 bytes")
+	}
+	got, want := Sum32(data), binary.LittleEndian.Uint32(field)
+	if got != want {
+		return &MismatchError{Got: uint64(got), Want: uint64(want)}
+	}
+	return nil
+}
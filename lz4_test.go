@@ -0,0 +1,145 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// lz4ReferenceFrames were produced with liblz4 1.9.4, the library behind the
+// lz4 command-line tool, using the frame preferences that the tool sets for
+// the listed flags (compression level 1, content checksum on).
+var lz4ReferenceFrames = []struct {
+	flags   string
+	content string
+	frame   string
+	blocks  int
+}{
+	{
+		// Default settings, empty input: FLG 0x64, BD 0x40.
+		flags:   "",
+		content: "",
+		frame:   "04224d186440a700000000055dcc02",
+	},
+	{
+		// One uncompressed block with a block checksum: FLG 0x74.
+		flags:   "-BX",
+		content: "Call me Ishmael. Some years ago--never mind how long precisely-",
+		frame: "04224d187440bd3f00008043616c6c206d65204973686d61656c2e20536f6d65" +
+			"2079656172732061676f2d2d6e65766572206d696e6420686f77206c6f6e6720" +
+			"707265636973656c792d5903326f000000005903326f",
+		blocks: 1,
+	},
+	{
+		// Two linked, compressed 64KB blocks and the content size: FLG 0x4c.
+		flags:   "-B4 -BD --content-size",
+		content: strings.Repeat("xxhash lz4 frame test\n", 4000)[:70000],
+		frame: "04224d184c4070110100000000006421010000ff07787868617368206c7a3420" +
+			"6672616d6520746573740a1600ffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffd25065207465731b0000000fecffffffffffff" +
+			"ffffffffffffffffffffffff6950616d6520740000000073d6edb1",
+		blocks: 2,
+	},
+}
+
+func TestLZ4ReferenceFrames(t *testing.T) {
+	for _, tt := range lz4ReferenceFrames {
+		t.Run("flags="+tt.flags, func(t *testing.T) {
+			frame, err := hex.DecodeString(tt.frame)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := []byte(tt.content)
+			if !bytes.Equal(frame[:4], []byte{0x04, 0x22, 0x4d, 0x18}) {
+				t.Fatalf("bad magic number %x", frame[:4])
+			}
+
+			// Frame descriptor: FLG, BD, and the optional content size and
+			// dictionary ID, followed by the header checksum.
+			flg := frame[4]
+			end := 6
+			if flg&0x08 != 0 {
+				if size := binary.LittleEndian.Uint64(frame[end:]); size != uint64(len(content)) {
+					t.Errorf("content size: got %d; want %d", size, len(content))
+				}
+				end += 8
+			}
+			if flg&0x01 != 0 {
+				end += 4
+			}
+			if got := LZ4HeaderChecksum(frame[4:end]); got != frame[end] {
+				t.Errorf("header checksum: got 0x%02x; want 0x%02x", got, frame[end])
+			}
+
+			// Data blocks, each with an optional block checksum, up to the
+			// end mark.
+			p := frame[end+1:]
+			blocks := 0
+			for {
+				size := binary.LittleEndian.Uint32(p)
+				p = p[4:]
+				if size == 0 {
+					break
+				}
+				n := size &^ (1 << 31)
+				data := p[:n]
+				p = p[n:]
+				if size&(1<<31) != 0 && !bytes.Contains(content, data) {
+					t.Errorf("block %d: uncompressed data is not part of the content", blocks)
+				}
+				if flg&0x10 != 0 {
+					if err := VerifyLZ4Checksum(data, p[:4]); err != nil {
+						t.Errorf("block %d checksum: %v", blocks, err)
+					}
+					p = p[4:]
+				}
+				blocks++
+			}
+			if blocks != tt.blocks {
+				t.Errorf("got %d blocks; want %d", blocks, tt.blocks)
+			}
+
+			if flg&0x04 == 0 {
+				t.Fatal("frame has no content checksum")
+			}
+			if err := VerifyLZ4Checksum(content, p[:4]); err != nil {
+				t.Errorf("content checksum: %v", err)
+			}
+			if got := AppendLZ4Checksum(nil, content); !bytes.Equal(got, p[:4]) {
+				t.Errorf("AppendLZ4Checksum: got %x; want %x", got, p[:4])
+			}
+			d := New32()
+			d.WriteString(tt.content)
+			if got := d.AppendSumLE(nil); !bytes.Equal(got, p[:4]) {
+				t.Errorf("streaming content checksum: got %x; want %x", got, p[:4])
+			}
+			if len(p) != 4 {
+				t.Errorf("%d bytes after the content checksum", len(p)-4)
+			}
+		})
+	}
+}
+
+func TestVerifyLZ4ChecksumErrors(t *testing.T) {
+	content := []byte("abc")
+	field := AppendLZ4Checksum(nil, content)
+	if got, want := LZ4Checksum(content), Sum32(content); got != want {
+		t.Errorf("LZ4Checksum = 0x%08x; want 0x%08x", got, want)
+	}
+	err := VerifyLZ4Checksum([]byte("abd"), field)
+	if me, ok := err.(*MismatchError); !ok || me.Want != uint64(Sum32(content)) {
+		t.Errorf("corrupt data: got %v; want a *MismatchError", err)
+	}
+	if err := VerifyLZ4Checksum(content, field[:3]); err == nil {
+		t.Error("short field: got nil error")
+	}
+}
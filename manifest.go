@@ -0,0 +1,126 @@
+package xxhash
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// VerifyStatus is the outcome of checking a single file in VerifyManifest.
+type VerifyStatus int
+
+const (
+	VerifyOK       VerifyStatus = iota // the file's digest matched
+	VerifyMismatch                     // the file's digest did not match
+	VerifyMissing                      // the file does not exist
+	VerifyFailed                       // the file could not be read
+)
+
+func (s VerifyStatus) String() string {
+	switch s {
+	case VerifyOK:
+		return "OK"
+	case VerifyMismatch:
+		return "MISMATCH"
+	case VerifyMissing:
+		return "MISSING"
+	case VerifyFailed:
+		return "FAILED"
+	}
+	return "VerifyStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// A VerifyResult is the result of checking one manifest entry.
+type VerifyResult struct {
+	Name     string // file name as written in the manifest
+	Expected uint64
+	Actual   uint64 // only meaningful if Status is VerifyOK or VerifyMismatch
+	Status   VerifyStatus
+	// Err is a *MismatchError if Status is VerifyMismatch, the error from
+	// opening or reading the file if Status is VerifyMissing or VerifyFailed,
+	// and nil otherwise.
+	Err error
+}
+
+// VerifyManifest checks the files listed in a checksum manifest against their
+// recorded XXH64 digests.
+//
+// The manifest uses the format written by xxhsum and the coreutils *sum
+// tools: each non-empty line is a 16-digit hexadecimal digest, two spaces (or
+// a space and an asterisk), and a file name. Relative file names are resolved
+// against baseDir.
+//
+// VerifyManifest returns one result per entry, in manifest order. Problems
+// with individual files are reported in the results; the returned error is
+// only non-nil if the manifest itself cannot be read or parsed.
+func VerifyManifest(manifestPath, baseDir string) ([]VerifyResult, error) {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []VerifyResult
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		want, name, ok := parseManifestLine(line)
+		if !ok {
+			return nil, fmt.Errorf("xxhash: %s:%d: malformed manifest line", manifestPath, lineNum)
+		}
+		results = append(results, verifyFile(baseDir, name, want))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func parseManifestLine(line string) (sum uint64, name string, ok bool) {
+	if len(line) < 16+2+1 {
+		return 0, "", false
+	}
+	if sep := line[16:18]; sep != "  " && sep != " *" {
+		return 0, "", false
+	}
+	sum, err := strconv.ParseUint(line[:16], 16, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return sum, line[18:], true
+}
+
+func verifyFile(baseDir, name string, want uint64) VerifyResult {
+	r := VerifyResult{Name: name, Expected: want}
+	path := filepath.FromSlash(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		r.Status, r.Err = VerifyFailed, err
+		if os.IsNotExist(err) {
+			r.Status = VerifyMissing
+		}
+		return r
+	}
+	defer f.Close()
+	got, err := NewMapping(f).Sum64()
+	if err != nil {
+		r.Status, r.Err = VerifyFailed, err
+		return r
+	}
+	r.Actual = got
+	if got != want {
+		r.Status, r.Err = VerifyMismatch, &MismatchError{Got: got, Want: want}
+		return r
+	}
+	r.Status = VerifyOK
+	return r
+}
@@ -0,0 +1,90 @@
+package xxhash
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhash-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"good.txt":     "good contents",
+		"sub/also.txt": "more good contents",
+		"bad.txt":      "contents that changed",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := strings.Join([]string{
+		fmt.Sprintf("%016x  good.txt", Sum64String("good contents")),
+		fmt.Sprintf("%016x *sub/also.txt", Sum64String("more good contents")),
+		"",
+		fmt.Sprintf("%016x  bad.txt", Sum64String("original contents")),
+		fmt.Sprintf("%016x  missing.txt", Sum64String("gone")),
+	}, "\n") + "\n"
+	manifestPath := filepath.Join(dir, "MANIFEST")
+	if err := ioutil.WriteFile(manifestPath, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := VerifyManifest(manifestPath, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name   string
+		status VerifyStatus
+	}{
+		{"good.txt", VerifyOK},
+		{"sub/also.txt", VerifyOK},
+		{"bad.txt", VerifyMismatch},
+		{"missing.txt", VerifyMissing},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results; want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Name != w.name || r.Status != w.status {
+			t.Errorf("result %d: got (%q, %s); want (%q, %s)", i, r.Name, r.Status, w.name, w.status)
+		}
+	}
+	bad := results[2]
+	if bad.Expected != Sum64String("original contents") || bad.Actual != Sum64String("contents that changed") {
+		t.Errorf("mismatch result has Expected=0x%x, Actual=0x%x", bad.Expected, bad.Actual)
+	}
+	if merr, ok := bad.Err.(*MismatchError); !ok || merr.Got != bad.Actual || merr.Want != bad.Expected {
+		t.Errorf("mismatch result has Err=%#v", bad.Err)
+	}
+	if results[0].Err != nil || results[3].Err == nil {
+		t.Errorf("unexpected Err values: %v, %v", results[0].Err, results[3].Err)
+	}
+}
+
+func TestVerifyManifestMalformed(t *testing.T) {
+	for _, line := range []string{
+		"abc  file",
+		"0123456789abcdef file",
+		"0123456789abcdeg  file",
+		"0123456789abcdef  ",
+	} {
+		if _, _, ok := parseManifestLine(line); ok {
+			t.Errorf("parseManifestLine(%q) succeeded", line)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package xxhash
+
+import "os"
+
+// A Mapping computes the XXH64 digest of a file's contents.
+//
+// On platforms that support it, the file is memory-mapped read-only for the
+// duration of the hash so that its contents are fed directly to Sum64 without
+// being copied through a read buffer. Elsewhere, and for files that cannot be
+// mapped, the contents are read with ordinary buffered reads instead. Either
+// way the result is the same.
+type Mapping struct {
+	f *os.File
+}
+
+// NewMapping returns a Mapping for f. The caller retains ownership of f and
+// is responsible for closing it.
+func NewMapping(f *os.File) *Mapping {
+	return &Mapping{f: f}
+}
+
+// Sum64 returns the XXH64 digest of the file's contents.
+//
+// For regular files the entire file is hashed, starting at offset 0,
+// regardless of the current file offset (which is not modified). Other kinds
+// of files, such as pipes, are hashed by reading from the current offset
+// until EOF.
+func (m *Mapping) Sum64() (uint64, error) {
+	fi, err := m.f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if !fi.Mode().IsRegular() {
+		return readSum64(m.f)
+	}
+	return m.sum64(fi.Size())
+}
+
+// Sum64File returns the XXH64 digest of the contents of the named file. Like
+// Mapping.Sum64, it memory-maps the file where possible and falls back to
+// ordinary reads otherwise.
+func Sum64File(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return NewMapping(f).Sum64()
+}
@@ -0,0 +1,35 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+// +build !appengine
+// +build !tinygo
+
+package xxhash
+
+import (
+	"io"
+	"syscall"
+)
+
+func (m *Mapping) sum64(size int64) (sum uint64, err error) {
+	if size == 0 {
+		// mmap rejects zero-length mappings.
+		return Sum64(nil), nil
+	}
+	if sum, ok, err := sparseSum64(m.f, size); ok {
+		return sum, err
+	}
+	if int64(int(size)) != size {
+		// Too large to map on this platform (32-bit).
+		return readSum64(io.NewSectionReader(m.f, 0, size))
+	}
+	b, err := syscall.Mmap(int(m.f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		// Some filesystems don't support mmap; fall back to reading.
+		return readSum64(io.NewSectionReader(m.f, 0, size))
+	}
+	defer func() {
+		if uerr := syscall.Munmap(b); uerr != nil && err == nil {
+			sum, err = 0, uerr
+		}
+	}()
+	return Sum64(b), nil
+}
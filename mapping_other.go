@@ -0,0 +1,9 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd appengine tinygo
+
+package xxhash
+
+import "io"
+
+func (m *Mapping) sum64(size int64) (uint64, error) {
+	return readSum64(io.NewSectionReader(m.f, 0, size))
+}
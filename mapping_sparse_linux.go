@@ -0,0 +1,84 @@
+// +build !appengine
+// +build !tinygo
+
+package xxhash
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// lseek whence values for finding data and holes in sparse files.
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+// zeroBuf is hashed in place of the holes of sparse files.
+var zeroBuf [maxReadBufSize]byte
+
+// sparseSum64 hashes the first size bytes of f, which must be a regular file,
+// writing zeros for its holes instead of reading (or faulting in) them. It
+// reports ok = false, having done nothing, if f has no holes or the
+// filesystem cannot report them. The file offset is not modified.
+func sparseSum64(f *os.File, size int64) (sum uint64, ok bool, err error) {
+	fd := int(f.Fd())
+	cur, err := syscall.Seek(fd, 0, io.SeekCurrent)
+	if err != nil {
+		return 0, false, nil
+	}
+	// Seeking for data and holes moves the offset; put it back when done.
+	defer func() {
+		if _, serr := syscall.Seek(fd, cur, io.SeekStart); serr != nil && err == nil {
+			sum, err = 0, serr
+		}
+	}()
+	hole, err := syscall.Seek(fd, 0, seekHole)
+	if err != nil || hole >= size {
+		// Either SEEK_HOLE is unsupported or the only hole is the implicit
+		// one at EOF.
+		return 0, false, nil
+	}
+
+	d := New()
+	var off int64
+	for off < size {
+		data, err := syscall.Seek(fd, off, seekData)
+		if err == syscall.ENXIO || data > size {
+			// The rest of the file is a hole.
+			data = size
+		} else if err != nil {
+			return 0, true, err
+		}
+		writeZeros(d, data-off)
+		if data == size {
+			break
+		}
+		hole, err := syscall.Seek(fd, data, seekHole)
+		if err != nil {
+			return 0, true, err
+		}
+		if hole > size {
+			hole = size
+		}
+		sr := io.NewSectionReader(f, data, hole-data)
+		if _, err := d.ReadFrom(sr); err != nil {
+			return 0, true, err
+		}
+		off = hole
+	}
+	return d.Sum64(), true, nil
+}
+
+// writeZeros writes n zero bytes to d.
+func writeZeros(d *Digest, n int64) {
+	for n > 0 {
+		k := int64(len(zeroBuf))
+		if n < k {
+			k = n
+		}
+		d.Write(zeroBuf[:k])
+		n -= k
+	}
+}
@@ -0,0 +1,32 @@
+// +build !appengine
+// +build !tinygo
+
+package xxhash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSparseSum64(t *testing.T) {
+	data := bytes.Repeat([]byte("sparse "), 1000)
+	f, contents := writeSparseFile(t, 4<<20, data, 1<<20)
+	defer removeTempFile(f)
+	sum, ok, err := sparseSum64(f, int64(len(contents)))
+	if !ok {
+		t.Skip("filesystem does not report holes")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Sum64(contents); sum != want {
+		t.Fatalf("got 0x%x; want 0x%x", sum, want)
+	}
+
+	// A file without holes is left to the caller.
+	g := writeTempFile(t, data)
+	defer removeTempFile(g)
+	if _, ok, _ := sparseSum64(g, int64(len(data))); ok {
+		t.Fatal("sparseSum64 handled a file without holes")
+	}
+}
@@ -0,0 +1,155 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, b []byte) *os.File {
+	t.Helper()
+	f, err := ioutil.TempFile("", "xxhash-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		t.Fatal(err)
+	}
+	return f
+}
+
+func removeTempFile(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+func TestMapping(t *testing.T) {
+	for _, n := range []int{0, 1, 31, 32, 100, 1 << 20} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			b := make([]byte, n)
+			for i := range b {
+				b[i] = byte(i * 7)
+			}
+			f := writeTempFile(t, b)
+			defer removeTempFile(f)
+			want := Sum64(b)
+
+			// The current file offset (at EOF after writing) should not
+			// matter.
+			got, err := NewMapping(f).Sum64()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("Mapping.Sum64: got 0x%x; want 0x%x", got, want)
+			}
+
+			got, err = readSum64(io.NewSectionReader(f, 0, int64(n)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("read fallback: got 0x%x; want 0x%x", got, want)
+			}
+		})
+	}
+}
+
+// writeSparseFile creates a file of the given size that contains b at each
+// of the given offsets and holes (where the filesystem supports them)
+// everywhere else. It returns the file and its full contents.
+func writeSparseFile(t *testing.T, size int64, b []byte, offsets ...int64) (*os.File, []byte) {
+	t.Helper()
+	f := writeTempFile(t, nil)
+	if err := f.Truncate(size); err != nil {
+		removeTempFile(f)
+		t.Fatal(err)
+	}
+	contents := make([]byte, size)
+	for _, off := range offsets {
+		if _, err := f.WriteAt(b, off); err != nil {
+			removeTempFile(f)
+			t.Fatal(err)
+		}
+		copy(contents[off:], b)
+	}
+	return f, contents
+}
+
+func TestMappingSparse(t *testing.T) {
+	data := bytes.Repeat([]byte("sparse "), 1000)
+	const size = 8 << 20
+	for _, tt := range []struct {
+		name    string
+		offsets []int64
+	}{
+		{"all hole", nil},
+		{"leading data", []int64{0}},
+		{"middle data", []int64{1 << 20, 3<<20 + 5}},
+		{"trailing data", []int64{size - int64(len(data))}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, contents := writeSparseFile(t, size, data, tt.offsets...)
+			defer removeTempFile(f)
+			if _, err := f.Seek(123, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewMapping(f).Sum64()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := Sum64(contents); got != want {
+				t.Fatalf("got 0x%x; want 0x%x", got, want)
+			}
+			off, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if off != 123 {
+				t.Fatalf("file offset changed to %d", off)
+			}
+		})
+	}
+}
+
+func TestSum64File(t *testing.T) {
+	b := bytes.Repeat([]byte("file data "), 1000)
+	f := writeTempFile(t, b)
+	defer removeTempFile(f)
+	got, err := Sum64File(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Sum64(b); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+
+	if _, err := Sum64File(f.Name() + "-missing"); !os.IsNotExist(err) {
+		t.Fatalf("missing file: got err=%v; want a not-exist error", err)
+	}
+}
+
+func TestMappingPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	b := bytes.Repeat([]byte("pipe data "), 1000)
+	go func() {
+		w.Write(b)
+		w.Close()
+	}()
+	got, err := NewMapping(r).Sum64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Sum64(b); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+}
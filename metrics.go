@@ -0,0 +1,77 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"sync/atomic"
+)
+
+// Counters records how much hashing is done through the digests created by
+// its New method: the number of Write and WriteString calls and the number
+// of bytes, per Variant. Hashing through other functions in this package is
+// not counted, so metering is opt-in and costs nothing elsewhere.
+//
+// Counters implements expvar.Var, so it can be published with
+// expvar.Publish. The zero value is ready to use; a Counters must not be
+// copied after first use.
+//
+// The counters are updated with 64-bit atomic operations, which on 386, ARM,
+// and 32-bit MIPS require 64-bit alignment (see the sync/atomic package
+// documentation). A Counters allocated on its own, for example with new or
+// as a global variable, is aligned; one embedded in a struct must be its
+// first field, or otherwise placed at a 64-bit aligned offset.
+type Counters struct {
+	writes [XXH3_128 + 1]uint64
+	bytes  [XXH3_128 + 1]uint64
+}
+
+// New returns a digest that computes v, like NewVariant, and counts its
+// writes in c.
+func (c *Counters) New(v Variant) hash.Hash {
+	return &meteredDigest{VariantDigest: NewVariant(v), c: c}
+}
+
+// Load returns the number of writes and bytes counted for v so far.
+func (c *Counters) Load(v Variant) (writes, bytes uint64) {
+	if !v.valid() {
+		return 0, 0
+	}
+	return atomic.LoadUint64(&c.writes[v]), atomic.LoadUint64(&c.bytes[v])
+}
+
+// String returns the counters as a JSON object keyed by Variant name, with
+// the implementation, writes, and bytes of each, for expvar.
+func (c *Counters) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for v := XXH64; v <= XXH3_128; v++ {
+		if v > XXH64 {
+			buf.WriteString(", ")
+		}
+		writes, n := c.Load(v)
+		fmt.Fprintf(&buf, `%q: {"implementation": %q, "writes": %d, "bytes": %d}`, v, Implementation(v), writes, n)
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+type meteredDigest struct {
+	*VariantDigest
+	c *Counters
+}
+
+func (d *meteredDigest) count(n int) {
+	atomic.AddUint64(&d.c.writes[d.variant], 1)
+	atomic.AddUint64(&d.c.bytes[d.variant], uint64(n))
+}
+
+func (d *meteredDigest) Write(b []byte) (n int, err error) {
+	d.count(len(b))
+	return d.VariantDigest.Write(b)
+}
+
+func (d *meteredDigest) WriteString(s string) (n int, err error) {
+	d.count(len(s))
+	return d.VariantDigest.WriteString(s)
+}
@@ -0,0 +1,53 @@
+package xxhash
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestCounters(t *testing.T) {
+	var c Counters
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d := c.New(XXH64)
+			d.Write([]byte("abc"))
+			io.WriteString(d, "de")
+		}()
+	}
+	d := c.New(XXH3_128)
+	d.Write(make([]byte, 100))
+	wg.Wait()
+
+	for _, tt := range []struct {
+		v             Variant
+		writes, bytes uint64
+	}{
+		{XXH64, 8, 20},
+		{XXH32, 0, 0},
+		{XXH3_128, 1, 100},
+	} {
+		if writes, n := c.Load(tt.v); writes != tt.writes || n != tt.bytes {
+			t.Errorf("%s: got (%d, %d); want (%d, %d)", tt.v, writes, n, tt.writes, tt.bytes)
+		}
+	}
+	if sum, want := d.Sum(nil), SumXXH3_128(make([]byte, 100)).Bytes(); string(sum) != string(want[:]) {
+		t.Errorf("metered digest: got %x; want %x", sum, want)
+	}
+
+	var vars map[string]struct {
+		Implementation string
+		Writes, Bytes  uint64
+	}
+	if err := json.Unmarshal([]byte(c.String()), &vars); err != nil {
+		t.Fatalf("String() = %s: %v", c.String(), err)
+	}
+	got := vars["XXH64"]
+	if got.Implementation != Implementation(XXH64) || got.Writes != 8 || got.Bytes != 20 || len(vars) != 4 {
+		t.Errorf("String() = %s", c.String())
+	}
+}
@@ -0,0 +1,114 @@
+package xxhash
+
+import "math"
+
+// A MinHasher computes b-bit MinHash signatures of sets of tokens, for
+// estimating the Jaccard similarity of the sets (the size of their
+// intersection over the size of their union) from small signatures.
+//
+// A signature has k values. Each token is hashed once with XXH64, and value
+// i of the signature is the low b bits of the minimum, over the tokens, of
+// Mix64(Sum64(token) ^ seed_i), where seed_i = Sum64Uint64(i); since Mix64 is
+// a bijection, each value is the minimum under a different permutation of
+// the digests. Signatures computed by MinHashers with the same k and b are
+// comparable, in any process.
+type MinHasher struct {
+	seeds []uint64
+	bits  uint
+	mask  uint64
+}
+
+// NewMinHasher returns a MinHasher for signatures of k values of b bits each.
+// The estimates' standard error is about 1/sqrt(k); small b saves space at
+// the cost of accidental matches, which Similarity corrects for. It panics
+// unless k > 0 and 1 <= b <= 64.
+func NewMinHasher(k, b int) *MinHasher {
+	if k <= 0 || b < 1 || b > 64 {
+		panic("xxhash: invalid MinHasher parameters")
+	}
+	m := &MinHasher{seeds: make([]uint64, k), bits: uint(b), mask: 1<<uint(b) - 1}
+	if b == 64 {
+		m.mask = math.MaxUint64
+	}
+	for i := range m.seeds {
+		m.seeds[i] = Sum64Uint64(uint64(i))
+	}
+	return m
+}
+
+// Signature returns the signature of the set of tokens.
+func (m *MinHasher) Signature(tokens []string) []uint64 {
+	s := m.NewSketch()
+	for _, t := range tokens {
+		s.AddString(t)
+	}
+	return s.Signature()
+}
+
+// Similarity returns the estimated Jaccard similarity, in [0, 1], of the sets
+// with signatures a and b. For b-bit signatures, it discounts the fraction
+// 2^-b of values expected to match by accident. It panics if a or b is not a
+// signature of m's length.
+func (m *MinHasher) Similarity(a, b []uint64) float64 {
+	if len(a) != len(m.seeds) || len(b) != len(m.seeds) {
+		panic("xxhash: MinHash signature length mismatch")
+	}
+	var eq int
+	for i := range a {
+		if a[i] == b[i] {
+			eq++
+		}
+	}
+	e := float64(eq) / float64(len(a))
+	c := math.Ldexp(1, -int(m.bits))
+	j := (e - c) / (1 - c)
+	if j < 0 {
+		return 0
+	}
+	return j
+}
+
+// NewSketch returns an empty MinHashSketch for accumulating a signature from
+// a stream of tokens.
+func (m *MinHasher) NewSketch() *MinHashSketch {
+	s := &MinHashSketch{m: m, mins: make([]uint64, len(m.seeds))}
+	s.Reset()
+	return s
+}
+
+// A MinHashSketch accumulates the MinHash signature of a stream of tokens.
+// Adding a token more than once has no effect.
+type MinHashSketch struct {
+	m    *MinHasher
+	mins []uint64
+}
+
+// Add adds token to the set.
+func (s *MinHashSketch) Add(token []byte) { s.addHash(Sum64(token)) }
+
+// AddString adds token to the set.
+func (s *MinHashSketch) AddString(token string) { s.addHash(Sum64String(token)) }
+
+func (s *MinHashSketch) addHash(h uint64) {
+	for i, seed := range s.m.seeds {
+		if v := avalanche(h ^ seed); v < s.mins[i] {
+			s.mins[i] = v
+		}
+	}
+}
+
+// Reset empties the set.
+func (s *MinHashSketch) Reset() {
+	for i := range s.mins {
+		s.mins[i] = math.MaxUint64
+	}
+}
+
+// Signature returns the signature of the tokens added so far.
+func (s *MinHashSketch) Signature() []uint64 {
+	sig := make([]uint64, len(s.mins))
+	for i, v := range s.mins {
+		sig[i] = v & s.m.mask
+	}
+	return sig
+}
@@ -0,0 +1,85 @@
+package xxhash
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestMinHasherSignature(t *testing.T) {
+	m := NewMinHasher(4, 64)
+	tokens := []string{"a", "b", "c"}
+	sig := m.Signature(tokens)
+	for i := range sig {
+		want := uint64(math.MaxUint64)
+		for _, tok := range tokens {
+			if v := Mix64(Sum64String(tok) ^ Sum64Uint64(uint64(i))); v < want {
+				want = v
+			}
+		}
+		if sig[i] != want {
+			t.Errorf("value %d: got 0x%x; want 0x%x", i, sig[i], want)
+		}
+	}
+
+	// Order and duplicates do not matter, and sketches agree with Signature.
+	s := m.NewSketch()
+	for _, tok := range []string{"c", "a", "b", "a"} {
+		s.Add([]byte(tok))
+	}
+	if fmt.Sprint(s.Signature()) != fmt.Sprint(sig) {
+		t.Errorf("sketch signature %x; want %x", s.Signature(), sig)
+	}
+
+	b8 := NewMinHasher(4, 8).Signature(tokens)
+	for i := range b8 {
+		if b8[i] != sig[i]&0xff {
+			t.Errorf("8-bit value %d: got 0x%x; want 0x%x", i, b8[i], sig[i]&0xff)
+		}
+	}
+}
+
+func TestMinHasherSimilarity(t *testing.T) {
+	// Sets of 1000 tokens overlapping in 600 have Jaccard similarity
+	// 600/1400.
+	set := func(lo, hi int) []string {
+		var tokens []string
+		for i := lo; i < hi; i++ {
+			tokens = append(tokens, fmt.Sprint("token", i))
+		}
+		return tokens
+	}
+	a, b := set(0, 1000), set(400, 1400)
+	const want = 600.0 / 1400
+	for _, bits := range []int{1, 4, 64} {
+		m := NewMinHasher(1024, bits)
+		sa, sb := m.Signature(a), m.Signature(b)
+		if got := m.Similarity(sa, sb); math.Abs(got-want) > 0.08 {
+			t.Errorf("b=%d: Similarity = %.3f; want about %.3f", bits, got, want)
+		}
+		if got := m.Similarity(sa, sa); got != 1 {
+			t.Errorf("b=%d: self-similarity = %v; want 1", bits, got)
+		}
+		if got := m.Similarity(sa, m.Signature(set(5000, 6000))); got > 0.1 {
+			t.Errorf("b=%d: similarity of disjoint sets = %.3f; want about 0", bits, got)
+		}
+	}
+}
+
+func TestMinHasherPanics(t *testing.T) {
+	for _, fn := range []func(){
+		func() { NewMinHasher(0, 8) },
+		func() { NewMinHasher(8, 0) },
+		func() { NewMinHasher(8, 65) },
+		func() { m := NewMinHasher(2, 8); m.Similarity(make([]uint64, 2), make([]uint64, 3)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,104 @@
+package xxhash
+
+import "hash"
+
+// A MultiDigest writes everything written to it to each of several digests,
+// such as Digests with different seeds or digests of different algorithms,
+// so that they can all be computed in one pass over the input. Unlike
+// io.MultiWriter, it also has a Reset method.
+//
+// The digests are computed independently; read their results from the digests
+// themselves.
+type MultiDigest struct {
+	ds []hash.Hash
+}
+
+// NewMultiDigest creates a MultiDigest that writes to ds.
+func NewMultiDigest(ds ...hash.Hash) *MultiDigest {
+	return &MultiDigest{ds: append([]hash.Hash(nil), ds...)}
+}
+
+// Write adds more data to each digest. It always returns len(b), nil.
+func (m *MultiDigest) Write(b []byte) (n int, err error) {
+	for _, d := range m.ds {
+		// hash.Hash's Write never returns an error.
+		d.Write(b)
+	}
+	return len(b), nil
+}
+
+// WriteString adds more data to each digest. It always returns len(s), nil.
+func (m *MultiDigest) WriteString(s string) (n int, err error) {
+	return m.Write([]byte(s))
+}
+
+// Reset calls the Reset method of each digest. Note that Digest.Reset uses a
+// seed of zero; use ResetWithSeed directly on Digests that need another
+// seed.
+func (m *MultiDigest) Reset() {
+	for _, d := range m.ds {
+		d.Reset()
+	}
+}
+
+// Checksums holds the XXH32, XXH64, and 128-bit XXH3 digests of the same
+// data, each with a seed of zero.
+type Checksums struct {
+	XXH32    uint32
+	XXH64    uint64
+	XXH3_128 Uint128
+}
+
+// SumChecksums computes the XXH32, XXH64, and 128-bit XXH3 digests of b.
+func SumChecksums(b []byte) Checksums {
+	return Checksums{
+		XXH32:    Sum32(b),
+		XXH64:    Sum64(b),
+		XXH3_128: SumXXH3_128(b),
+	}
+}
+
+// A ChecksumsDigest computes the XXH32, XXH64, and 128-bit XXH3 digests of
+// the data written to it, so that all three are obtained by reading the data
+// once.
+type ChecksumsDigest struct {
+	d32 Digest32
+	d64 Digest
+	d3  DigestXXH3
+}
+
+// NewChecksums creates a new ChecksumsDigest.
+func NewChecksums() *ChecksumsDigest {
+	d := new(ChecksumsDigest)
+	d.Reset()
+	return d
+}
+
+// Reset clears d's state so that it can be reused.
+func (d *ChecksumsDigest) Reset() {
+	d.d32.Reset()
+	d.d64.Reset()
+	d.d3.Reset()
+}
+
+// Write adds more data to d. It always returns len(b), nil.
+func (d *ChecksumsDigest) Write(b []byte) (n int, err error) {
+	d.d32.Write(b)
+	d.d64.Write(b)
+	d.d3.Write(b)
+	return len(b), nil
+}
+
+// WriteString adds more data to d. It always returns len(s), nil.
+func (d *ChecksumsDigest) WriteString(s string) (n int, err error) {
+	return d.Write([]byte(s))
+}
+
+// Checksums returns the digests of the data written so far.
+func (d *ChecksumsDigest) Checksums() Checksums {
+	return Checksums{
+		XXH32:    d.d32.Sum32(),
+		XXH64:    d.d64.Sum64(),
+		XXH3_128: d.d3.Sum128(),
+	}
+}
@@ -0,0 +1,69 @@
+package xxhash
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestMultiDigest(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 13)
+	}
+	d1 := NewWithSeed(1)
+	d2 := NewWithSeed(2)
+	d32 := New32()
+	d3 := NewXXH3()
+	m := NewMultiDigest(d1, d2, d32, d3)
+	check := func(data []byte) {
+		t.Helper()
+		if got, want := d1.Sum64(), Sum64WithSeed(data, 1); got != want {
+			t.Errorf("seed 1: got 0x%x; want 0x%x", got, want)
+		}
+		if got, want := d2.Sum64(), Sum64WithSeed(data, 2); got != want {
+			t.Errorf("seed 2: got 0x%x; want 0x%x", got, want)
+		}
+		if got, want := d32.Sum32(), Sum32(data); got != want {
+			t.Errorf("XXH32: got 0x%x; want 0x%x", got, want)
+		}
+		if got, want := d3.Sum64(), SumXXH3_64(data); got != want {
+			t.Errorf("XXH3: got 0x%x; want 0x%x", got, want)
+		}
+	}
+	if _, err := io.Copy(m, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	check(data)
+
+	m.Reset()
+	d1.ResetWithSeed(1)
+	d2.ResetWithSeed(2)
+	m.WriteString("abc")
+	check([]byte("abc"))
+}
+
+func TestChecksums(t *testing.T) {
+	data := make([]byte, 5000)
+	for i := range data {
+		data[i] = byte(i * 13)
+	}
+	for _, n := range []int{0, 1, 16, 100, 240, 241, 1024, len(data)} {
+		b := data[:n]
+		want := Checksums{Sum32(b), Sum64(b), SumXXH3_128(b)}
+		if got := SumChecksums(b); got != want {
+			t.Fatalf("n=%d: SumChecksums = %+v; want %+v", n, got, want)
+		}
+		d := NewChecksums()
+		io.Copy(d, iotest.HalfReader(bytes.NewReader(b)))
+		if got := d.Checksums(); got != want {
+			t.Fatalf("n=%d: ChecksumsDigest: got %+v; want %+v", n, got, want)
+		}
+		d.Reset()
+		d.WriteString(string(b))
+		if got := d.Checksums(); got != want {
+			t.Fatalf("n=%d: after Reset: got %+v; want %+v", n, got, want)
+		}
+	}
+}
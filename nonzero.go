@@ -0,0 +1,26 @@
+package xxhash
+
+// NonZeroFallback is the value that NonZero substitutes for a zero digest.
+//
+// It is the XXH64 constant prime5: an arbitrary odd value that is distinct
+// from the sentinels callers commonly reserve (0, 1, and ^uint64(0)). It will
+// not change between releases.
+const NonZeroFallback uint64 = prime5
+
+// NonZero returns h unless h is 0, in which case it returns NonZeroFallback.
+//
+// This allows hash tables that use 0 to mark empty slots to store digests
+// without a special case. The mapping is deterministic; the only cost is that
+// a digest of 0 becomes indistinguishable from a digest of NonZeroFallback.
+func NonZero(h uint64) uint64 {
+	if h == 0 {
+		return NonZeroFallback
+	}
+	return h
+}
+
+// Sum64NonZero computes the 64-bit xxHash digest of b and passes it through
+// NonZero, so the result is never 0.
+func Sum64NonZero(b []byte) uint64 {
+	return NonZero(Sum64(b))
+}
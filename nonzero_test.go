@@ -0,0 +1,29 @@
+package xxhash
+
+import "testing"
+
+func TestNonZero(t *testing.T) {
+	if got := NonZero(0); got != NonZeroFallback {
+		t.Fatalf("NonZero(0): got 0x%x; want 0x%x", got, NonZeroFallback)
+	}
+	for _, h := range []uint64{1, 2, NonZeroFallback, 0xef46db3751d8e999, ^uint64(0)} {
+		if got := NonZero(h); got != h {
+			t.Errorf("NonZero(0x%x): got 0x%x; want it unchanged", h, got)
+		}
+	}
+	if NonZeroFallback == 0 || NonZeroFallback == 1 || NonZeroFallback == ^uint64(0) {
+		t.Errorf("NonZeroFallback is a reserved value (0x%x)", NonZeroFallback)
+	}
+}
+
+func TestSum64NonZero(t *testing.T) {
+	for _, s := range []string{"", "a", "asdf", "Call me Ishmael."} {
+		b := []byte(s)
+		if got, want := Sum64NonZero(b), Sum64(b); got != want {
+			t.Errorf("Sum64NonZero(%q): got 0x%x; want 0x%x", s, got, want)
+		}
+		if got0, got1 := Sum64NonZero(b), Sum64NonZero(b); got0 != got1 {
+			t.Errorf("Sum64NonZero(%q) is not stable: 0x%x != 0x%x", s, got0, got1)
+		}
+	}
+}
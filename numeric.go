@@ -0,0 +1,77 @@
+//go:build go1.18
+// +build go1.18
+
+package xxhash
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Sum64Ints computes the XXH64 digest of s encoded as consecutive
+// little-endian integers, each as wide as T. The result equals Sum64 of that
+// encoding, so it is the same on every platform. (The platform-sized int,
+// uint, and uintptr types are not allowed for that reason.)
+func Sum64Ints[T ~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64](s []T) uint64 {
+	// Multiplying 1 by 256 until it overflows to 0 takes one step per byte
+	// of T.
+	size := 0
+	for x := T(1); x != 0; x = x * 16 * 16 {
+		size++
+	}
+	var d Digest
+	d.Reset()
+	var buf [256]byte
+	for len(s) > 0 {
+		n := 0
+		for n+size <= len(buf) && len(s) > 0 {
+			putUint(buf[n:], uint64(s[0]), size)
+			s = s[1:]
+			n += size
+		}
+		d.Write(buf[:n])
+	}
+	return d.Sum64()
+}
+
+// Sum64Floats computes the XXH64 digest of s encoded as consecutive
+// little-endian IEEE 754 values, each as wide as T. The bits are hashed as
+// they are, so 0 and -0 hash differently, and NaNs hash according to their
+// bit patterns.
+func Sum64Floats[T ~float32 | ~float64](s []T) uint64 {
+	size := 8
+	if float64(T(0.1)) != 0.1 { // 0.1 rounds differently as a float32
+		size = 4
+	}
+	var d Digest
+	d.Reset()
+	var buf [256]byte
+	for len(s) > 0 {
+		n := 0
+		for n+size <= len(buf) && len(s) > 0 {
+			if size == 4 {
+				binary.LittleEndian.PutUint32(buf[n:], math.Float32bits(float32(s[0])))
+			} else {
+				binary.LittleEndian.PutUint64(buf[n:], math.Float64bits(float64(s[0])))
+			}
+			s = s[1:]
+			n += size
+		}
+		d.Write(buf[:n])
+	}
+	return d.Sum64()
+}
+
+// putUint writes the low size bytes of v to b in little-endian order.
+func putUint(b []byte, v uint64, size int) {
+	switch size {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(b, uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(b, uint32(v))
+	default:
+		binary.LittleEndian.PutUint64(b, v)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build go1.18
+// +build go1.18
+
+package xxhash
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestSum64Ints(t *testing.T) {
+	const n = 100 // spans several internal buffers
+	var (
+		i8  []int8
+		u16 []uint16
+		i32 []int32
+		u64 []uint64
+	)
+	var b8, b16, b32, b64 []byte
+	for i := 0; i < n; i++ {
+		v := uint64(i) * prime1
+		i8 = append(i8, int8(v))
+		b8 = append(b8, byte(v))
+		u16 = append(u16, uint16(v))
+		b16 = append(b16, byte(v), byte(v>>8))
+		i32 = append(i32, int32(v))
+		b32 = appendUint32(b32, uint32(v))
+		u64 = append(u64, v)
+		b64 = appendUint64(b64, v)
+	}
+	if got, want := Sum64Ints(i8), Sum64(b8); got != want {
+		t.Errorf("int8: got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := Sum64Ints(u16), Sum64(b16); got != want {
+		t.Errorf("uint16: got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := Sum64Ints(i32), Sum64(b32); got != want {
+		t.Errorf("int32: got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := Sum64Ints(u64), Sum64(b64); got != want {
+		t.Errorf("uint64: got 0x%x; want 0x%x", got, want)
+	}
+	type id int64
+	if got, want := Sum64Ints([]id{-1, 2}), Sum64Ints([]int64{-1, 2}); got != want {
+		t.Errorf("named type: got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := Sum64Ints([]uint32(nil)), Sum64(nil); got != want {
+		t.Errorf("empty: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestSum64Floats(t *testing.T) {
+	var f32 []float32
+	var f64 []float64
+	var b32, b64 []byte
+	for i := 0; i < 100; i++ {
+		v := float64(i) * 1.25
+		f32 = append(f32, float32(v))
+		b32 = appendUint32(b32, math.Float32bits(float32(v)))
+		f64 = append(f64, v)
+		b64 = appendUint64(b64, math.Float64bits(v))
+	}
+	if got, want := Sum64Floats(f32), Sum64(b32); got != want {
+		t.Errorf("float32: got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := Sum64Floats(f64), Sum64(b64); got != want {
+		t.Errorf("float64: got 0x%x; want 0x%x", got, want)
+	}
+	if Sum64Floats([]float64{0}) == Sum64Floats([]float64{math.Copysign(0, -1)}) {
+		t.Error("0 and -0 hash identically")
+	}
+}
+
+func appendUint32(b []byte, x uint32) []byte {
+	var a [4]byte
+	binary.LittleEndian.PutUint32(a[:], x)
+	return append(b, a[:]...)
+}
@@ -0,0 +1,279 @@
+package xxhash
+
+import (
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Object computes a deterministic 64-bit hash of v by walking it with
+// reflection. It is the same as (&ObjectHasher{}).Hash(v).
+func Object(v interface{}) uint64 {
+	var h ObjectHasher
+	return h.Hash(v)
+}
+
+// An ObjectHasher computes deterministic hashes of Go values, such as
+// configuration structs, by walking them with reflection. The hash of a value
+// depends only on its contents, not on memory addresses, map iteration order,
+// or the platform, so it is stable across runs and can be persisted, for
+// example to detect configuration drift or to key a memoization cache.
+//
+// Values are hashed as follows:
+//
+//   - Integers of any size hash as their int64 or uint64 value, and floats
+//     and complex numbers as float64 components, with -0 hashed as 0 and all
+//     NaNs hashed alike.
+//   - Strings, slices, and arrays hash their length and elements. A nil slice
+//     hashes like an empty one.
+//   - Maps hash their length and entries in an order-insensitive way.
+//   - Pointers hash whether they are nil and what they point to.
+//   - A pointer, map, or slice that leads back to a value that is already
+//     being hashed (a cycle) hashes as a reference to that value rather than
+//     being followed again.
+//   - Interfaces hash the name of their dynamic type and its value.
+//   - Structs hash the names and values of their fields, in order.
+//
+// The hashing of struct fields can be customized with struct tags. A field
+// tagged xxhash:"-" is ignored, and a field tagged xxhash:"name" is hashed
+// under that name instead of its own, so that it can be renamed in Go without
+// changing hashes:
+//
+//	type Row struct {
+//		ID        int
+//		Email     string    `xxhash:"email"`
+//		UpdatedAt time.Time `xxhash:"-"`
+//	}
+//
+// Each value is tagged with its kind, so that, for example, a string never
+// hashes like an integer. Object panics on channels, functions, and unsafe
+// pointers, which have no stable contents.
+//
+// The zero ObjectHasher is ready to use.
+type ObjectHasher struct {
+	// SkipUnexported makes the hasher ignore unexported struct fields.
+	SkipUnexported bool
+}
+
+// Hash returns the hash of v.
+func (h *ObjectHasher) Hash(v interface{}) uint64 {
+	w := objectWalker{h: h}
+	w.d.Reset()
+	w.value(&w.d, reflect.ValueOf(v))
+	return w.d.Sum64()
+}
+
+// Kind tags written before every value.
+const (
+	objNil byte = iota
+	objBool
+	objInt
+	objUint
+	objFloat
+	objComplex
+	objString
+	objSlice
+	objMap
+	objPtr
+	objCycle
+	objInterface
+	objStruct
+)
+
+type objectWalker struct {
+	h    *ObjectHasher
+	d    Digest
+	path []objectRef // pointers, maps, and slices being followed, to detect cycles
+}
+
+// An objectRef identifies a pointed-to value, map, or slice. The type is
+// needed because a struct and its first field have the same address, and the
+// length because a slice and a shorter slice of it do.
+type objectRef struct {
+	p uintptr
+	t reflect.Type
+	n int
+}
+
+// push adds r to the path and reports whether it was not already on it. If
+// it was, push writes a reference to the earlier occurrence to d instead, and
+// the caller must not follow r.
+func (w *objectWalker) push(d *Digest, r objectRef) bool {
+	for i, q := range w.path {
+		if q == r {
+			d.WriteByte(objCycle)
+			d.WriteUint64(uint64(len(w.path) - i))
+			return false
+		}
+	}
+	w.path = append(w.path, r)
+	return true
+}
+
+func (w *objectWalker) pop() { w.path = w.path[:len(w.path)-1] }
+
+func (w *objectWalker) value(d *Digest, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		d.WriteByte(objNil)
+	case reflect.Bool:
+		d.WriteByte(objBool)
+		if v.Bool() {
+			d.WriteByte(1)
+		} else {
+			d.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.WriteByte(objInt)
+		d.WriteUint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.WriteByte(objUint)
+		d.WriteUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		d.WriteByte(objFloat)
+		writeObjectFloat(d, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		d.WriteByte(objComplex)
+		c := v.Complex()
+		writeObjectFloat(d, real(c))
+		writeObjectFloat(d, imag(c))
+	case reflect.String:
+		d.WriteByte(objString)
+		s := v.String()
+		d.WriteUint64(uint64(len(s)))
+		d.WriteString(s)
+	case reflect.Slice:
+		if v.Cap() == 0 {
+			w.elems(d, v)
+			return
+		}
+		if w.push(d, objectRef{v.Pointer(), v.Type(), v.Len()}) {
+			w.elems(d, v)
+			w.pop()
+		}
+	case reflect.Array:
+		w.elems(d, v)
+	case reflect.Map:
+		if v.IsNil() {
+			w.entries(d, v)
+			return
+		}
+		if w.push(d, objectRef{v.Pointer(), v.Type(), 0}) {
+			w.entries(d, v)
+			w.pop()
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			d.WriteByte(objPtr)
+			d.WriteByte(0)
+			return
+		}
+		if w.push(d, objectRef{v.Pointer(), v.Type(), 0}) {
+			d.WriteByte(objPtr)
+			d.WriteByte(1)
+			w.value(d, v.Elem())
+			w.pop()
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			d.WriteByte(objNil)
+			return
+		}
+		e := v.Elem()
+		d.WriteByte(objInterface)
+		name := e.Type().String()
+		d.WriteUint64(uint64(len(name)))
+		d.WriteString(name)
+		w.value(d, e)
+	case reflect.Struct:
+		d.WriteByte(objStruct)
+		fields := w.h.structFields(v.Type())
+		d.WriteUint64(uint64(len(fields)))
+		for _, f := range fields {
+			d.WriteUint64(uint64(len(f.name)))
+			d.WriteString(f.name)
+			w.value(d, v.Field(f.index))
+		}
+	default:
+		panic("xxhash: cannot hash value of type " + v.Type().String())
+	}
+}
+
+// elems writes the slice or array v to d.
+func (w *objectWalker) elems(d *Digest, v reflect.Value) {
+	d.WriteByte(objSlice)
+	d.WriteUint64(uint64(v.Len()))
+	for i := 0; i < v.Len(); i++ {
+		w.value(d, v.Index(i))
+	}
+}
+
+// entries writes the map v to d. The entry hashes are combined with
+// addition, which doesn't depend on their order.
+func (w *objectWalker) entries(d *Digest, v reflect.Value) {
+	d.WriteByte(objMap)
+	d.WriteUint64(uint64(v.Len()))
+	var sum uint64
+	for _, k := range v.MapKeys() {
+		var e Digest
+		e.Reset()
+		w.value(&e, k)
+		w.value(&e, v.MapIndex(k))
+		sum += e.Sum64()
+	}
+	d.WriteUint64(sum)
+}
+
+// An objectField is a struct field included in struct hashes.
+type objectField struct {
+	index int
+	name  string // the field name, or the name from its tag
+}
+
+// objectFields and objectExportedFields hold the []objectField of each
+// struct type that has been hashed, keyed by type, without and with
+// SkipUnexported respectively.
+var (
+	objectFields         sync.Map
+	objectExportedFields sync.Map
+)
+
+// structFields returns the fields of the struct type t that h hashes.
+func (h *ObjectHasher) structFields(t reflect.Type) []objectField {
+	cache := &objectFields
+	if h.SkipUnexported {
+		cache = &objectExportedFields
+	}
+	if fields, ok := cache.Load(t); ok {
+		return fields.([]objectField)
+	}
+	var fields []objectField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if h.SkipUnexported && f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		switch tag := f.Tag.Get("xxhash"); tag {
+		case "-":
+			continue
+		case "":
+		default:
+			name = tag
+		}
+		fields = append(fields, objectField{i, name})
+	}
+	cache.Store(t, fields)
+	return fields
+}
+
+// writeObjectFloat writes f to d, writing -0 as 0 and all NaNs alike.
+func writeObjectFloat(d *Digest, f float64) {
+	switch {
+	case f == 0:
+		f = 0
+	case f != f:
+		f = math.NaN()
+	}
+	d.WriteUint64(math.Float64bits(f))
+}
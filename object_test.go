@@ -0,0 +1,263 @@
+package xxhash
+
+import (
+	"math"
+	"testing"
+)
+
+type objInner struct {
+	A int
+	B string
+}
+
+type objConfig struct {
+	Name    string
+	Port    int
+	Ratio   float64
+	Tags    []string
+	Limits  map[string]int
+	Inner   *objInner
+	Extra   interface{}
+	private int
+}
+
+func TestObjectEqual(t *testing.T) {
+	a := objConfig{
+		Name:   "svc",
+		Port:   8080,
+		Tags:   []string{"a", "b"},
+		Limits: map[string]int{},
+		Inner:  &objInner{1, "x"},
+		Extra:  []int{1, 2},
+	}
+	b := a
+	b.Inner = &objInner{1, "x"} // a different pointer to an equal value
+	b.Limits = map[string]int{}
+	for i := 0; i < 100; i++ {
+		a.Limits[string(rune('a'+i%26))+string(rune('a'+i/26))] = i
+	}
+	for i := 99; i >= 0; i-- {
+		b.Limits[string(rune('a'+i%26))+string(rune('a'+i/26))] = i
+	}
+	if Object(a) != Object(b) {
+		t.Error("equal configs hash differently")
+	}
+	if Object(&a) != Object(&b) {
+		t.Error("pointers to equal configs hash differently")
+	}
+
+	for _, pair := range [][2]interface{}{
+		{int8(-3), int64(-3)},
+		{uint16(7), uint(7)},
+		{float32(1.5), 1.5},
+		{0.0, math.Copysign(0, -1)},
+		{math.NaN(), -math.NaN()},
+		{[]int(nil), []int{}},
+		{[2]int{1, 2}, []int{1, 2}},
+	} {
+		if Object(pair[0]) != Object(pair[1]) {
+			t.Errorf("%#v and %#v hash differently", pair[0], pair[1])
+		}
+	}
+}
+
+func TestObjectDistinct(t *testing.T) {
+	type x struct{ A int }
+	type xy struct {
+		A int
+		B int
+	}
+	type outer1 struct {
+		X x
+		B int
+	}
+	type outer2 struct {
+		X xy
+	}
+	type renamed struct{ C int }
+	vals := []interface{}{
+		nil,
+		0,
+		uint(0),
+		"",
+		"0",
+		false,
+		[]int{},
+		[]int{0},
+		[]string{"ab", "c"},
+		[]string{"a", "bc"},
+		map[int]int{1: 2},
+		map[int]int{2: 1},
+		x{1},
+		renamed{1},
+		outer1{x{1}, 2},
+		outer2{xy{1, 2}},
+		(*int)(nil),
+		new(int),
+		[]interface{}{1},
+		[]interface{}{int8(1)},
+		[]interface{}{nil},
+	}
+	seen := make(map[uint64]int)
+	for i, v := range vals {
+		h := Object(v)
+		if j, ok := seen[h]; ok {
+			t.Errorf("%#v and %#v hash identically", vals[j], v)
+		}
+		seen[h] = i
+	}
+}
+
+func TestObjectSkipUnexported(t *testing.T) {
+	a := objConfig{Name: "svc", private: 1}
+	b := objConfig{Name: "svc", private: 2}
+	if Object(a) == Object(b) {
+		t.Error("unexported field ignored by default")
+	}
+	h := ObjectHasher{SkipUnexported: true}
+	if h.Hash(a) != h.Hash(b) {
+		t.Error("unexported field hashed with SkipUnexported")
+	}
+	if h.Hash(a) == Object(a) {
+		t.Error("SkipUnexported didn't change the hash")
+	}
+}
+
+type objNode struct {
+	V    int
+	Next *objNode
+}
+
+func TestObjectCycle(t *testing.T) {
+	ring := func(vals ...int) *objNode {
+		first := &objNode{V: vals[0]}
+		n := first
+		for _, v := range vals[1:] {
+			n.Next = &objNode{V: v}
+			n = n.Next
+		}
+		n.Next = first
+		return first
+	}
+	if Object(ring(1, 2, 3)) != Object(ring(1, 2, 3)) {
+		t.Error("equal rings hash differently")
+	}
+	if Object(ring(1, 2, 3)) == Object(ring(1, 2, 4)) {
+		t.Error("different rings hash identically")
+	}
+	if Object(ring(1, 2)) == Object(ring(1, 2, 1, 2)) {
+		t.Error("rings of different lengths hash identically")
+	}
+
+	// Cycles through maps and slices.
+	m := map[string]interface{}{"a": 1}
+	m["self"] = m
+	m2 := map[string]interface{}{"a": 1}
+	m2["self"] = m2
+	if Object(m) != Object(m2) {
+		t.Error("equal self-referencing maps hash differently")
+	}
+	m2["a"] = 2
+	if Object(m) == Object(m2) {
+		t.Error("different self-referencing maps hash identically")
+	}
+	s := []interface{}{1, nil}
+	s[1] = s
+	s2 := []interface{}{1, nil}
+	s2[1] = s2
+	if Object(s) != Object(s2) {
+		t.Error("equal self-referencing slices hash differently")
+	}
+	// A shorter slice of the same array is a different value, so it is
+	// followed; the cycles through both slices still end the walk.
+	s3 := make([]interface{}, 2, 3)
+	s3[0] = s3[:1]
+	s3[1] = s3
+	Object(s3)
+
+	// A struct and its first field share an address; that is not a cycle.
+	type wrapper struct{ Inner objInner }
+	w := &wrapper{}
+	v := []interface{}{w, &w.Inner}
+	u := []interface{}{&wrapper{}, &objInner{}}
+	if Object(v) != Object(u) {
+		t.Error("pointer to first field was treated as a cycle")
+	}
+}
+
+func TestObjectPanics(t *testing.T) {
+	for _, v := range []interface{}{
+		func() {},
+		make(chan int),
+		struct{ F func() }{},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic hashing %T", v)
+				}
+			}()
+			Object(v)
+		}()
+	}
+}
+
+func TestObjectGolden(t *testing.T) {
+	// Pin the encoding so that hashes stay stable across releases.
+	v := objConfig{
+		Name:   "svc",
+		Port:   8080,
+		Ratio:  0.5,
+		Tags:   []string{"a"},
+		Limits: map[string]int{"cpu": 2, "mem": 4},
+		Inner:  &objInner{1, "x"},
+		Extra:  uint8(3),
+	}
+	const want uint64 = 0xaa7fa94e2d93f825
+	if got := Object(v); got != want {
+		t.Fatalf("got 0x%016x; want 0x%016x", got, want)
+	}
+}
+
+func TestObjectTags(t *testing.T) {
+	type row struct {
+		ID      int
+		Email   string `xxhash:"email"`
+		Updated int64  `xxhash:"-"`
+		Note    string `json:"note"`
+	}
+	type renamed struct {
+		ID    int
+		email string
+		Note  string
+	}
+	a := row{ID: 1, Email: "a@example.com", Updated: 100, Note: "n"}
+	b := a
+	b.Updated = 200
+	if Object(a) != Object(b) {
+		t.Error(`field tagged xxhash:"-" was hashed`)
+	}
+	if got, want := Object(a), Object(renamed{1, "a@example.com", "n"}); got != want {
+		t.Errorf("renamed field: got 0x%x; want 0x%x", got, want)
+	}
+	b.Email = "b@example.com"
+	if Object(a) == Object(b) {
+		t.Error("renamed field was not hashed")
+	}
+}
+
+func TestObjectHasherAllocs(t *testing.T) {
+	// After the first hash of a type, hashing a struct of scalars
+	// shouldn't allocate. (Following pointers does.)
+	type row struct {
+		ID   int
+		Name string
+		Skip bool `xxhash:"-"`
+	}
+	var h ObjectHasher
+	var r interface{} = row{1, "x", true}
+	h.Hash(r)
+	testAllocs(t, func() {
+		sink = h.Hash(r)
+	})
+}
@@ -0,0 +1,53 @@
+package xxhash
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelChunkSize is the chunk size of Sum64Parallel. It is part of the
+// definition of the result and must not change.
+const parallelChunkSize = 1 << 20
+
+// Sum64Parallel computes a tree hash of b using up to workers goroutines,
+// for large in-memory buffers where hashing on one core cannot keep up with
+// memory bandwidth. If workers <= 0, it uses GOMAXPROCS.
+//
+// The result is not the XXH64 digest of b. It is SumTree64 of b with 1 MiB
+// chunks, that is, SumTree64(bytes.NewReader(b), int64(len(b)), 1<<20), so
+// it does not depend on workers and can be recomputed from a file or
+// stream.
+func Sum64Parallel(b []byte, workers int) uint64 {
+	nchunks := (len(b) + parallelChunkSize - 1) / parallelChunkSize
+	leaves := make([]uint64, nchunks)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > nchunks {
+		workers = nchunks
+	}
+	var (
+		next int64 = -1
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= nchunks {
+					return
+				}
+				chunk := b[i*parallelChunkSize:]
+				if len(chunk) > parallelChunkSize {
+					chunk = chunk[:parallelChunkSize]
+				}
+				leaves[i] = Sum64(chunk)
+			}
+		}()
+	}
+	wg.Wait()
+	return treeRoot(parallelChunkSize, int64(len(b)), leaves)
+}
@@ -0,0 +1,31 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSum64Parallel(t *testing.T) {
+	data := make([]byte, 3*parallelChunkSize+100)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, n := range []int{0, 1, parallelChunkSize - 1, parallelChunkSize, parallelChunkSize + 1, len(data)} {
+		want := treeSum64(data[:n], parallelChunkSize)
+		tree, err := SumTree64(bytes.NewReader(data[:n]), int64(n), parallelChunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree != want {
+			t.Fatalf("n=%d: SumTree64 = 0x%x; want 0x%x", n, tree, want)
+		}
+		for _, workers := range []int{-1, 0, 1, 2, 8} {
+			t.Run(fmt.Sprintf("n=%d,workers=%d", n, workers), func(t *testing.T) {
+				if got := Sum64Parallel(data[:n], workers); got != want {
+					t.Fatalf("got 0x%x; want 0x%x", got, want)
+				}
+			})
+		}
+	}
+}
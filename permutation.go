@@ -0,0 +1,49 @@
+package xxhash
+
+import "math/bits"
+
+// Permutation returns a pseudo-random permutation of the integers [0, n)
+// determined entirely by seed. A typical seed is the Sum64 of some key.
+//
+// The result for a given (seed, n) pair is deterministic and will not change
+// between releases, so it may be used for reproducible orderings such as
+// consistent load distribution. The sequence is generated by passing a
+// counter through the XXH64 avalanche function; it is not cryptographically
+// unpredictable.
+func Permutation(seed uint64, n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	r := mixRand{state: seed}
+	// Fisher-Yates shuffle.
+	for i := n - 1; i > 0; i-- {
+		j := r.uint64n(uint64(i) + 1)
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+// mixRand is a simple counter-based generator: each output is the avalanche
+// of a Weyl sequence with an odd increment.
+type mixRand struct {
+	state uint64
+}
+
+func (r *mixRand) next() uint64 {
+	r.state += prime1
+	return avalanche(r.state)
+}
+
+// uint64n returns a uniformly distributed value in [0, n) using Lemire's
+// multiply-and-reject method. n must be nonzero.
+func (r *mixRand) uint64n(n uint64) uint64 {
+	hi, lo := bits.Mul64(r.next(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(r.next(), n)
+		}
+	}
+	return hi
+}
@@ -0,0 +1,64 @@
+package xxhash
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPermutation(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		p := Permutation(Sum64String("key"), n)
+		if len(p) != n {
+			t.Fatalf("n=%d: got length %d", n, len(p))
+		}
+		seen := make([]bool, n)
+		for _, v := range p {
+			if v < 0 || v >= n || seen[v] {
+				t.Fatalf("n=%d: %v is not a permutation", n, p)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestPermutationDeterministic(t *testing.T) {
+	seed := Sum64String("shard-a")
+	p0 := Permutation(seed, 100)
+	p1 := Permutation(seed, 100)
+	if !reflect.DeepEqual(p0, p1) {
+		t.Fatal("identical seeds gave different permutations")
+	}
+	differ := 0
+	for i := 0; i < 100; i++ {
+		if !reflect.DeepEqual(p0, Permutation(Sum64String(fmt.Sprint(i)), 100)) {
+			differ++
+		}
+	}
+	if differ < 99 {
+		t.Fatalf("only %d of 100 different seeds gave a different permutation", differ)
+	}
+
+	// Pin the output so that an accidental change in the generator is caught.
+	if got, want := Permutation(0, 8), []int{1, 6, 2, 0, 3, 4, 7, 5}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Permutation(0, 8): got %v; want %v", got, want)
+	}
+}
+
+func TestPermutationCoverage(t *testing.T) {
+	// Every ordering of a small set should be reachable.
+	seen := make(map[[3]int]int)
+	for seed := uint64(0); seed < 600; seed++ {
+		var k [3]int
+		copy(k[:], Permutation(seed, 3))
+		seen[k]++
+	}
+	if len(seen) != 6 {
+		t.Fatalf("saw %d distinct permutations of 3 elements; want 6", len(seen))
+	}
+	for k, n := range seen {
+		if n < 50 {
+			t.Errorf("permutation %v only occurred %d times out of 600", k, n)
+		}
+	}
+}
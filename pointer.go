@@ -0,0 +1,34 @@
+// +build !appengine,!tinygo,!nounsafe
+
+package xxhash
+
+import "unsafe"
+
+// Sum64Pointer computes the 64-bit xxHash digest of the n bytes starting at
+// p. It is intended for memory that is not managed by Go, such as buffers
+// allocated by C or mapped with mmap, that would otherwise have to be turned
+// into a slice by hand. The result is the same as Sum64 of those bytes.
+//
+// The n bytes at p must be readable and must not be freed, unmapped, or
+// modified until Sum64Pointer returns; it does not retain p after that. If n
+// is 0, p is not used and may be nil. If p points into memory managed by Go,
+// the memory must be part of a single allocation, as with any unsafe.Pointer
+// arithmetic. Sum64Pointer is not available in builds that avoid unsafe:
+// with the nounsafe or appengine tag, or under TinyGo.
+func Sum64Pointer(p unsafe.Pointer, n uintptr) uint64 {
+	if n == 0 {
+		return Sum64(nil)
+	}
+	if int(n) < 0 || uintptr(int(n)) != n {
+		panic("xxhash: Sum64Pointer length out of range")
+	}
+	b := *(*[]byte)(unsafe.Pointer(&rawSlice{p, int(n), int(n)}))
+	return Sum64(b)
+}
+
+// rawSlice has the layout of a slice header.
+type rawSlice struct {
+	p   unsafe.Pointer
+	len int
+	cap int
+}
@@ -0,0 +1,31 @@
+// +build !appengine,!tinygo,!nounsafe
+
+package xxhash
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestSum64Pointer(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, n := range []int{1, 7, 32, 100, 1000} {
+		if got, want := Sum64Pointer(unsafe.Pointer(&data[0]), uintptr(n)), Sum64(data[:n]); got != want {
+			t.Errorf("n=%d: got 0x%x; want 0x%x", n, got, want)
+		}
+	}
+	if got, want := Sum64Pointer(nil, 0), Sum64(nil); got != want {
+		t.Errorf("nil: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestSum64PointerAllocs(t *testing.T) {
+	data := make([]byte, 100)
+	p := unsafe.Pointer(&data[0])
+	testAllocs(t, func() {
+		sink = Sum64Pointer(p, uintptr(len(data)))
+	})
+}
@@ -0,0 +1,22 @@
+package xxhash
+
+import "sync"
+
+var digestPool = sync.Pool{
+	New: func() interface{} { return new(Digest) },
+}
+
+// GetDigest returns a Digest from a pool shared by the package, reset with a
+// seed of zero. Servers that hash a payload per request can use GetDigest and
+// PutDigest to avoid allocating a Digest each time.
+func GetDigest() *Digest {
+	d := digestPool.Get().(*Digest)
+	d.Reset()
+	return d
+}
+
+// PutDigest returns d to the pool used by GetDigest. The caller must not use
+// d afterwards.
+func PutDigest(d *Digest) {
+	digestPool.Put(d)
+}
@@ -0,0 +1,37 @@
+package xxhash
+
+import "testing"
+
+func TestGetDigest(t *testing.T) {
+	d := GetDigest()
+	d.WriteString("hello")
+	if got, want := d.Sum64(), Sum64String("hello"); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+	PutDigest(d)
+
+	// A Digest from the pool is always reset, even if it was put back with
+	// data and a seed.
+	d = GetDigest()
+	d.ResetWithSeed(5)
+	d.WriteString("dirty")
+	PutDigest(d)
+	for i := 0; i < 10; i++ {
+		d := GetDigest()
+		if got, want := d.Sum64(), Sum64(nil); got != want {
+			t.Fatalf("got 0x%x; want 0x%x", got, want)
+		}
+		PutDigest(d)
+	}
+}
+
+func TestGetDigestAllocs(t *testing.T) {
+	b := make([]byte, 100)
+	PutDigest(GetDigest())
+	testAllocs(t, func() {
+		d := GetDigest()
+		d.Write(b)
+		sink = d.Sum64()
+		PutDigest(d)
+	})
+}
@@ -0,0 +1,92 @@
+package xxhash
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// A QuickSum is a sampled fingerprint computed by QuickSum64. It is not a
+// digest of the whole content: files that differ only outside the sampled
+// ranges have the same QuickSum. Use it as a cheap first pass, such as to
+// find candidate duplicates, and compare full digests before relying on a
+// match.
+type QuickSum uint64
+
+// String returns q as "quick:" followed by 16 hex digits, so that it is not
+// mistaken for a full XXH64 digest.
+func (q QuickSum) String() string {
+	return fmt.Sprintf("quick:%016x", uint64(q))
+}
+
+// quickInteriorSamples is how many samples QuickSum64 takes between the
+// first and last.
+const quickInteriorSamples = 3
+
+// QuickSum64 computes a QuickSum of the first size bytes of r by reading only
+// a few sampleSize-byte ranges: the start, the end, and three ranges spaced
+// evenly in between. If size is at most 5*sampleSize, the whole content is
+// hashed instead. The result is defined as follows:
+//
+// The samples start at offsets i*((size-sampleSize)/4) for i = 0, 1, 2, 3
+// (with integer division), and size-sampleSize. The result is the XXH64
+// digest (seed 0) of sampleSize and size, encoded as little-endian uint64s,
+// followed by the samples in that order (or by the whole content, for small
+// inputs).
+//
+// QuickSum64 returns an error if sampleSize is not positive, if size is
+// negative, or if reading fails. If r has fewer than size bytes, the error is
+// io.ErrUnexpectedEOF.
+func QuickSum64(r io.ReaderAt, size int64, sampleSize int) (QuickSum, error) {
+	if sampleSize <= 0 {
+		return 0, errors.New("xxhash: quick sample size must be positive")
+	}
+	if size < 0 {
+		return 0, errors.New("xxhash: negative quick input size")
+	}
+	var d Digest
+	d.WriteUint64(uint64(sampleSize))
+	d.WriteUint64(uint64(size))
+	n := int64(sampleSize)
+	if size <= (quickInteriorSamples+2)*n {
+		nr, err := io.Copy(&d, io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return 0, err
+		}
+		if nr < size {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return QuickSum(d.Sum64()), nil
+	}
+	buf := make([]byte, sampleSize)
+	for i := int64(0); i <= quickInteriorSamples+1; i++ {
+		off := (size - n) / (quickInteriorSamples + 1) * i
+		if i == quickInteriorSamples+1 {
+			off = size - n
+		}
+		nr, err := r.ReadAt(buf, off)
+		if nr < len(buf) {
+			if err == io.EOF || err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		d.Write(buf)
+	}
+	return QuickSum(d.Sum64()), nil
+}
+
+// QuickSum64File is like QuickSum64 for the named file.
+func QuickSum64File(path string, sampleSize int) (QuickSum, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return QuickSum64(f, fi.Size(), sampleSize)
+}
@@ -0,0 +1,98 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// quickSum64 is a direct transcription of the QuickSum64 definition.
+func quickSum64(b []byte, sampleSize int) QuickSum {
+	rec := appendUint64(nil, uint64(sampleSize))
+	rec = appendUint64(rec, uint64(len(b)))
+	if len(b) <= 5*sampleSize {
+		return QuickSum(Sum64(append(rec, b...)))
+	}
+	for i := 0; i < 4; i++ {
+		off := i * ((len(b) - sampleSize) / 4)
+		rec = append(rec, b[off:off+sampleSize]...)
+	}
+	rec = append(rec, b[len(b)-sampleSize:]...)
+	return QuickSum(Sum64(rec))
+}
+
+func TestQuickSum64(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, n := range []int{0, 1, 499, 500, 501, 1000, 10000} {
+		got, err := QuickSum64(bytes.NewReader(data), int64(n), 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := quickSum64(data[:n], 100); got != want {
+			t.Errorf("n=%d: got %v; want %v", n, got, want)
+		}
+	}
+
+	// Only the samples matter.
+	a, _ := QuickSum64(bytes.NewReader(data), int64(len(data)), 100)
+	changed := append([]byte(nil), data...)
+	changed[200]++
+	b, _ := QuickSum64(bytes.NewReader(changed), int64(len(data)), 100)
+	if a != b {
+		t.Error("changing an unsampled byte changed the QuickSum")
+	}
+	changed[len(changed)-1]++
+	if c, _ := QuickSum64(bytes.NewReader(changed), int64(len(data)), 100); c == a {
+		t.Error("changing the last byte did not change the QuickSum")
+	}
+	if c, _ := QuickSum64(bytes.NewReader(data), int64(len(data)), 101); c == a {
+		t.Error("the sample size does not affect the QuickSum")
+	}
+}
+
+func TestQuickSum64Errors(t *testing.T) {
+	r := bytes.NewReader(make([]byte, 1000))
+	if _, err := QuickSum64(r, 1000, 0); err == nil {
+		t.Error("zero sample size: got nil error")
+	}
+	if _, err := QuickSum64(r, -1, 10); err == nil {
+		t.Error("negative size: got nil error")
+	}
+	for _, size := range []int64{50, 2000} {
+		if _, err := QuickSum64(bytes.NewReader(make([]byte, 40)), size, 10); err != io.ErrUnexpectedEOF {
+			t.Errorf("size %d of 40 bytes: got %v; want %v", size, err, io.ErrUnexpectedEOF)
+		}
+	}
+	errRead := errors.New("read failed")
+	if _, err := QuickSum64(errReaderAt{errRead}, 1000, 10); err != errRead {
+		t.Errorf("read error: got %v; want %v", err, errRead)
+	}
+}
+
+func TestQuickSum64File(t *testing.T) {
+	data := make([]byte, 5000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	f := writeTempFile(t, data)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	got, err := QuickSum64File(f.Name(), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := quickSum64(data, 64); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestQuickSumString(t *testing.T) {
+	if got, want := QuickSum(0xab).String(), "quick:00000000000000ab"; got != want {
+		t.Fatalf("got %q; want %q", got, want)
+	}
+}
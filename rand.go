@@ -0,0 +1,32 @@
+package xxhash
+
+import "math/rand"
+
+// NewRand returns a *rand.Rand whose sequence is determined by key, for
+// reproducible per-case randomness in property tests and simulations. Its
+// source is NewXOF(Sum64String(key)), so all 64 bits of the hash matter
+// (rand.NewSource keeps only 31 bits of its seed).
+func NewRand(key string) *rand.Rand {
+	return rand.New(NewXOF(Sum64String(key)))
+}
+
+// SeedFrom derives a 64-bit seed from parts, which are hashed together as
+// by Object, so that, for example, SeedFrom("case", 3) and SeedFrom("case",
+// "3") differ. It panics if a part cannot be hashed by Object. Use the
+// result with NewXOF or rand.New(NewXOF(seed)) for a full-width source.
+func SeedFrom(parts ...interface{}) uint64 {
+	return Object(parts)
+}
+
+// Int63 returns the next 8 bytes of the stream as a non-negative int64.
+// With Seed and Uint64, it makes an XOF a rand.Source64.
+func (x *XOF) Int63() int64 {
+	return int64(x.Uint64() >> 1)
+}
+
+// Seed resets x to the start of the stream determined by uint64(seed).
+func (x *XOF) Seed(seed int64) {
+	*x = XOF{sum: uint64(seed)}
+}
+
+var _ rand.Source64 = (*XOF)(nil)
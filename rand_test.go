@@ -0,0 +1,56 @@
+package xxhash
+
+import "testing"
+
+func TestNewRand(t *testing.T) {
+	r1, r2 := NewRand("case-1"), NewRand("case-1")
+	for i := 0; i < 100; i++ {
+		if a, b := r1.Int63(), r2.Int63(); a != b {
+			t.Fatalf("draw %d: %d != %d for the same key", i, a, b)
+		}
+	}
+	x := NewXOF(Sum64String("case-2"))
+	r := NewRand("case-2")
+	for i := 0; i < 10; i++ {
+		if got, want := r.Uint64(), x.Uint64(); got != want {
+			t.Fatalf("draw %d: got 0x%x; want 0x%x", i, got, want)
+		}
+	}
+	if NewRand("case-1").Int63() == NewRand("case-2").Int63() {
+		t.Error("different keys give the same first draw")
+	}
+}
+
+func TestXOFSource(t *testing.T) {
+	x := NewXOF(1)
+	x.Uint64()
+	x.Read(make([]byte, 3))
+	x.Seed(-1)
+	want := NewXOF(1<<64 - 1)
+	for i := 0; i < 4; i++ {
+		if got, want := x.Int63(), int64(want.Uint64()>>1); got != want || got < 0 {
+			t.Fatalf("draw %d after Seed: got %d; want %d", i, got, want)
+		}
+	}
+}
+
+func TestSeedFrom(t *testing.T) {
+	if got, want := SeedFrom("case", 3), Object([]interface{}{"case", 3}); got != want {
+		t.Errorf("SeedFrom = 0x%x; want 0x%x", got, want)
+	}
+	seeds := map[uint64]bool{}
+	for _, parts := range [][]interface{}{
+		{},
+		{"case", 3},
+		{"case", "3"},
+		{"case", uint8(3)},
+		{"case3"},
+		{3, "case"},
+	} {
+		seeds[SeedFrom(parts...)] = true
+	}
+	// Parts hash with their dynamic types, so even uint8(3) and 3 differ.
+	if len(seeds) != 6 {
+		t.Errorf("got %d distinct seeds; want 6", len(seeds))
+	}
+}
@@ -0,0 +1,185 @@
+package xxhash
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// Reader helpers read through a buffer that starts small and grows as long as
+// reads keep filling it: tiny inputs are hashed without allocating a large
+// buffer, while long streams quickly reach a size that amortizes the per-read
+// (typically per-syscall) overhead. Past 64 KB, larger buffers were measured
+// to be slower because they no longer stay in cache between the read and the
+// hash (see BenchmarkReadSum64).
+const (
+	minReadBufSize = 4 << 10
+	maxReadBufSize = 64 << 10
+)
+
+// Sum64Reader computes the 64-bit xxHash digest of everything read from r
+// until EOF. It returns the digest and the number of bytes read. If reading
+// fails, it returns a zero digest, the number of bytes read before the
+// failure, and the error.
+//
+// If r implements io.WriterTo, it writes its data directly into the digest.
+// Otherwise Sum64Reader reads as described for Digest.ReadFrom. The digest
+// and read buffer are reused across calls, so Sum64Reader does not allocate
+// in the common case.
+func Sum64Reader(r io.Reader) (uint64, int64, error) {
+	d := GetDigest()
+	var n int64
+	var err error
+	if wt, ok := r.(io.WriterTo); ok {
+		n, err = wt.WriteTo(d)
+	} else {
+		n, err = d.ReadFrom(r)
+	}
+	var h uint64
+	if err == nil {
+		h = d.Sum64()
+	}
+	PutDigest(d)
+	return h, n, err
+}
+
+// SumFrom computes the 64-bit xxHash digest of everything wt writes when its
+// WriteTo method is called with a Digest, so that a value that can serialize
+// itself is hashed in one pass without materializing the serialized form. If
+// WriteTo fails, SumFrom returns a zero digest and the error. The Digest
+// comes from the pool used by GetDigest, so SumFrom does not allocate it.
+func SumFrom(wt io.WriterTo) (uint64, error) {
+	d := GetDigest()
+	_, err := wt.WriteTo(d)
+	var h uint64
+	if err == nil {
+		h = d.Sum64()
+	}
+	PutDigest(d)
+	return h, err
+}
+
+// ReadFrom adds everything read from r until EOF to d and returns the number
+// of bytes read. Any error except io.EOF encountered during the read is
+// returned. ReadFrom implements io.ReaderFrom, so io.Copy(d, r) reads from r
+// into a buffer reused across calls, growing the reads as described for
+// minReadBufSize, and hashes each read in place.
+func (d *Digest) ReadFrom(r io.Reader) (n int64, err error) {
+	return readAdaptive(r, minReadBufSize, maxReadBufSize, func(b []byte) error {
+		d.Write(b)
+		return nil
+	})
+}
+
+// A Reader is an io.Reader that reads from an underlying reader and computes
+// the XXH64 digest of everything read through it.
+type Reader struct {
+	r io.Reader
+	d Digest
+	n int64
+}
+
+// NewReader creates a Reader that reads from src.
+func NewReader(src io.Reader) *Reader {
+	r := new(Reader)
+	r.Reset(src)
+	return r
+}
+
+// Reset discards r's state and makes it read from src, so that it can be
+// reused.
+func (r *Reader) Reset(src io.Reader) {
+	r.r = src
+	r.d.Reset()
+	r.n = 0
+}
+
+// Read reads from the underlying reader and hashes the bytes it returns. If
+// the underlying reader returns a count outside [0, len(p)], Read hashes
+// nothing and returns an error, as Writer.Write does for writes.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n < 0 || n > len(p) {
+		n = 0
+		if err == nil {
+			err = errInvalidRead
+		}
+	}
+	r.d.Write(p[:n])
+	r.n += int64(n)
+	return n, err
+}
+
+// errInvalidRead means that a read returned an impossible count.
+var errInvalidRead = errors.New("xxhash: invalid read result")
+
+// Sum64 returns the digest of the data read so far.
+func (r *Reader) Sum64() uint64 { return r.d.Sum64() }
+
+// BytesRead returns the number of bytes read so far.
+func (r *Reader) BytesRead() int64 { return r.n }
+
+// readBufPool holds the read buffers used by readAdaptive.
+var readBufPool = sync.Pool{
+	New: func() interface{} { return new([maxReadBufSize]byte) },
+}
+
+// readSum64 hashes everything that can be read from r.
+func readSum64(r io.Reader) (uint64, error) {
+	d := New()
+	if _, err := d.ReadFrom(r); err != nil {
+		return 0, err
+	}
+	return d.Sum64(), nil
+}
+
+// readAdaptive reads from r until EOF and passes each chunk read to consume.
+// It returns the number of bytes read and the first error from r (other than
+// io.EOF) or from consume, which stops the loop. A read that returns an
+// impossible count stops it with an error. Each chunk is only valid
+// until consume returns.
+//
+// The read size starts at minSize bytes and doubles, up to maxSize, after
+// each read that fills it completely. If maxSize is at most maxReadBufSize,
+// the buffer comes from readBufPool.
+func readAdaptive(r io.Reader, minSize, maxSize int, consume func([]byte) error) (int64, error) {
+	var buf []byte
+	if maxSize <= maxReadBufSize {
+		p := readBufPool.Get().(*[maxReadBufSize]byte)
+		defer readBufPool.Put(p)
+		buf = p[:minSize]
+	} else {
+		buf = make([]byte, minSize)
+	}
+	var total int64
+	for {
+		n, err := r.Read(buf)
+		if n < 0 || n > len(buf) {
+			n = 0
+			if err == nil {
+				err = errInvalidRead
+			}
+		}
+		total += int64(n)
+		if cerr := consume(buf[:n]); cerr != nil {
+			return total, cerr
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		if n == len(buf) && len(buf) < maxSize {
+			size := 2 * len(buf)
+			if size > maxSize {
+				size = maxSize
+			}
+			if size <= cap(buf) {
+				buf = buf[:size]
+			} else {
+				buf = make([]byte, size)
+			}
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadSum64(t *testing.T) {
+	data := make([]byte, 3*maxReadBufSize+17)
+	for i := range data {
+		data[i] = byte(i * 5)
+	}
+	for _, n := range []int{0, 1, 100, minReadBufSize, minReadBufSize + 1, 100000, len(data)} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			want := Sum64(data[:n])
+			for _, r := range []io.Reader{
+				bytes.NewReader(data[:n]),
+				iotest.HalfReader(bytes.NewReader(data[:n])),
+				iotest.DataErrReader(bytes.NewReader(data[:n])),
+			} {
+				got, err := readSum64(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Fatalf("got 0x%x; want 0x%x", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadAdaptiveError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 10)), errReader{errRead})
+	n, err := readAdaptive(r, 4, 16, func([]byte) error { return nil })
+	if err != errRead || n != 10 {
+		t.Fatalf("got (%d, %v); want (10, %v)", n, err, errRead)
+	}
+}
+
+func TestReadAdaptiveInvalidRead(t *testing.T) {
+	for _, n := range []int{-1, 17} {
+		total, err := readAdaptive(badCountReader{n}, 4, 16, func([]byte) error { return nil })
+		if total != 0 || err != errInvalidRead {
+			t.Fatalf("n=%d: got (%d, %v); want (0, %v)", n, total, err, errInvalidRead)
+		}
+	}
+}
+
+func TestSum64Reader(t *testing.T) {
+	data := make([]byte, 3*maxReadBufSize+17)
+	for i := range data {
+		data[i] = byte(i * 5)
+	}
+	for _, n := range []int{0, 1, 100, maxReadBufSize, maxReadBufSize + 1, len(data)} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			want := Sum64(data[:n])
+			for _, r := range []io.Reader{
+				bytes.NewReader(data[:n]), // implements io.WriterTo
+				struct{ io.Reader }{bytes.NewReader(data[:n])},
+				iotest.OneByteReader(bytes.NewReader(data[:n])),
+				iotest.DataErrReader(bytes.NewReader(data[:n])),
+			} {
+				got, nr, err := Sum64Reader(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want || nr != int64(n) {
+					t.Fatalf("got (0x%x, %d); want (0x%x, %d)", got, nr, want, n)
+				}
+			}
+		})
+	}
+}
+
+func TestSum64ReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 10)), errReader{errRead})
+	h, n, err := Sum64Reader(r)
+	if h != 0 || n != 10 || err != errRead {
+		t.Fatalf("got (0x%x, %d, %v); want (0, 10, %v)", h, n, err, errRead)
+	}
+}
+
+func TestSum64ReaderAllocs(t *testing.T) {
+	data := make([]byte, 100000)
+	br := bytes.NewReader(data)
+	r := struct{ io.Reader }{br}
+	for _, tt := range []struct {
+		name string
+		r    io.Reader
+	}{
+		{"WriterTo", br},
+		{"Reader", r},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			testAllocs(t, func() {
+				br.Reset(data)
+				h, _, _ := Sum64Reader(tt.r)
+				sink = h
+			})
+		})
+	}
+}
+
+// chunkedWriterTo writes its data in chunks of 7 bytes.
+type chunkedWriterTo struct {
+	data []byte
+	err  error
+}
+
+func (c chunkedWriterTo) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for b := c.data; len(b) > 0; {
+		m := 7
+		if m > len(b) {
+			m = len(b)
+		}
+		k, err := w.Write(b[:m])
+		n += int64(k)
+		if err != nil {
+			return n, err
+		}
+		b = b[m:]
+	}
+	return n, c.err
+}
+
+func TestSumFrom(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for _, n := range []int{0, 5, 32, 1000} {
+		h, err := SumFrom(chunkedWriterTo{data: data[:n]})
+		if want := Sum64(data[:n]); h != want || err != nil {
+			t.Errorf("n=%d: got (0x%x, %v); want (0x%x, nil)", n, h, err, want)
+		}
+	}
+
+	errWrite := errors.New("write failed")
+	if h, err := SumFrom(chunkedWriterTo{data, errWrite}); h != 0 || err != errWrite {
+		t.Errorf("got (0x%x, %v); want (0, %v)", h, err, errWrite)
+	}
+
+	var wt io.WriterTo = chunkedWriterTo{data: data}
+	testAllocs(t, func() {
+		h, _ := SumFrom(wt)
+		sink = h
+	})
+}
+
+func TestDigestReadFrom(t *testing.T) {
+	data := make([]byte, 3*maxReadBufSize+17)
+	for i := range data {
+		data[i] = byte(i * 5)
+	}
+	for _, n := range []int{0, 1, 100, maxReadBufSize, maxReadBufSize + 1, len(data)} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			for _, r := range []io.Reader{
+				struct{ io.Reader }{bytes.NewReader(data[:n])},
+				iotest.OneByteReader(bytes.NewReader(data[:n])),
+				iotest.DataErrReader(bytes.NewReader(data[:n])),
+			} {
+				d := New()
+				d.Write(data[:3]) // ReadFrom continues an existing digest
+				nr, err := io.Copy(d, r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := Sum64(append(data[:3:3], data[:n]...))
+				if got := d.Sum64(); got != want || nr != int64(n) {
+					t.Fatalf("got (0x%x, %d); want (0x%x, %d)", got, nr, want, n)
+				}
+			}
+		})
+	}
+}
+
+func TestDigestReadFromError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 10)), errReader{errRead})
+	n, err := New().ReadFrom(r)
+	if n != 10 || err != errRead {
+		t.Fatalf("got (%d, %v); want (10, %v)", n, err, errRead)
+	}
+}
+
+func TestDigestReadFromAllocs(t *testing.T) {
+	data := make([]byte, 100000)
+	br := bytes.NewReader(data)
+	d := New()
+	testAllocs(t, func() {
+		br.Reset(data)
+		d.Reset()
+		d.ReadFrom(br)
+		sink = d.Sum64()
+	})
+}
+
+func TestReader(t *testing.T) {
+	data := make([]byte, 3*maxReadBufSize+17)
+	for i := range data {
+		data[i] = byte(i * 5)
+	}
+	for _, src := range []io.Reader{
+		bytes.NewReader(data),
+		iotest.OneByteReader(bytes.NewReader(data)),
+		iotest.DataErrReader(bytes.NewReader(data)),
+	} {
+		r := NewReader(src)
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, r); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatal("wrong data read")
+		}
+		if got, want := r.Sum64(), Sum64(data); got != want {
+			t.Fatalf("Sum64: got 0x%x; want 0x%x", got, want)
+		}
+		if r.BytesRead() != int64(len(data)) {
+			t.Fatalf("BytesRead: got %d; want %d", r.BytesRead(), len(data))
+		}
+	}
+}
+
+// badCountReader returns n from every Read, whatever len(p) is.
+type badCountReader struct{ n int }
+
+func (r badCountReader) Read(p []byte) (int, error) { return r.n, nil }
+
+func TestReaderInvalidRead(t *testing.T) {
+	for _, n := range []int{-1, 9} {
+		r := NewReader(badCountReader{n})
+		got, err := r.Read(make([]byte, 8))
+		if got != 0 || err == nil {
+			t.Fatalf("n=%d: got (%d, %v); want (0, non-nil error)", n, got, err)
+		}
+		if r.Sum64() != Sum64String("") || r.BytesRead() != 0 {
+			t.Fatalf("n=%d: invalid read hashed or counted bytes", n)
+		}
+	}
+}
+
+func TestReaderReset(t *testing.T) {
+	r := NewReader(bytes.NewReader(make([]byte, 100)))
+	ioutil.ReadAll(r)
+	r.Reset(strings.NewReader("abc"))
+	ioutil.ReadAll(r)
+	if r.Sum64() != Sum64String("abc") || r.BytesRead() != 3 {
+		t.Fatal("wrong state after Reset")
+	}
+}
+
+func TestReaderAllocs(t *testing.T) {
+	data := make([]byte, 1000)
+	br := bytes.NewReader(data)
+	r := NewReader(br)
+	buf := make([]byte, 100)
+	testAllocs(t, func() {
+		br.Reset(data)
+		r.Reset(br)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				break
+			}
+		}
+		sink = r.Sum64()
+	})
+}
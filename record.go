@@ -0,0 +1,116 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A RecordReader reads a stream of records, each consisting of a payload
+// followed by the 8-byte XXH64 digest of that payload, and verifies every
+// record as it is read.
+//
+// The trailing digest is stored in big-endian order, which is the same
+// encoding produced by Digest.Sum. Payloads are framed in one of two ways:
+//
+//   - Fixed-size (NewRecordReader): every payload is exactly the same length.
+//   - Length-prefixed (NewLengthPrefixedRecordReader): each record starts with
+//     a 4-byte big-endian payload length.
+type RecordReader struct {
+	r     io.Reader
+	size  int // fixed payload size; -1 if records are length-prefixed
+	max   int // maximum payload size for length-prefixed records
+	buf   []byte
+	index int
+	err   error
+}
+
+// maxRecordPayload is the largest payload for which the payload plus its
+// digest still fits in an int.
+const maxRecordPayload = int(^uint(0)>>1) - 8
+
+// NewRecordReader returns a RecordReader that reads records with
+// payloadSize-byte payloads from r. It panics if payloadSize is negative or
+// too large to buffer together with its digest.
+func NewRecordReader(r io.Reader, payloadSize int) *RecordReader {
+	if payloadSize < 0 || payloadSize > maxRecordPayload {
+		panic("xxhash: invalid record payload size")
+	}
+	return &RecordReader{r: r, size: payloadSize}
+}
+
+// NewLengthPrefixedRecordReader returns a RecordReader that reads
+// length-prefixed records from r. Records whose declared payload length is
+// greater than maxPayload are rejected with an error rather than allocated.
+// A negative maxPayload means there is no limit other than what fits in an
+// int.
+func NewLengthPrefixedRecordReader(r io.Reader, maxPayload int) *RecordReader {
+	return &RecordReader{r: r, size: -1, max: maxPayload}
+}
+
+// A RecordError reports a record whose payload does not match its trailing
+// digest.
+type RecordError struct {
+	Index int // zero-based index of the record in the stream
+	MismatchError
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("xxhash: record %d: checksum mismatch: got %016x, want %016x", e.Index, e.Got, e.Want)
+}
+
+// Unwrap returns the underlying *MismatchError.
+func (e *RecordError) Unwrap() error { return &e.MismatchError }
+
+// Next reads and verifies the next record and returns its payload. The
+// returned slice is only valid until the next call to Next.
+//
+// At the end of the stream Next returns io.EOF. If the stream ends partway
+// through a record, it returns io.ErrUnexpectedEOF. If a record fails
+// verification, Next returns a *RecordError. Once Next has returned an error,
+// all later calls return the same error.
+func (rr *RecordReader) Next() ([]byte, error) {
+	if rr.err != nil {
+		return nil, rr.err
+	}
+	p, err := rr.next()
+	if err != nil {
+		rr.err = err
+		return nil, err
+	}
+	rr.index++
+	return p, nil
+}
+
+func (rr *RecordReader) next() ([]byte, error) {
+	n := rr.size
+	if n < 0 {
+		var hdr [4]byte
+		if _, err := io.ReadFull(rr.r, hdr[:]); err != nil {
+			return nil, err
+		}
+		size := binary.BigEndian.Uint32(hdr[:])
+		if uint64(size) > uint64(rr.max) || uint64(size) > uint64(maxRecordPayload) {
+			return nil, fmt.Errorf("xxhash: record %d: payload length %d exceeds maximum %d", rr.index, size, rr.max)
+		}
+		n = int(size)
+	}
+	if cap(rr.buf) < n+8 {
+		rr.buf = make([]byte, n+8)
+	}
+	b := rr.buf[:n+8]
+	if _, err := io.ReadFull(rr.r, b); err != nil {
+		if err == io.EOF && rr.size < 0 {
+			// We already consumed this record's length prefix.
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	payload := b[:n]
+	got := Sum64(payload)
+	want := binary.BigEndian.Uint64(b[n:])
+	if got != want {
+		return nil, &RecordError{Index: rr.index, MismatchError: MismatchError{Got: got, Want: want}}
+	}
+	return payload, nil
+}
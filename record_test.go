@@ -0,0 +1,142 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func appendRecord(b []byte, payload []byte, lengthPrefix bool) []byte {
+	if lengthPrefix {
+		var hdr [4]byte
+		binary.BigEndian.PutUint32(hdr[:], uint32(len(payload)))
+		b = append(b, hdr[:]...)
+	}
+	b = append(b, payload...)
+	d := New()
+	d.Write(payload)
+	return d.Sum(b)
+}
+
+func TestRecordReader(t *testing.T) {
+	const numRecords = 50
+	const bad = 37
+	for _, lengthPrefix := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lengthPrefix=%t", lengthPrefix), func(t *testing.T) {
+			var stream []byte
+			var payloads [][]byte
+			for i := 0; i < numRecords; i++ {
+				payload := []byte(fmt.Sprintf("record %04d", i))
+				if lengthPrefix {
+					payload = bytes.Repeat(payload, i%3+1)
+				}
+				payloads = append(payloads, payload)
+				stream = appendRecord(stream, payload, lengthPrefix)
+			}
+			// Corrupt one byte of the bad record's payload.
+			off := 0
+			for i := 0; i < bad; i++ {
+				off += len(payloads[i]) + 8
+				if lengthPrefix {
+					off += 4
+				}
+			}
+			if lengthPrefix {
+				off += 4
+			}
+			stream[off] ^= 1
+
+			var rr *RecordReader
+			if lengthPrefix {
+				rr = NewLengthPrefixedRecordReader(bytes.NewReader(stream), 1<<10)
+			} else {
+				rr = NewRecordReader(bytes.NewReader(stream), len(payloads[0]))
+			}
+			for i := 0; i < bad; i++ {
+				p, err := rr.Next()
+				if err != nil {
+					t.Fatalf("record %d: unexpected error: %s", i, err)
+				}
+				if !bytes.Equal(p, payloads[i]) {
+					t.Fatalf("record %d: got payload %q; want %q", i, p, payloads[i])
+				}
+			}
+			_, err := rr.Next()
+			rerr, ok := err.(*RecordError)
+			if !ok {
+				t.Fatalf("got error %v; want *RecordError", err)
+			}
+			if rerr.Index != bad {
+				t.Errorf("got Index=%d; want %d", rerr.Index, bad)
+			}
+			if rerr.Want != Sum64(payloads[bad]) {
+				t.Errorf("got Want=0x%x; want 0x%x", rerr.Want, Sum64(payloads[bad]))
+			}
+			if rerr.Got == rerr.Want {
+				t.Errorf("got Got == Want (0x%x)", rerr.Got)
+			}
+			if _, ok := rerr.Unwrap().(*MismatchError); !ok {
+				t.Errorf("Unwrap returned %T; want *MismatchError", rerr.Unwrap())
+			}
+			if _, err2 := rr.Next(); err2 != err {
+				t.Errorf("after a mismatch, Next returned %v; want the same error", err2)
+			}
+		})
+	}
+}
+
+func TestRecordReaderEOF(t *testing.T) {
+	stream := appendRecord(nil, []byte("abcd"), false)
+	stream = appendRecord(stream, []byte("efgh"), false)
+
+	rr := NewRecordReader(bytes.NewReader(stream), 4)
+	for i := 0; i < 2; i++ {
+		if _, err := rr.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := rr.Next(); err != io.EOF {
+		t.Fatalf("at end of stream: got %v; want io.EOF", err)
+	}
+
+	rr = NewRecordReader(bytes.NewReader(stream[:len(stream)-3]), 4)
+	rr.Next()
+	if _, err := rr.Next(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated record: got %v; want io.ErrUnexpectedEOF", err)
+	}
+
+	stream = appendRecord(nil, []byte("abcd"), true)
+	rr = NewLengthPrefixedRecordReader(bytes.NewReader(stream[:4]), 10)
+	if _, err := rr.Next(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("length prefix only: got %v; want io.ErrUnexpectedEOF", err)
+	}
+	rr = NewLengthPrefixedRecordReader(bytes.NewReader(stream), 3)
+	if _, err := rr.Next(); err == nil {
+		t.Fatal("oversized record: got nil error")
+	}
+}
+
+func TestRecordReaderNoLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 1000)
+	rr := NewLengthPrefixedRecordReader(bytes.NewReader(appendRecord(nil, payload, true)), -1)
+	got, err := rr.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("got %d-byte payload; want %d bytes", len(got), len(payload))
+	}
+
+	for _, size := range []int{-1, maxRecordPayload + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewRecordReader(r, %d) did not panic", size)
+				}
+			}()
+			NewRecordReader(bytes.NewReader(nil), size)
+		}()
+	}
+}
@@ -0,0 +1,40 @@
+package xxhash
+
+import (
+	"hash"
+	"strings"
+)
+
+// registry lists the constructors returned by Lookup, in the order of Names.
+var registry = []struct {
+	name string
+	new  func() hash.Hash
+}{
+	{"xxh32", func() hash.Hash { return New32() }},
+	{"xxh64", func() hash.Hash { return New() }},
+	{"xxh3-64", func() hash.Hash { return NewXXH3() }},
+	{"xxh3-128", func() hash.Hash { return NewVariant(XXH3_128) }},
+}
+
+// Lookup returns a constructor for the hash named name, for selecting an
+// algorithm from configuration. The names, matched case-insensitively, are
+// those returned by Names. Each constructed hash uses a seed of zero and, for
+// XXH3, the default secret; its Sum appends the canonical big-endian digest.
+func Lookup(name string) (newHash func() hash.Hash, ok bool) {
+	for _, r := range registry {
+		if strings.EqualFold(name, r.name) {
+			return r.new, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names that Lookup accepts: "xxh32", "xxh64", "xxh3-64",
+// and "xxh3-128".
+func Names() []string {
+	names := make([]string, len(registry))
+	for i, r := range registry {
+		names[i] = r.name
+	}
+	return names
+}
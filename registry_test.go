@@ -0,0 +1,37 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	// The canonical digests of "abc", as printed by the reference xxhsum.
+	want := map[string]string{
+		"xxh32":    "32d153ff",
+		"xxh64":    "44bc2cf5ad770999",
+		"xxh3-64":  "78af5f94892f3950",
+		"xxh3-128": "06b05ab6733a618578af5f94892f3950",
+	}
+	names := Names()
+	if fmt.Sprint(names) != "[xxh32 xxh64 xxh3-64 xxh3-128]" {
+		t.Errorf("Names() = %q", names)
+	}
+	for _, name := range names {
+		newHash, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("Lookup(%q) failed", name)
+		}
+		h := newHash()
+		h.Write([]byte("abc"))
+		if got := h.Sum(nil); fmt.Sprintf("%x", got) != want[name] || h.Size() != len(got) {
+			t.Errorf("%s: got %x (Size %d); want %x", name, got, h.Size(), want[name])
+		}
+	}
+	if _, ok := Lookup("XXH3-64"); !ok {
+		t.Error("Lookup is case-sensitive")
+	}
+	if _, ok := Lookup("md5"); ok {
+		t.Error("Lookup(\"md5\") succeeded")
+	}
+}
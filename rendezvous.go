@@ -0,0 +1,80 @@
+package xxhash
+
+import "sort"
+
+// A Rendezvous assigns keys to nodes by rendezvous (highest random weight)
+// hashing: each key belongs to the node with the highest score for that
+// key. When a node is removed, only its keys move, and they spread evenly
+// over the remaining nodes.
+//
+// The score of a key for a node is the XXH64 digest of the key using the
+// digest of the node's identifier as the seed.
+type Rendezvous struct {
+	nodes []string
+	seeds []uint64
+}
+
+// NewRendezvous creates a Rendezvous over the given node identifiers.
+func NewRendezvous(nodes ...string) *Rendezvous {
+	r := &Rendezvous{
+		nodes: append([]string(nil), nodes...),
+		seeds: make([]uint64, len(nodes)),
+	}
+	for i, n := range nodes {
+		r.seeds[i] = Sum64String(n)
+	}
+	return r
+}
+
+// Nodes returns the node identifiers, in the order passed to NewRendezvous.
+func (r *Rendezvous) Nodes() []string {
+	return append([]string(nil), r.nodes...)
+}
+
+// Owner returns the node with the highest score for key, or "" if there are
+// no nodes. Ties go to the node that was listed first.
+func (r *Rendezvous) Owner(key string) string {
+	best := -1
+	var bestScore uint64
+	for i, seed := range r.seeds {
+		if s := Sum64StringWithSeed(key, seed); best < 0 || s > bestScore {
+			best, bestScore = i, s
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	return r.nodes[best]
+}
+
+// Top returns the k nodes with the highest scores for key, highest first,
+// such as the replicas for the key. If k exceeds the number of nodes, all of
+// them are returned.
+func (r *Rendezvous) Top(key string, k int) []string {
+	if k > len(r.nodes) {
+		k = len(r.nodes)
+	}
+	if k <= 0 {
+		return nil
+	}
+	scored := make([]rendezvousScore, len(r.nodes))
+	for i, seed := range r.seeds {
+		scored[i] = rendezvousScore{i, Sum64StringWithSeed(key, seed)}
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].index < scored[j].index
+	})
+	top := make([]string, k)
+	for i := range top {
+		top[i] = r.nodes[scored[i].index]
+	}
+	return top
+}
+
+type rendezvousScore struct {
+	index int
+	score uint64
+}
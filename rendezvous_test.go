@@ -0,0 +1,76 @@
+package xxhash
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRendezvous(t *testing.T) {
+	nodes := []string{"a", "b", "c", "d", "e"}
+	r := NewRendezvous(nodes...)
+	counts := make(map[string]int)
+	const keys = 10000
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprint("key", i)
+		owner := r.Owner(key)
+		counts[owner]++
+		top := r.Top(key, 3)
+		if len(top) != 3 || top[0] != owner {
+			t.Fatalf("Top(%q, 3) = %v; want 3 nodes starting with owner %q", key, top, owner)
+		}
+		if got := r.Top(key, 10); len(got) != len(nodes) || !reflect.DeepEqual(got[:3], top) {
+			t.Fatalf("Top(%q, 10) = %v; want all nodes starting with %v", key, got, top)
+		}
+	}
+	for _, n := range nodes {
+		if c := counts[n]; c < keys/len(nodes)*3/4 || c > keys/len(nodes)*5/4 {
+			t.Errorf("node %q owns %d keys; want about %d", n, c, keys/len(nodes))
+		}
+	}
+}
+
+func TestRendezvousRemoveNode(t *testing.T) {
+	// Removing a node only moves its keys, and they move to the node that
+	// was second for them.
+	full := NewRendezvous("a", "b", "c", "d")
+	less := NewRendezvous("a", "b", "d")
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("key", i)
+		top := full.Top(key, 2)
+		want := top[0]
+		if want == "c" {
+			want = top[1]
+		}
+		if got := less.Owner(key); got != want {
+			t.Fatalf("key %q: got owner %q after removing c; want %q", key, got, want)
+		}
+	}
+}
+
+func TestRendezvousEmpty(t *testing.T) {
+	r := NewRendezvous()
+	if got := r.Owner("x"); got != "" {
+		t.Errorf("Owner with no nodes: got %q", got)
+	}
+	if got := r.Top("x", 2); len(got) != 0 {
+		t.Errorf("Top with no nodes: got %v", got)
+	}
+	if got := NewRendezvous("a").Top("x", 0); len(got) != 0 {
+		t.Errorf("Top(0): got %v", got)
+	}
+}
+
+func TestRendezvousScore(t *testing.T) {
+	// Pin the scoring so that assignments stay stable across versions.
+	r := NewRendezvous("node1", "node2")
+	s1 := Sum64StringWithSeed("key", Sum64String("node1"))
+	s2 := Sum64StringWithSeed("key", Sum64String("node2"))
+	want := "node1"
+	if s2 > s1 {
+		want = "node2"
+	}
+	if got := r.Owner("key"); got != want {
+		t.Fatalf("got %q; want %q", got, want)
+	}
+}
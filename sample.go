@@ -0,0 +1,33 @@
+package xxhash
+
+import "math"
+
+// SampleString reports whether key is in a sample of the given rate: the
+// decision is a deterministic function of key, so every process sampling
+// at the same rate makes the same decision for the same key, and keys
+// sampled at one rate are also sampled at every higher rate.
+//
+// Key is sampled if Sum64String(key) < rate * 2^64, so the probability of
+// sampling a key is rate to within 2^-64. A rate <= 0 (or NaN) samples
+// nothing and a rate >= 1 samples everything.
+func SampleString(key string, rate float64) bool {
+	return sampled(Sum64String(key), rate)
+}
+
+// SampleStringWithSeed is like SampleString, but hashes key with the given
+// seed, so that independent samples can be drawn from the same keys.
+func SampleStringWithSeed(key string, seed uint64, rate float64) bool {
+	return sampled(Sum64StringWithSeed(key, seed), rate)
+}
+
+func sampled(h uint64, rate float64) bool {
+	if !(rate > 0) {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	// rate * 2^64 is exact and below 2^64, so the conversion only drops
+	// the fraction.
+	return h < uint64(math.Ldexp(rate, 64))
+}
@@ -0,0 +1,88 @@
+package xxhash
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSampleString(t *testing.T) {
+	keys := make([]string, 100000)
+	for i := range keys {
+		keys[i] = fmt.Sprint("trace-", i)
+	}
+	for _, rate := range []float64{0.001, 0.1, 0.5, 0.9} {
+		var n int
+		for _, k := range keys {
+			if SampleString(k, rate) {
+				n++
+				// Sampling is monotone in the rate.
+				if !SampleString(k, rate+0.05) {
+					t.Errorf("%q sampled at %v but not at %v", k, rate, rate+0.05)
+				}
+			}
+		}
+		want := rate * float64(len(keys))
+		if tol := 5 * math.Sqrt(want); math.Abs(float64(n)-want) > tol {
+			t.Errorf("rate %v: sampled %d of %d keys; want about %.0f", rate, n, len(keys), want)
+		}
+	}
+
+	for _, tt := range []struct {
+		rate float64
+		want bool
+	}{
+		{0, false},
+		{-1, false},
+		{math.NaN(), false},
+		{1, true},
+		{2, true},
+		{math.Inf(1), true},
+	} {
+		for _, k := range keys[:100] {
+			if got := SampleString(k, tt.rate); got != tt.want {
+				t.Fatalf("SampleString(%q, %v) = %t; want %t", k, tt.rate, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSampledRange(t *testing.T) {
+	// The threshold covers the full 64-bit range.
+	for _, tt := range []struct {
+		h    uint64
+		rate float64
+		want bool
+	}{
+		{0, 1.0 / (1 << 64), true},
+		{1, 1.0 / (1 << 64), false},
+		{0, math.SmallestNonzeroFloat64, false},
+		{1<<63 - 1, 0.5, true},
+		{1 << 63, 0.5, false},
+		{math.MaxUint64 - 2048, math.Nextafter(1, 0), true},
+		{math.MaxUint64, math.Nextafter(1, 0), false},
+	} {
+		if got := sampled(tt.h, tt.rate); got != tt.want {
+			t.Errorf("sampled(0x%x, %v) = %t; want %t", tt.h, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestSampleStringWithSeed(t *testing.T) {
+	for _, k := range []string{"", "a", "trace-1"} {
+		h := Sum64StringWithSeed(k, 42)
+		if got, want := SampleStringWithSeed(k, 42, 0.5), h < 1<<63; got != want {
+			t.Errorf("SampleStringWithSeed(%q, 42, 0.5) = %t; want %t", k, got, want)
+		}
+	}
+	var differ int
+	for i := 0; i < 1000; i++ {
+		k := fmt.Sprint(i)
+		if SampleStringWithSeed(k, 1, 0.5) != SampleStringWithSeed(k, 2, 0.5) {
+			differ++
+		}
+	}
+	if differ < 400 || differ > 600 {
+		t.Errorf("samples with different seeds differ on %d of 1000 keys; want about 500", differ)
+	}
+}
@@ -0,0 +1,71 @@
+package xxhash
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// DeriveSeeds deterministically derives k seeds from a single master seed,
+// for configuring structures that need several independent hash functions.
+//
+// The ith seed is the XXH64 avalanche of master + (i+1)*prime1. Because the
+// avalanche step is a bijection, the k seeds are always distinct. The output
+// for a given (master, k) pair will not change between releases, and the
+// seeds for a smaller k are a prefix of those for a larger k.
+func DeriveSeeds(master uint64, k int) []uint64 {
+	seeds := make([]uint64, k)
+	r := mixRand{state: master}
+	for i := range seeds {
+		seeds[i] = r.next()
+	}
+	return seeds
+}
+
+// A Seed is a random seed for hashing, in the style of hash/maphash. Servers
+// that put attacker-controlled keys in hash tables can use a Seed made by
+// MakeSeed, per process or per table, so that the hashes of those keys
+// cannot be predicted offline.
+//
+// A seed makes hashes unpredictable to anyone who doesn't know it, but
+// xxHash is not a keyed cryptographic hash: an attacker who can observe
+// enough hashes may still be able to find collisions.
+//
+// The zero Seed is not valid; using it panics. Seeds may be compared with ==.
+type Seed struct {
+	s uint64
+}
+
+// MakeSeed returns a new random Seed.
+func MakeSeed() Seed {
+	var b [8]byte
+	for {
+		if _, err := rand.Read(b[:]); err != nil {
+			panic("xxhash: cannot read random seed: " + err.Error())
+		}
+		if s := binary.LittleEndian.Uint64(b[:]); s != 0 {
+			return Seed{s}
+		}
+	}
+}
+
+func (s Seed) value() uint64 {
+	if s.s == 0 {
+		panic("xxhash: use of uninitialized Seed")
+	}
+	return s.s
+}
+
+// Sum64 computes the XXH64 digest of b using s.
+func (s Seed) Sum64(b []byte) uint64 {
+	return Sum64WithSeed(b, s.value())
+}
+
+// Sum64String computes the XXH64 digest of str using s.
+func (s Seed) Sum64String(str string) uint64 {
+	return Sum64StringWithSeed(str, s.value())
+}
+
+// New creates a new Digest that computes XXH64 using s.
+func (s Seed) New() *Digest {
+	return NewWithSeed(s.value())
+}
@@ -0,0 +1,57 @@
+package xxhash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeriveSeeds(t *testing.T) {
+	for _, master := range []uint64{0, 1, 0xef46db3751d8e999} {
+		seeds := DeriveSeeds(master, 1000)
+		seen := make(map[uint64]bool)
+		for i, s := range seeds {
+			if seen[s] {
+				t.Fatalf("master=0x%x: seed %d (0x%x) is a duplicate", master, i, s)
+			}
+			seen[s] = true
+		}
+		if again := DeriveSeeds(master, 1000); !reflect.DeepEqual(seeds, again) {
+			t.Fatalf("master=0x%x: seeds are not reproducible", master)
+		}
+		if prefix := DeriveSeeds(master, 10); !reflect.DeepEqual(prefix, seeds[:10]) {
+			t.Fatalf("master=0x%x: k=10 is not a prefix of k=1000", master)
+		}
+	}
+	if got := DeriveSeeds(1, 0); len(got) != 0 {
+		t.Fatalf("k=0: got %v", got)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	s1, s2 := MakeSeed(), MakeSeed()
+	if s1 == s2 {
+		t.Fatal("MakeSeed returned the same seed twice")
+	}
+	b := []byte("hello, seed")
+	if s1.Sum64(b) == s2.Sum64(b) {
+		t.Error("different seeds gave the same hash")
+	}
+	if got, want := s1.Sum64String(string(b)), s1.Sum64(b); got != want {
+		t.Errorf("Sum64String: got 0x%x; want 0x%x", got, want)
+	}
+	d := s1.New()
+	d.Write(b)
+	if got, want := d.Sum64(), s1.Sum64(b); got != want {
+		t.Errorf("New: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestSeedZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic using the zero Seed")
+		}
+	}()
+	var s Seed
+	s.Sum64(nil)
+}
@@ -0,0 +1,91 @@
+package xxhash
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// selfTestInput returns the input used by SelfTest's XXH3 vectors, the same
+// as the package tests.
+func selfTestInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i*7 + i/256)
+	}
+	return b
+}
+
+const selfTestText = "Call me Ishmael. Some years ago--never mind how long precisely-"
+
+// SelfTest checks that the implementations selected for this build and CPU,
+// including any assembly and vector kernels, compute correct digests. It
+// checks XXH64, XXH32, and XXH3 against known-answer vectors from the
+// reference implementation, then compares the selected XXH64 and XXH3 code
+// against the pure-Go code on random inputs. It returns a descriptive error
+// for the first mismatch.
+//
+// SelfTest takes a few milliseconds. It is meant for deployments that want a
+// startup check on the actual hardware; the package works the same whether or
+// not it is called.
+func SelfTest() error {
+	input := selfTestInput(100000)
+	for _, v := range []struct {
+		name string
+		got  uint64
+		want uint64
+	}{
+		{"XXH64 of 0 bytes", Sum64(nil), 0xef46db3751d8e999},
+		{"XXH64 of 4 bytes", Sum64String("asdf"), 0x415872f599cea71e},
+		{"XXH64 of 63 bytes", Sum64String(selfTestText), 0x02a2e85470d6fd96},
+		{"XXH64 of 63 bytes with seed", Sum64StringWithSeed(selfTestText, 1), 0x67ca9f6ecb8a4659},
+		{"XXH32 of 0 bytes", uint64(Sum32(nil)), 0x02cc5d05},
+		{"XXH32 of 63 bytes", uint64(Sum32String(selfTestText)), 0x6f320359},
+		{"XXH3-64 of 3 bytes", SumXXH3_64(input[:3]), 0xc3489259e968ad9e},
+		{"XXH3-64 of 17 bytes", SumXXH3_64(input[:17]), 0xf34c3c9cf5a112d1},
+		{"XXH3-64 of 129 bytes", SumXXH3_64(input[:129]), 0x28065c6ec25f5b25},
+		{"XXH3-64 of 241 bytes", SumXXH3_64(input[:241]), 0x541b19226f0052e8},
+		{"XXH3-64 of 1025 bytes", SumXXH3_64(input[:1025]), 0xd9b414f4e1bbf7ad},
+		{"XXH3-64 of 100000 bytes", SumXXH3_64(input), 0xb25cea78018497ff},
+		{"XXH3-128 (high) of 1025 bytes", SumXXH3_128(input[:1025]).Hi, 0xa53cd4fd16206676},
+		{"XXH3-128 (high) of 100000 bytes", SumXXH3_128(input).Hi, 0x4e53faeda1b5812b},
+	} {
+		if v.got != v.want {
+			return fmt.Errorf("xxhash: self-test failed: %s: got %#x, want %#x", v.name, v.got, v.want)
+		}
+	}
+
+	seed := time.Now().UnixNano()
+	rng := rand.New(rand.NewSource(seed))
+	rng.Read(input)
+	for i := 0; i < 100; i++ {
+		n := rng.Intn(5000)
+		b := input[rng.Intn(len(input)-n):][:n]
+		if got, want := Sum64(b), sum64Generic(b); got != want {
+			return fmt.Errorf("xxhash: self-test failed: XXH64 of %d random bytes (seed %d): got %#x, pure Go %#x", n, seed, got, want)
+		}
+		var d, g Digest
+		d.Reset()
+		g.Reset()
+		if len(b) >= 32 && (writeBlocks(&d, b) != writeBlocksGeneric(&g, b) || d != g) {
+			return fmt.Errorf("xxhash: self-test failed: XXH64 blocks of %d random bytes (seed %d) differ from pure Go", n, seed)
+		}
+
+		var acc, want [8]uint64
+		for j := range acc {
+			acc[j] = rng.Uint64()
+		}
+		want = acc
+		stripes := 1 + rng.Intn(16)
+		b = input[rng.Intn(len(input)-stripes*xxh3StripeLen):]
+		secret := xxh3Secret[rng.Intn(len(xxh3Secret)-(stripes-1)*8-xxh3StripeLen+1):]
+		xxh3Accumulate(&acc, b, secret, stripes)
+		xxh3ScrambleAcc(&acc, secret)
+		xxh3AccumulateGeneric(&want, b, secret, stripes)
+		xxh3ScrambleAccGeneric(&want, secret)
+		if acc != want {
+			return fmt.Errorf("xxhash: self-test failed: XXH3 %s kernel on %d random stripes (seed %d) differs from pure Go", xxh3Implementation(), stripes, seed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build go1.23
+// +build go1.23
+
+package xxhash
+
+import "iter"
+
+// Sum64Seq computes the 64-bit xxHash digest of the concatenation of the
+// fragments produced by seq.
+func Sum64Seq(seq iter.Seq[[]byte]) uint64 {
+	var d Digest
+	d.Reset()
+	for b := range seq {
+		d.Write(b)
+	}
+	return d.Sum64()
+}
@@ -0,0 +1,33 @@
+//go:build go1.23
+// +build go1.23
+
+package xxhash
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSum64Seq(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 17)
+	}
+	for _, n := range []int{1, 3, 31, 32, 33, 100, 1000} {
+		var frags [][]byte
+		for b := data; len(b) > 0; {
+			k := n
+			if k > len(b) {
+				k = len(b)
+			}
+			frags = append(frags, b[:k], nil)
+			b = b[k:]
+		}
+		if got, want := Sum64Seq(slices.Values(frags)), Sum64(data); got != want {
+			t.Fatalf("fragments of %d: got 0x%x; want 0x%x", n, got, want)
+		}
+	}
+	if got, want := Sum64Seq(slices.Values([][]byte(nil))), Sum64(nil); got != want {
+		t.Fatalf("empty sequence: got 0x%x; want 0x%x", got, want)
+	}
+}
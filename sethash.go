@@ -0,0 +1,74 @@
+package xxhash
+
+import "encoding/binary"
+
+// A SetHash is an order-independent digest of a multiset of byte strings,
+// maintained incrementally: adding and then removing an element leaves it
+// unchanged, and two SetHashes that saw the same elements added and removed
+// in any order are equal. The zero value is the digest of the empty set.
+//
+// The digest has four 64-bit lanes; lane i is the sum, modulo 2^64, of
+// Sum64WithSeed(x, i) over the elements x, so it costs four XXH64 digests
+// per update. Like XXH64 itself, it detects accidental differences but is
+// not secure against an adversary who chooses the elements.
+type SetHash struct {
+	acc [4]uint64
+	n   int64
+}
+
+// Add adds b to the set.
+func (s *SetHash) Add(b []byte) {
+	for i := range s.acc {
+		s.acc[i] += Sum64WithSeed(b, uint64(i))
+	}
+	s.n++
+}
+
+// AddString adds str to the set.
+func (s *SetHash) AddString(str string) {
+	for i := range s.acc {
+		s.acc[i] += Sum64StringWithSeed(str, uint64(i))
+	}
+	s.n++
+}
+
+// Remove removes b from the set. Removing an element that was never added
+// is allowed; it is undone by adding the element later.
+func (s *SetHash) Remove(b []byte) {
+	for i := range s.acc {
+		s.acc[i] -= Sum64WithSeed(b, uint64(i))
+	}
+	s.n--
+}
+
+// RemoveString removes str from the set, like Remove.
+func (s *SetHash) RemoveString(str string) {
+	for i := range s.acc {
+		s.acc[i] -= Sum64StringWithSeed(str, uint64(i))
+	}
+	s.n--
+}
+
+// Merge adds all the elements of o to s.
+func (s *SetHash) Merge(o *SetHash) {
+	for i := range s.acc {
+		s.acc[i] += o.acc[i]
+	}
+	s.n += o.n
+}
+
+// Len returns the number of elements added minus the number removed.
+func (s *SetHash) Len() int64 { return s.n }
+
+// Equal reports whether s and o are digests of the same multiset.
+func (s *SetHash) Equal(o *SetHash) bool { return *s == *o }
+
+// Sum appends the 32-byte digest, the four lanes in little-endian order, to
+// b and returns the resulting slice. It does not include Len.
+func (s *SetHash) Sum(b []byte) []byte {
+	var buf [32]byte
+	for i, v := range s.acc {
+		binary.LittleEndian.PutUint64(buf[8*i:], v)
+	}
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,78 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSetHash(t *testing.T) {
+	var a, b SetHash
+	for i := 0; i < 100; i++ {
+		a.AddString(fmt.Sprint(i))
+	}
+	for i := 99; i >= 0; i-- {
+		b.Add([]byte(fmt.Sprint(i)))
+	}
+	if !a.Equal(&b) || !bytes.Equal(a.Sum(nil), b.Sum(nil)) {
+		t.Fatal("same elements in a different order give different digests")
+	}
+	if a.Len() != 100 {
+		t.Errorf("Len = %d; want 100", a.Len())
+	}
+
+	b.AddString("extra")
+	if a.Equal(&b) {
+		t.Fatal("extra element did not change the digest")
+	}
+	b.Remove([]byte("extra"))
+	if !a.Equal(&b) {
+		t.Fatal("removing the extra element did not restore the digest")
+	}
+
+	// Duplicates count: {x, x} differs from {x}.
+	b.AddString("0")
+	if a.Equal(&b) {
+		t.Fatal("duplicate element did not change the digest")
+	}
+	b.RemoveString("0")
+
+	for i := 0; i < 100; i++ {
+		a.RemoveString(fmt.Sprint(i))
+	}
+	var empty SetHash
+	if !a.Equal(&empty) || a.Len() != 0 {
+		t.Fatal("removing every element did not give the empty digest")
+	}
+}
+
+func TestSetHashMerge(t *testing.T) {
+	var all, x, y SetHash
+	for i := 0; i < 10; i++ {
+		all.AddString(fmt.Sprint(i))
+		if i%2 == 0 {
+			x.AddString(fmt.Sprint(i))
+		} else {
+			y.AddString(fmt.Sprint(i))
+		}
+	}
+	x.Merge(&y)
+	if !x.Equal(&all) {
+		t.Fatal("merged digest differs from the digest of the union")
+	}
+}
+
+func TestSetHashSum(t *testing.T) {
+	var s SetHash
+	if got := s.Sum([]byte("x")); !bytes.Equal(got, append([]byte("x"), make([]byte, 32)...)) {
+		t.Errorf("empty Sum = %x", got)
+	}
+	s.AddString("abc")
+	var want []byte
+	for i := uint64(0); i < 4; i++ {
+		want = appendUint64(want, Sum64StringWithSeed("abc", i))
+	}
+	if got := s.Sum(nil); !bytes.Equal(got, want) {
+		t.Errorf("Sum = %x; want %x", got, want)
+	}
+}
@@ -0,0 +1,17 @@
+package xxhash
+
+import "time"
+
+// Sum64Time computes the XXH64 digest of the instant t: the digest of the
+// 16-byte little-endian encoding of t.Unix() followed by t.Nanosecond(),
+// computed without building it. It ignores t's location and monotonic clock
+// reading, so times that are Equal have the same digest.
+func Sum64Time(t time.Time) uint64 {
+	return Sum64Pair(uint64(t.Unix()), uint64(t.Nanosecond()))
+}
+
+// Sum64UUID computes the XXH64 digest of the 16 bytes of u, such as a UUID.
+// It equals Sum64(u[:]).
+func Sum64UUID(u [16]byte) uint64 {
+	return Sum64(u[:])
+}
@@ -0,0 +1,50 @@
+//go:build go1.18
+// +build go1.18
+
+package xxhash
+
+import "net/netip"
+
+// Sum64Addr computes the XXH64 digest of a, encoded as the 16 bytes of
+// a.As16(), one byte that is 4 for an IPv4 address, 6 for an IPv6 address,
+// and 0 for the zero Addr, and then the zone. Addrs that are == have the same
+// digest; in particular an IPv4 address and its IPv4-mapped IPv6 form differ.
+func Sum64Addr(a netip.Addr) uint64 {
+	b := addrKey(a)
+	return sumAddrKey(b[:], a.Zone())
+}
+
+// Sum64AddrPort computes the XXH64 digest of ap, encoded like Sum64Addr of
+// ap.Addr() but with the port, as 2 little-endian bytes, before the zone.
+func Sum64AddrPort(ap netip.AddrPort) uint64 {
+	a := ap.Addr()
+	var b [19]byte
+	k := addrKey(a)
+	copy(b[:], k[:])
+	b[17], b[18] = byte(ap.Port()), byte(ap.Port()>>8)
+	return sumAddrKey(b[:], a.Zone())
+}
+
+func addrKey(a netip.Addr) [17]byte {
+	var b [17]byte
+	if a.IsValid() {
+		ip := a.As16()
+		copy(b[:], ip[:])
+		b[16] = 6
+		if a.Is4() {
+			b[16] = 4
+		}
+	}
+	return b
+}
+
+func sumAddrKey(b []byte, zone string) uint64 {
+	if zone == "" {
+		return Sum64(b)
+	}
+	var d Digest
+	d.Reset()
+	d.Write(b)
+	d.WriteString(zone)
+	return d.Sum64()
+}
@@ -0,0 +1,51 @@
+//go:build go1.18
+// +build go1.18
+
+package xxhash
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestSum64Addr(t *testing.T) {
+	key := func(a netip.Addr) []byte {
+		b := make([]byte, 17)
+		if a.IsValid() {
+			ip := a.As16()
+			copy(b, ip[:])
+			b[16] = 6
+			if a.Is4() {
+				b[16] = 4
+			}
+		}
+		return append(b, a.Zone()...)
+	}
+	seen := make(map[uint64]netip.Addr)
+	for _, s := range []string{"", "192.0.2.1", "::ffff:192.0.2.1", "2001:db8::1", "fe80::1%eth0", "fe80::1%eth1", "fe80::1"} {
+		var a netip.Addr
+		if s != "" {
+			a = netip.MustParseAddr(s)
+		}
+		got := Sum64Addr(a)
+		if want := Sum64(key(a)); got != want {
+			t.Errorf("Sum64Addr(%v) = 0x%x; want 0x%x", a, got, want)
+		}
+		if prev, ok := seen[got]; ok {
+			t.Errorf("%v and %v have the same digest", a, prev)
+		}
+		seen[got] = a
+
+		ap := netip.AddrPortFrom(a, 443)
+		k := key(a)
+		want := Sum64(append(append(k[:17:17], 443&0xff, 443>>8), a.Zone()...))
+		if got := Sum64AddrPort(ap); got != want {
+			t.Errorf("Sum64AddrPort(%v) = 0x%x; want 0x%x", ap, got, want)
+		}
+	}
+
+	a := netip.MustParseAddr("fe80::1%eth0")
+	ap := netip.AddrPortFrom(a, 80)
+	testAllocs(t, func() { sink = Sum64Addr(a) })
+	testAllocs(t, func() { sink = Sum64AddrPort(ap) })
+}
@@ -0,0 +1,39 @@
+package xxhash
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSum64Time(t *testing.T) {
+	for _, tm := range []time.Time{
+		{},
+		time.Unix(0, 0),
+		time.Unix(1700000000, 123456789),
+		time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+		time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		want := Sum64(appendUint64(appendUint64(nil, uint64(tm.Unix())), uint64(tm.Nanosecond())))
+		if got := Sum64Time(tm); got != want {
+			t.Errorf("Sum64Time(%v) = 0x%x; want 0x%x", tm, got, want)
+		}
+	}
+
+	// The location and monotonic reading do not matter.
+	now := time.Now()
+	if Sum64Time(now) != Sum64Time(now.Round(0).In(time.FixedZone("X", 3600))) {
+		t.Error("equal times have different digests")
+	}
+	if Sum64Time(now) == Sum64Time(now.Add(1)) {
+		t.Error("times 1ns apart have the same digest")
+	}
+	testAllocs(t, func() { sink = Sum64Time(now) })
+}
+
+func TestSum64UUID(t *testing.T) {
+	u := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	if got, want := Sum64UUID(u), Sum64(u[:]); got != want {
+		t.Errorf("got 0x%x; want 0x%x", got, want)
+	}
+	testAllocs(t, func() { sink = Sum64UUID(u) })
+}
@@ -0,0 +1,88 @@
+package xxhash
+
+// A TailDigest computes the XXH64 digest of the last n bytes written to it.
+// It keeps only those n bytes in memory, so it can fingerprint the end of an
+// unbounded stream in a single pass.
+//
+// TailDigest implements hash.Hash64.
+type TailDigest struct {
+	buf  []byte
+	pos  int  // index in buf where the next byte goes
+	full bool // whether buf has wrapped at least once
+}
+
+// NewTailDigest creates a TailDigest that hashes the final n bytes of its
+// input.
+func NewTailDigest(n int) *TailDigest {
+	if n < 0 {
+		panic("xxhash: negative tail size")
+	}
+	return &TailDigest{buf: make([]byte, n)}
+}
+
+// Reset discards all the bytes written so far.
+func (t *TailDigest) Reset() {
+	t.pos = 0
+	t.full = false
+}
+
+// Size always returns 8 bytes.
+func (t *TailDigest) Size() int { return 8 }
+
+// BlockSize always returns 32 bytes.
+func (t *TailDigest) BlockSize() int { return 32 }
+
+// Write adds more data to t. It always returns len(b), nil.
+func (t *TailDigest) Write(b []byte) (int, error) {
+	n := len(b)
+	if len(t.buf) == 0 {
+		return n, nil
+	}
+	if len(b) >= len(t.buf) {
+		copy(t.buf, b[len(b)-len(t.buf):])
+		t.pos = 0
+		t.full = true
+		return n, nil
+	}
+	c := copy(t.buf[t.pos:], b)
+	if c < len(b) {
+		t.pos = copy(t.buf, b[c:])
+		t.full = true
+	} else if t.pos += c; t.pos == len(t.buf) {
+		t.pos = 0
+		t.full = true
+	}
+	return n, nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (t *TailDigest) Sum(b []byte) []byte {
+	s := t.Sum64()
+	return append(
+		b,
+		byte(s>>56),
+		byte(s>>48),
+		byte(s>>40),
+		byte(s>>32),
+		byte(s>>24),
+		byte(s>>16),
+		byte(s>>8),
+		byte(s),
+	)
+}
+
+// Sum64 returns the XXH64 digest of the last n bytes written, or of all the
+// bytes written if there have been fewer than n.
+func (t *TailDigest) Sum64() uint64 {
+	if !t.full {
+		return Sum64(t.buf[:t.pos])
+	}
+	if t.pos == 0 {
+		return Sum64(t.buf)
+	}
+	var d Digest
+	d.Reset()
+	d.Write(t.buf[t.pos:])
+	d.Write(t.buf[:t.pos])
+	return d.Sum64()
+}
@@ -0,0 +1,59 @@
+package xxhash
+
+import (
+	"fmt"
+	"hash"
+	"testing"
+)
+
+var _ hash.Hash64 = (*TailDigest)(nil)
+
+func TestTailDigest(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 11)
+	}
+	for _, n := range []int{0, 1, 7, 32, 100} {
+		for _, total := range []int{0, 1, n - 1, n, n + 1, 3*n + 5, len(data)} {
+			if total < 0 {
+				continue
+			}
+			for _, chunk := range []int{1, 3, n, 64} {
+				if chunk <= 0 {
+					continue
+				}
+				name := fmt.Sprintf("n=%d,total=%d,chunk=%d", n, total, chunk)
+				t.Run(name, func(t *testing.T) {
+					td := NewTailDigest(n)
+					in := data[:total]
+					for i := 0; i < len(in); i += chunk {
+						end := i + chunk
+						if end > len(in) {
+							end = len(in)
+						}
+						if m, err := td.Write(in[i:end]); m != end-i || err != nil {
+							t.Fatalf("Write: got (%d, %v)", m, err)
+						}
+					}
+					tail := in
+					if len(tail) > n {
+						tail = tail[len(tail)-n:]
+					}
+					if got, want := td.Sum64(), Sum64(tail); got != want {
+						t.Fatalf("got 0x%x; want 0x%x", got, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestTailDigestReset(t *testing.T) {
+	td := NewTailDigest(8)
+	td.Write([]byte("0123456789"))
+	td.Reset()
+	td.Write([]byte("abc"))
+	if got, want := td.Sum64(), Sum64String("abc"); got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+}
@@ -0,0 +1,194 @@
+// Package tarhash computes stable identities of tar archives, such as
+// container image layers, with XXH64 in the same pass that reads or writes
+// them: the digest of each entry's content and an aggregate digest of the
+// whole archive.
+//
+// The aggregate digest covers, for each entry in archive order, its
+// normalized header and the digest of its content. The normalized header is
+// the type flag, name, link name, mode, user and group IDs and names, size,
+// and device numbers; modification, access, and change times and the header
+// format are excluded, so rebuilding an archive with the same files at a
+// different time does not change its identity. Concretely, the aggregate is
+// the XXH64 digest (seed 0) of, for each entry,
+//
+//	typeflag, name, linkname, mode, uid, gid, uname, gname, size,
+//	devmajor, devminor, content digest
+//
+// with each integer encoded as a little-endian uint64 and each string as its
+// length (likewise encoded) followed by its bytes.
+package tarhash
+
+import (
+	"archive/tar"
+	"io"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// An Entry is the identity of one archive entry.
+type Entry struct {
+	Name string
+	Size int64
+	Sum  uint64 // XXH64 digest of the entry's content
+}
+
+// archive accumulates the digests for a Reader or Writer. The zero value is
+// ready to use.
+type archive struct {
+	agg     xxhash.Digest
+	content xxhash.Digest
+	hdr     *tar.Header
+	entries []Entry
+}
+
+func (a *archive) start(hdr *tar.Header) {
+	a.hdr = hdr
+	a.content.Reset()
+}
+
+func (a *archive) finish() {
+	if a.hdr == nil {
+		return
+	}
+	h := a.hdr
+	sum := a.content.Sum64()
+	d := &a.agg
+	d.WriteUint64(uint64(h.Typeflag))
+	writeString(d, h.Name)
+	writeString(d, h.Linkname)
+	d.WriteUint64(uint64(h.Mode))
+	d.WriteUint64(uint64(h.Uid))
+	d.WriteUint64(uint64(h.Gid))
+	writeString(d, h.Uname)
+	writeString(d, h.Gname)
+	d.WriteUint64(uint64(h.Size))
+	d.WriteUint64(uint64(h.Devmajor))
+	d.WriteUint64(uint64(h.Devminor))
+	d.WriteUint64(sum)
+	a.entries = append(a.entries, Entry{Name: h.Name, Size: h.Size, Sum: sum})
+	a.hdr = nil
+}
+
+func writeString(d *xxhash.Digest, s string) {
+	d.WriteUint64(uint64(len(s)))
+	d.WriteString(s)
+}
+
+// A Reader reads a tar archive like tar.Reader and hashes it.
+type Reader struct {
+	tr *tar.Reader
+	a  archive
+}
+
+// NewReader returns a Reader that reads the tar archive r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{tr: tar.NewReader(r)}
+}
+
+// Next advances to the next entry, like tar.Reader.Next. It first reads any
+// unread content of the current entry, so that the whole content is hashed.
+func (r *Reader) Next() (*tar.Header, error) {
+	if r.a.hdr != nil {
+		if _, err := io.Copy(&r.a.content, r.tr); err != nil {
+			return nil, err
+		}
+		r.a.finish()
+	}
+	hdr, err := r.tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	r.a.start(hdr)
+	return hdr, nil
+}
+
+// Read reads from the current entry, like tar.Reader.Read.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.tr.Read(p)
+	r.a.content.Write(p[:n])
+	return n, err
+}
+
+// Entries returns the identities of the entries read so far, not including
+// the current one.
+func (r *Reader) Entries() []Entry { return r.a.entries }
+
+// Sum64 returns the aggregate digest of the entries read so far, not
+// including the current one. Once Next has returned io.EOF, it is the digest
+// of the whole archive.
+func (r *Reader) Sum64() uint64 { return r.a.agg.Sum64() }
+
+// A Writer writes a tar archive like tar.Writer and hashes it.
+type Writer struct {
+	tw *tar.Writer
+	a  archive
+}
+
+// NewWriter returns a Writer that writes a tar archive to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{tw: tar.NewWriter(w)}
+}
+
+// WriteHeader writes hdr and prepares to accept the entry's content, like
+// tar.Writer.WriteHeader.
+func (w *Writer) WriteHeader(hdr *tar.Header) error {
+	if err := w.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	w.a.finish()
+	h := *hdr
+	w.a.start(&h)
+	return nil
+}
+
+// Write writes to the current entry, like tar.Writer.Write.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.tw.Write(p)
+	w.a.content.Write(p[:n])
+	return n, err
+}
+
+// Close closes the archive, like tar.Writer.Close.
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	w.a.finish()
+	return nil
+}
+
+// Entries returns the identities of the entries written so far, not
+// including the current one until Close.
+func (w *Writer) Entries() []Entry { return w.a.entries }
+
+// Sum64 returns the aggregate digest of the entries written so far. After a
+// successful Close, it is the digest of the whole archive.
+func (w *Writer) Sum64() uint64 { return w.a.agg.Sum64() }
+
+// Sum reads the tar archive r to the end and returns the identities of its
+// entries and its aggregate digest.
+func Sum(r io.Reader) ([]Entry, uint64, error) {
+	tr := NewReader(r)
+	for {
+		if _, err := tr.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, 0, err
+		}
+	}
+	return tr.Entries(), tr.Sum64(), nil
+}
+
+// Check reads the tar archive r to the end and checks that its aggregate
+// digest is want. It returns a *xxhash.MismatchError if the digest differs,
+// or any error from reading the archive.
+func Check(r io.Reader, want uint64) error {
+	_, got, err := Sum(r)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return &xxhash.MismatchError{Got: got, Want: want}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package tarhash
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+type testFile struct {
+	hdr  tar.Header
+	body string
+}
+
+var testFiles = []testFile{
+	{tar.Header{Typeflag: tar.TypeDir, Name: "dir/", Mode: 0755}, ""},
+	{tar.Header{Typeflag: tar.TypeReg, Name: "dir/a", Mode: 0644, Uid: 1000, Gid: 1000, Uname: "u", Gname: "g", Size: 5}, "hello"},
+	{tar.Header{Typeflag: tar.TypeSymlink, Name: "link", Linkname: "dir/a", Mode: 0777}, ""},
+	{tar.Header{Typeflag: tar.TypeReg, Name: "big", Mode: 0600, Size: 100000}, string(make([]byte, 100000))},
+}
+
+// writeArchive writes files with Writer, with every header's times set to
+// mtime and its format set to format.
+func writeArchive(t *testing.T, files []testFile, mtime time.Time, format tar.Format) ([]byte, []Entry, uint64) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, f := range files {
+		hdr := f.hdr
+		hdr.ModTime = mtime
+		hdr.Format = format
+		if err := w.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, f.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), w.Entries(), w.Sum64()
+}
+
+// wantSum is a direct transcription of the package documentation.
+func wantSum(files []testFile) ([]Entry, uint64) {
+	var b []byte
+	u64 := func(v uint64) {
+		var x [8]byte
+		binary.LittleEndian.PutUint64(x[:], v)
+		b = append(b, x[:]...)
+	}
+	str := func(s string) {
+		u64(uint64(len(s)))
+		b = append(b, s...)
+	}
+	var entries []Entry
+	for _, f := range files {
+		h := f.hdr
+		sum := xxhash.Sum64String(f.body)
+		u64(uint64(h.Typeflag))
+		str(h.Name)
+		str(h.Linkname)
+		u64(uint64(h.Mode))
+		u64(uint64(h.Uid))
+		u64(uint64(h.Gid))
+		str(h.Uname)
+		str(h.Gname)
+		u64(uint64(h.Size))
+		u64(uint64(h.Devmajor))
+		u64(uint64(h.Devminor))
+		u64(sum)
+		entries = append(entries, Entry{h.Name, h.Size, sum})
+	}
+	return entries, xxhash.Sum64(b)
+}
+
+func TestWriterReader(t *testing.T) {
+	archive, entries, sum := writeArchive(t, testFiles, time.Unix(1e9, 0), tar.FormatPAX)
+	wantEntries, want := wantSum(testFiles)
+	if !reflect.DeepEqual(entries, wantEntries) || sum != want {
+		t.Fatalf("Writer: got (%v, 0x%x); want (%v, 0x%x)", entries, sum, wantEntries, want)
+	}
+
+	// Reading the archive gives the same result whether or not the content
+	// is read.
+	for _, readContent := range []bool{false, true} {
+		r := NewReader(bytes.NewReader(archive))
+		for i := 0; ; i++ {
+			hdr, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if readContent || i == 1 {
+				body, err := ioutil.ReadAll(r)
+				if err != nil || string(body) != testFiles[i].body {
+					t.Fatalf("%s: got (%d bytes, %v)", hdr.Name, len(body), err)
+				}
+			}
+		}
+		if got := r.Entries(); !reflect.DeepEqual(got, wantEntries) || r.Sum64() != want {
+			t.Errorf("Reader (content read: %v): got (%v, 0x%x); want (%v, 0x%x)", readContent, got, r.Sum64(), wantEntries, want)
+		}
+	}
+
+	gotEntries, got, err := Sum(bytes.NewReader(archive))
+	if err != nil || got != want || !reflect.DeepEqual(gotEntries, wantEntries) {
+		t.Errorf("Sum: got (%v, 0x%x, %v); want (%v, 0x%x, nil)", gotEntries, got, err, wantEntries, want)
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	_, _, sum := writeArchive(t, testFiles, time.Unix(1e9, 0), tar.FormatPAX)
+	if _, _, got := writeArchive(t, testFiles, time.Unix(2e9, 0), tar.FormatGNU); got != sum {
+		t.Errorf("changing times and format changed the digest")
+	}
+
+	renamed := append([]testFile(nil), testFiles...)
+	renamed[1].hdr.Name = "dir/b"
+	edited := append([]testFile(nil), testFiles...)
+	edited[1].body = "jello"
+	reordered := []testFile{testFiles[0], testFiles[2], testFiles[1], testFiles[3]}
+	for name, files := range map[string][]testFile{"renamed": renamed, "edited": edited, "reordered": reordered} {
+		if _, _, got := writeArchive(t, files, time.Unix(1e9, 0), tar.FormatPAX); got == sum {
+			t.Errorf("%s archive has the same digest", name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	archive, _, sum := writeArchive(t, testFiles, time.Time{}, tar.FormatPAX)
+	if err := Check(bytes.NewReader(archive), sum); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	err := Check(bytes.NewReader(archive), sum^1)
+	if me, ok := err.(*xxhash.MismatchError); !ok || me.Got != sum {
+		t.Errorf("wrong digest: got %v; want a *xxhash.MismatchError", err)
+	}
+	if err := Check(bytes.NewReader(archive[:700]), sum); err == nil {
+		t.Error("truncated archive: got nil error")
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	archive, entries, sum := writeArchive(t, nil, time.Time{}, tar.FormatPAX)
+	if len(entries) != 0 || sum != xxhash.Sum64(nil) {
+		t.Errorf("Writer: got (%v, 0x%x)", entries, sum)
+	}
+	if entries, got, err := Sum(bytes.NewReader(archive)); len(entries) != 0 || got != sum || err != nil {
+		t.Errorf("Sum: got (%v, 0x%x, %v)", entries, got, err)
+	}
+}
@@ -0,0 +1,93 @@
+package xxhash
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SumTree64 computes a tree hash of the first size bytes of r, hashing
+// chunkSize-byte chunks concurrently. The result is not the XXH64 digest of
+// the data, but it depends only on the data and chunkSize, and it is defined
+// as follows so that other implementations can reproduce it:
+//
+// The data is split into ceil(size/chunkSize) chunks, all of chunkSize bytes
+// except possibly the last; empty data has no chunks. Each chunk is hashed
+// with XXH64 (seed 0). The result is the XXH64 digest (seed 0) of the
+// concatenation of chunkSize, size, and the chunk digests in order, each
+// encoded as a little-endian uint64.
+//
+// SumTree64 uses up to GOMAXPROCS goroutines, each with a chunkSize-byte
+// buffer. It returns an error if chunkSize is not positive, if size is
+// negative, or if reading fails.
+func SumTree64(r io.ReaderAt, size, chunkSize int64) (uint64, error) {
+	if chunkSize <= 0 {
+		return 0, errors.New("xxhash: tree chunk size must be positive")
+	}
+	if size < 0 {
+		return 0, errors.New("xxhash: negative tree input size")
+	}
+	nchunks := (size + chunkSize - 1) / chunkSize
+	leaves := make([]uint64, nchunks)
+
+	workers := int64(runtime.GOMAXPROCS(0))
+	if workers > nchunks {
+		workers = nchunks
+	}
+	var (
+		next    int64 = -1
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		err     error
+		failed  int32
+	)
+	for w := int64(0); w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, chunkSize)
+			for atomic.LoadInt32(&failed) == 0 {
+				i := atomic.AddInt64(&next, 1)
+				if i >= nchunks {
+					return
+				}
+				off := i * chunkSize
+				b := buf
+				if size-off < chunkSize {
+					b = buf[:size-off]
+				}
+				if n, rerr := r.ReadAt(b, off); n < len(b) {
+					if rerr == nil || rerr == io.EOF {
+						rerr = io.ErrUnexpectedEOF
+					}
+					errOnce.Do(func() { err = rerr })
+					atomic.StoreInt32(&failed, 1)
+					return
+				}
+				leaves[i] = Sum64(b)
+			}
+		}()
+	}
+	wg.Wait()
+	if err != nil {
+		return 0, err
+	}
+
+	return treeRoot(chunkSize, size, leaves), nil
+}
+
+// treeRoot returns the SumTree64 result for the given chunk digests.
+func treeRoot(chunkSize, size int64, leaves []uint64) uint64 {
+	var d Digest
+	d.Reset()
+	var a [8]byte
+	for _, x := range []uint64{uint64(chunkSize), uint64(size)} {
+		d.Write(appendUint64(a[:0], x))
+	}
+	for _, h := range leaves {
+		d.Write(appendUint64(a[:0], h))
+	}
+	return d.Sum64()
+}
@@ -0,0 +1,84 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// treeSum64 is a direct transcription of the SumTree64 definition.
+func treeSum64(b []byte, chunkSize int) uint64 {
+	buf := appendUint64(nil, uint64(chunkSize))
+	buf = appendUint64(buf, uint64(len(b)))
+	for len(b) > 0 {
+		n := chunkSize
+		if n > len(b) {
+			n = len(b)
+		}
+		buf = appendUint64(buf, Sum64(b[:n]))
+		b = b[n:]
+	}
+	return Sum64(buf)
+}
+
+func TestSumTree64(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 4} {
+		runtime.GOMAXPROCS(procs)
+		for _, n := range []int{0, 1, 999, 1000, 1001, 10000} {
+			for _, chunkSize := range []int{1, 7, 1000, 20000} {
+				if n > 1000 && chunkSize == 1 {
+					continue
+				}
+				t.Run(fmt.Sprintf("procs=%d,n=%d,chunk=%d", procs, n, chunkSize), func(t *testing.T) {
+					got, err := SumTree64(bytes.NewReader(data[:n]), int64(n), int64(chunkSize))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if want := treeSum64(data[:n], chunkSize); got != want {
+						t.Fatalf("got 0x%x; want 0x%x", got, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSumTree64Format(t *testing.T) {
+	// Pin the definition so that it cannot change by accident.
+	got, err := SumTree64(bytes.NewReader([]byte("hello, tree")), 11, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want uint64 = 0x073aae2b941dd5ad
+	if got != want {
+		t.Fatalf("got 0x%x; want 0x%x", got, want)
+	}
+}
+
+type errReaderAt struct{ err error }
+
+func (r errReaderAt) ReadAt(p []byte, off int64) (int, error) { return 0, r.err }
+
+func TestSumTree64Errors(t *testing.T) {
+	if _, err := SumTree64(bytes.NewReader(nil), 0, 0); err == nil {
+		t.Error("zero chunk size: got nil error")
+	}
+	if _, err := SumTree64(bytes.NewReader(nil), -1, 10); err == nil {
+		t.Error("negative size: got nil error")
+	}
+	errRead := errors.New("read failed")
+	if _, err := SumTree64(errReaderAt{errRead}, 100, 10); err != errRead {
+		t.Errorf("read error: got %v; want %v", err, errRead)
+	}
+	if _, err := SumTree64(bytes.NewReader(make([]byte, 50)), 100, 10); err != io.ErrUnexpectedEOF {
+		t.Errorf("short input: got %v; want %v", err, io.ErrUnexpectedEOF)
+	}
+}
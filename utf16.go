@@ -0,0 +1,27 @@
+package xxhash
+
+import "encoding/binary"
+
+// Sum64UTF16 computes the 64-bit xxHash digest of u encoded as UTF-16LE,
+// the little-endian encoding of its code units used by Windows and NTFS, so
+// that UTF-16 keys can be fingerprinted without converting them to UTF-8.
+// The result is the same on every platform and equals Sum64 of the raw
+// UTF-16LE bytes. The code units are hashed as they are, so unpaired
+// surrogates are allowed.
+func Sum64UTF16(u []uint16) uint64 {
+	var d Digest
+	d.Reset()
+	var buf [256]byte
+	for len(u) > 0 {
+		n := len(u)
+		if n > len(buf)/2 {
+			n = len(buf) / 2
+		}
+		for i, c := range u[:n] {
+			binary.LittleEndian.PutUint16(buf[2*i:], c)
+		}
+		d.Write(buf[:2*n])
+		u = u[n:]
+	}
+	return d.Sum64()
+}
@@ -0,0 +1,28 @@
+package xxhash
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestSum64UTF16(t *testing.T) {
+	for _, s := range []string{"", "a", `C:\Windows\System32`, "日本語\U0001F600", strings.Repeat("file name ", 50)} {
+		u := utf16.Encode([]rune(s))
+		var le []byte
+		for _, c := range u {
+			le = append(le, byte(c), byte(c>>8))
+		}
+		if got, want := Sum64UTF16(u), Sum64(le); got != want {
+			t.Errorf("%q: got 0x%x; want 0x%x", s, got, want)
+		}
+	}
+	// Unpaired surrogates are hashed as they are.
+	if got, want := Sum64UTF16([]uint16{0xd800, 'x'}), Sum64([]byte{0x00, 0xd8, 'x', 0}); got != want {
+		t.Errorf("unpaired surrogate: got 0x%x; want 0x%x", got, want)
+	}
+	u := utf16.Encode([]rune(strings.Repeat("x", 1000)))
+	testAllocs(t, func() {
+		sink = Sum64UTF16(u)
+	})
+}
@@ -0,0 +1,198 @@
+package xxhash
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Variant identifies an algorithm in the xxHash family.
+type Variant int
+
+// The algorithms implemented by this package.
+const (
+	XXH64    Variant = iota // 64-bit xxHash; see Digest
+	XXH32                   // 32-bit xxHash; see Digest32
+	XXH3_64                 // 64-bit XXH3; see DigestXXH3.Sum64
+	XXH3_128                // 128-bit XXH3; see DigestXXH3.Sum128
+)
+
+func (v Variant) String() string {
+	switch v {
+	case XXH64:
+		return "XXH64"
+	case XXH32:
+		return "XXH32"
+	case XXH3_64:
+		return "XXH3-64"
+	case XXH3_128:
+		return "XXH3-128"
+	}
+	return fmt.Sprintf("Variant(%d)", int(v))
+}
+
+// Size returns the size, in bytes, of a digest computed by v.
+func (v Variant) Size() int {
+	switch v {
+	case XXH32:
+		return 4
+	case XXH3_128:
+		return 16
+	}
+	return 8
+}
+
+func (v Variant) valid() bool { return v >= XXH64 && v <= XXH3_128 }
+
+// A VariantDigest is a streaming digest that computes any of the algorithms
+// in this package, selected when it is created. It implements hash.Hash; Sum
+// appends the digest in its canonical big-endian form, which is Size bytes
+// long.
+//
+// VariantDigest is meant for code that chooses an algorithm at runtime, such
+// as from configuration. Code that always uses one algorithm should use the
+// dedicated type instead.
+type VariantDigest struct {
+	variant Variant
+	seed    uint64
+	secret  []byte
+
+	d64 Digest
+	d32 Digest32
+	d3  DigestXXH3
+}
+
+// NewVariant creates a new VariantDigest that computes v with a seed of
+// zero and, for XXH3, the default secret. It panics if v is not one of the
+// defined Variants.
+func NewVariant(v Variant) *VariantDigest {
+	if !v.valid() {
+		panic("xxhash: invalid " + v.String())
+	}
+	d := &VariantDigest{variant: v}
+	d.Reset()
+	return d
+}
+
+// An Option configures a VariantDigest created by NewHasher.
+type Option func(*VariantDigest)
+
+// WithAlgorithm selects the algorithm. The default is XXH64.
+func WithAlgorithm(v Variant) Option {
+	return func(d *VariantDigest) { d.variant = v }
+}
+
+// WithSeed sets the seed. XXH32 seeds are 32 bits. An XXH3 digest cannot
+// have both a seed and a custom secret.
+func WithSeed(seed uint64) Option {
+	return func(d *VariantDigest) { d.seed = seed }
+}
+
+// WithSecret sets a custom secret for XXH3; see SumXXH3_64WithSecret. The
+// digest refers to secret rather than copying it.
+func WithSecret(secret []byte) Option {
+	return func(d *VariantDigest) { d.secret = secret }
+}
+
+// NewHasher creates a new VariantDigest configured by opts. It returns an
+// error if the options do not describe a valid configuration: an unknown
+// algorithm, a seed too large for XXH32, a secret for XXH32 or XXH64, a
+// secret that is too short, or both a seed and a secret for XXH3.
+func NewHasher(opts ...Option) (*VariantDigest, error) {
+	d := new(VariantDigest)
+	for _, opt := range opts {
+		opt(d)
+	}
+	if !d.variant.valid() {
+		return nil, errors.New("xxhash: invalid " + d.variant.String())
+	}
+	switch d.variant {
+	case XXH32, XXH64:
+		if d.variant == XXH32 && d.seed > 1<<32-1 {
+			return nil, fmt.Errorf("xxhash: seed %#x does not fit in XXH32's 32-bit seed", d.seed)
+		}
+		if d.secret != nil {
+			return nil, fmt.Errorf("xxhash: %s does not use a secret", d.variant)
+		}
+	case XXH3_64, XXH3_128:
+		if d.seed != 0 && d.secret != nil {
+			return nil, fmt.Errorf("xxhash: %s takes a seed or a secret, not both", d.variant)
+		}
+		if d.secret != nil && len(d.secret) < XXH3SecretSizeMin {
+			return nil, fmt.Errorf("xxhash: secret is %d bytes; want at least %d", len(d.secret), XXH3SecretSizeMin)
+		}
+	}
+	d.Reset()
+	return d, nil
+}
+
+// Variant returns the algorithm computed by d.
+func (d *VariantDigest) Variant() Variant { return d.variant }
+
+// Reset clears d's state so that it can be reused. It keeps the algorithm,
+// seed, and secret.
+func (d *VariantDigest) Reset() {
+	switch d.variant {
+	case XXH32:
+		d.d32.resetWithSeed(uint32(d.seed))
+	case XXH64:
+		d.d64.ResetWithSeed(d.seed)
+	default:
+		if d.secret != nil {
+			d.d3.ResetWithSecret(d.secret)
+		} else {
+			d.d3.ResetWithSeed(d.seed)
+		}
+	}
+}
+
+// Size returns the size of the selected algorithm's digest: 4 bytes for
+// XXH32, 16 bytes for XXH3_128, and 8 bytes otherwise.
+func (d *VariantDigest) Size() int { return d.variant.Size() }
+
+// BlockSize returns the block size of the selected algorithm.
+func (d *VariantDigest) BlockSize() int {
+	switch d.variant {
+	case XXH32:
+		return d.d32.BlockSize()
+	case XXH64:
+		return d.d64.BlockSize()
+	}
+	return d.d3.BlockSize()
+}
+
+// Write adds more data to d. It always returns len(b), nil.
+func (d *VariantDigest) Write(b []byte) (n int, err error) {
+	switch d.variant {
+	case XXH32:
+		return d.d32.Write(b)
+	case XXH64:
+		return d.d64.Write(b)
+	}
+	return d.d3.Write(b)
+}
+
+// WriteString adds more data to d. It always returns len(s), nil.
+func (d *VariantDigest) WriteString(s string) (n int, err error) {
+	switch d.variant {
+	case XXH32:
+		return d.d32.WriteString(s)
+	case XXH64:
+		return d.d64.WriteString(s)
+	}
+	return d.d3.WriteString(s)
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (d *VariantDigest) Sum(b []byte) []byte {
+	switch d.variant {
+	case XXH32:
+		return d.d32.Sum(b)
+	case XXH64:
+		return d.d64.Sum(b)
+	case XXH3_64:
+		return d.d3.Sum(b)
+	}
+	var a [16]byte
+	putUint128BE(a[:], d.d3.Sum128())
+	return append(b, a[:]...)
+}
@@ -0,0 +1,120 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash"
+	"testing"
+)
+
+var _ hash.Hash = (*VariantDigest)(nil)
+
+func TestVariantDigest(t *testing.T) {
+	secret := GenerateXXH3Secret([]byte("variant"), XXH3SecretSizeMin)
+	for _, n := range []int{0, 3, 31, 32, 100, 241, 5000} {
+		input := xxh3TestInput(n)
+		h3 := SumXXH3_128(input)
+		h3s := SumXXH3_128WithSecret(input, secret)
+		h3seed := SumXXH3_128WithSeed(input, 7)
+		for _, tt := range []struct {
+			opts []Option
+			size int
+			want []byte
+		}{
+			{nil, 8, be64(Sum64(input))},
+			{[]Option{WithSeed(7)}, 8, be64(Sum64WithSeed(input, 7))},
+			{[]Option{WithAlgorithm(XXH32)}, 4, be32(Sum32(input))},
+			{[]Option{WithAlgorithm(XXH3_64)}, 8, be64(SumXXH3_64(input))},
+			{[]Option{WithAlgorithm(XXH3_128)}, 16, append(be64(h3.Hi), be64(h3.Lo)...)},
+			{[]Option{WithAlgorithm(XXH3_64), WithSecret(secret)}, 8, be64(SumXXH3_64WithSecret(input, secret))},
+			{[]Option{WithAlgorithm(XXH3_64), WithSeed(7)}, 8, be64(SumXXH3_64WithSeed(input, 7))},
+			{[]Option{WithAlgorithm(XXH3_128), WithSecret(secret)}, 16, append(be64(h3s.Hi), be64(h3s.Lo)...)},
+			{[]Option{WithAlgorithm(XXH3_128), WithSeed(7)}, 16, append(be64(h3seed.Hi), be64(h3seed.Lo)...)},
+		} {
+			d, err := NewHasher(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d.Size(); got != tt.size {
+				t.Errorf("%s: Size: got %d; want %d", d.Variant(), got, tt.size)
+			}
+			// Write, Reset (keeping the configuration), then write again in
+			// two pieces.
+			d.Write([]byte("garbage"))
+			d.Reset()
+			d.Write(input[:n/2])
+			d.WriteString(string(input[n/2:]))
+			if got := d.Sum([]byte("x")); !bytes.Equal(got, append([]byte("x"), tt.want...)) {
+				t.Errorf("%s, len=%d: Sum: got %x; want 78%x", d.Variant(), n, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestNewVariant(t *testing.T) {
+	input := []byte("asdf")
+	d := NewVariant(XXH32)
+	d.Write(input)
+	if got, want := d.Sum(nil), be32(Sum32(input)); !bytes.Equal(got, want) {
+		t.Errorf("XXH32: got %x; want %x", got, want)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("NewVariant(Variant(10)) did not panic")
+			}
+		}()
+		NewVariant(Variant(10))
+	}()
+}
+
+func TestVariantSeed32(t *testing.T) {
+	d, err := NewHasher(WithAlgorithm(XXH32), WithSeed(0x9e3779b9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteString("Call me Ishmael. Some years ago--never mind how long precisely-")
+	if got, want := d.Sum(nil), be32(0x448833a3); !bytes.Equal(got, want) {
+		t.Errorf("got %x; want %x", got, want)
+	}
+}
+
+func TestNewHasherErrors(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithAlgorithm(Variant(-1))},
+		{WithAlgorithm(XXH32), WithSeed(1 << 32)},
+		{WithAlgorithm(XXH64), WithSecret(make([]byte, 200))},
+		{WithAlgorithm(XXH3_64), WithSeed(1), WithSecret(make([]byte, 200))},
+		{WithAlgorithm(XXH3_128), WithSecret(make([]byte, 10))},
+	} {
+		if d, err := NewHasher(opts...); err == nil {
+			t.Errorf("NewHasher returned %s digest; want error", d.Variant())
+		}
+	}
+}
+
+func TestVariantString(t *testing.T) {
+	for v, want := range map[Variant]string{
+		XXH64:       "XXH64",
+		XXH32:       "XXH32",
+		XXH3_64:     "XXH3-64",
+		XXH3_128:    "XXH3-128",
+		Variant(20): "Variant(20)",
+	} {
+		if got := v.String(); got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	}
+}
+
+func be64(x uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], x)
+	return b[:]
+}
+
+func be32(x uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], x)
+	return b[:]
+}
@@ -0,0 +1,97 @@
+package xxhash
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrChecksumMismatch is returned by a reader created by NewVerifyingReader
+// when the data it read does not have the expected digest.
+var ErrChecksumMismatch = errors.New("xxhash: checksum mismatch")
+
+// A MismatchError reports that a computed digest did not match the expected
+// value.
+type MismatchError struct {
+	Got  uint64 // the digest of the data that was actually seen
+	Want uint64 // the digest the caller expected
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("xxhash: checksum mismatch: got %016x, want %016x", e.Got, e.Want)
+}
+
+// EqualConstantTime reports whether a == b in time that does not depend on
+// the values of a and b.
+//
+// xxHash is not a cryptographic hash, and its digests provide no secrecy or
+// collision resistance against an adversary. Constant-time comparison only
+// matters in threat models where the comparison itself must not leak timing
+// information about a secret-derived digest.
+func EqualConstantTime(a, b uint64) bool {
+	x := a ^ b
+	// (x | -x) has its top bit set iff x != 0.
+	return (x|-x)>>63 == 0
+}
+
+// ChecksumsEqual reports whether the canonical digests a and b (as produced
+// by Digest.Sum) are equal, in time that depends only on their lengths. See
+// EqualConstantTime for caveats.
+func ChecksumsEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// NewVerifyingReader returns a reader that reads from r and checks that the
+// XXH64 digest of everything read is want. Once r returns io.EOF, the
+// returned reader returns io.EOF if the digest matches and
+// ErrChecksumMismatch otherwise.
+//
+// The data is returned as it is read, before it can be verified, so callers
+// must not act on it irrevocably until they have seen io.EOF.
+func NewVerifyingReader(r io.Reader, want uint64) io.Reader {
+	v := &verifyingReader{want: want}
+	v.Reset(r)
+	return v
+}
+
+type verifyingReader struct {
+	Reader
+	want uint64
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF && r.Sum64() != r.want {
+		err = ErrChecksumMismatch
+	}
+	return n, err
+}
+
+// CheckReader reads r to EOF and checks that the XXH64 digest of its
+// contents is want. It returns a *MismatchError if the digest differs, or
+// the error from reading r.
+func CheckReader(r io.Reader, want uint64) error {
+	got, _, err := Sum64Reader(r)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return &MismatchError{Got: got, Want: want}
+	}
+	return nil
+}
+
+// CheckFile checks that the XXH64 digest of the named file is want, reading
+// it as Sum64File does. It returns a *MismatchError if the digest differs,
+// or the error from opening or reading the file.
+func CheckFile(path string, want uint64) error {
+	got, err := Sum64File(path)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return &MismatchError{Got: got, Want: want}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEqualConstantTime(t *testing.T) {
+	for _, tt := range []struct {
+		a, b uint64
+		want bool
+	}{
+		{0, 0, true},
+		{1, 1, true},
+		{^uint64(0), ^uint64(0), true},
+		{0, 1, false},
+		{1, 0, false},
+		{0, 1 << 63, false},
+		{1 << 63, 0, false},
+		{0xef46db3751d8e999, 0xef46db3751d8e998, false},
+	} {
+		if got := EqualConstantTime(tt.a, tt.b); got != tt.want {
+			t.Errorf("EqualConstantTime(0x%x, 0x%x): got %t; want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestChecksumsEqual(t *testing.T) {
+	a := New().Sum(nil)
+	b := New().Sum(nil)
+	if !ChecksumsEqual(a, b) {
+		t.Error("equal checksums compared unequal")
+	}
+	d := New()
+	d.WriteString("x")
+	if ChecksumsEqual(a, d.Sum(nil)) {
+		t.Error("different checksums compared equal")
+	}
+	if ChecksumsEqual(a, a[:7]) {
+		t.Error("checksums of different lengths compared equal")
+	}
+}
+
+func TestVerifyingReader(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 11)
+	}
+	want := Sum64(data)
+	for _, tt := range []struct {
+		want    uint64
+		wantErr error
+	}{
+		{want, nil},
+		{want + 1, ErrChecksumMismatch},
+	} {
+		for _, src := range []io.Reader{
+			bytes.NewReader(data),
+			iotest.DataErrReader(bytes.NewReader(data)),
+		} {
+			got, err := ioutil.ReadAll(NewVerifyingReader(src, tt.want))
+			if err != tt.wantErr {
+				t.Fatalf("want=0x%x: got error %v; want %v", tt.want, err, tt.wantErr)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("wrong data read")
+			}
+		}
+	}
+}
+
+func TestVerifyingReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := NewVerifyingReader(errReader{errRead}, 0)
+	if _, err := r.Read(make([]byte, 10)); err != errRead {
+		t.Fatalf("got error %v; want %v", err, errRead)
+	}
+}
+
+func TestCheckReader(t *testing.T) {
+	data := []byte("hello, check")
+	want := Sum64(data)
+	if err := CheckReader(bytes.NewReader(data), want); err != nil {
+		t.Fatalf("matching digest: got %v", err)
+	}
+	err := CheckReader(bytes.NewReader(data), want+1)
+	if merr, ok := err.(*MismatchError); !ok || merr.Got != want || merr.Want != want+1 {
+		t.Fatalf("mismatched digest: got %v", err)
+	}
+	errRead := errors.New("read failed")
+	if err := CheckReader(errReader{errRead}, want); err != errRead {
+		t.Fatalf("read error: got %v; want %v", err, errRead)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	data := []byte("hello, check")
+	f := writeTempFile(t, data)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	want := Sum64(data)
+	if err := CheckFile(f.Name(), want); err != nil {
+		t.Fatalf("matching digest: got %v", err)
+	}
+	err := CheckFile(f.Name(), want^1)
+	if merr, ok := err.(*MismatchError); !ok || merr.Got != want || merr.Want != want^1 {
+		t.Fatalf("mismatched digest: got %v", err)
+	}
+	if err := CheckFile(f.Name()+".missing", want); !os.IsNotExist(err) {
+		t.Fatalf("missing file: got %v", err)
+	}
+}
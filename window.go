@@ -0,0 +1,53 @@
+package xxhash
+
+import (
+	"errors"
+	"io"
+)
+
+// Sum64Windows reads r until EOF and computes an independent XXH64 digest of
+// each consecutive window-byte segment of the stream.
+//
+// For each window, onWindow is called with the window's zero-based index, its
+// digest, and its length n. Every window but the last has n == window; the
+// last window may be shorter. An empty stream produces no calls.
+//
+// If reading from r fails, Sum64Windows returns the error without calling
+// onWindow for the incomplete window. Sum64Windows does not buffer whole
+// windows, so window may be arbitrarily large.
+func Sum64Windows(r io.Reader, window int, onWindow func(index int, sum uint64, n int)) error {
+	if window <= 0 {
+		return errors.New("xxhash: window size must be positive")
+	}
+	bufSize := 32 * 1024
+	if window < bufSize {
+		bufSize = window
+	}
+	buf := make([]byte, bufSize)
+	var d Digest
+	d.Reset()
+	index, n := 0, 0
+	for {
+		want := window - n
+		if want > len(buf) {
+			want = len(buf)
+		}
+		m, err := r.Read(buf[:want])
+		d.Write(buf[:m])
+		n += m
+		if n == window {
+			onWindow(index, d.Sum64(), n)
+			d.Reset()
+			index, n = index+1, 0
+		}
+		if err == io.EOF {
+			if n > 0 {
+				onWindow(index, d.Sum64(), n)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
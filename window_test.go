@@ -0,0 +1,91 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+type windowSum struct {
+	index int
+	sum   uint64
+	n     int
+}
+
+func collectWindows(t *testing.T, r io.Reader, window int) ([]windowSum, error) {
+	t.Helper()
+	var got []windowSum
+	err := Sum64Windows(r, window, func(index int, sum uint64, n int) {
+		got = append(got, windowSum{index, sum, n})
+	})
+	return got, err
+}
+
+func TestSum64Windows(t *testing.T) {
+	data := make([]byte, 100000)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	for _, tt := range []struct {
+		size, window int
+	}{
+		{0, 10},
+		{10, 10},
+		{25, 10},
+		{100, 1},
+		{100000, 1000},
+		{100000, 33333},
+		{100000, 50000},
+		{100000, 1 << 20},
+	} {
+		t.Run(fmt.Sprintf("size=%d,window=%d", tt.size, tt.window), func(t *testing.T) {
+			in := data[:tt.size]
+			// OneByteReader exercises reads that don't line up with
+			// window boundaries.
+			for _, r := range []io.Reader{bytes.NewReader(in), iotest.OneByteReader(bytes.NewReader(in))} {
+				got, err := collectWindows(t, r, tt.window)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var want []windowSum
+				for i := 0; i*tt.window < len(in); i++ {
+					w := in[i*tt.window:]
+					if len(w) > tt.window {
+						w = w[:tt.window]
+					}
+					want = append(want, windowSum{i, Sum64(w), len(w)})
+				}
+				if len(got) != len(want) {
+					t.Fatalf("got %d windows; want %d", len(got), len(want))
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("window %d: got %+v; want %+v", i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestSum64WindowsError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 25)), errReader{errRead})
+	got, err := collectWindows(t, r, 10)
+	if err != errRead {
+		t.Fatalf("got error %v; want %v", err, errRead)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d windows before the error; want 2", len(got))
+	}
+	if _, err := collectWindows(t, bytes.NewReader(nil), 0); err == nil {
+		t.Fatal("window size 0: got nil error")
+	}
+}
@@ -0,0 +1,86 @@
+package xxhash
+
+import (
+	"errors"
+	"io"
+)
+
+// A Writer is an io.Writer that writes to an underlying writer and computes
+// the XXH64 digest of everything it writes.
+type Writer struct {
+	w io.Writer
+	d Digest
+	n int64
+}
+
+// NewWriter creates a Writer that writes to dst.
+func NewWriter(dst io.Writer) *Writer {
+	w := new(Writer)
+	w.Reset(dst)
+	return w
+}
+
+// Reset discards w's state and makes it write to dst, so that it can be
+// reused.
+func (w *Writer) Reset(dst io.Writer) {
+	w.w = dst
+	w.d.Reset()
+	w.n = 0
+}
+
+// Write writes p to the underlying writer. Only the bytes that the underlying
+// writer accepted are hashed and counted. If the underlying writer returns a
+// count outside [0, len(p)], Write hashes nothing and returns an error, as
+// CopyWithSum does.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n < 0 || n > len(p) {
+		n = 0
+		if err == nil {
+			err = errInvalidWrite
+		}
+	}
+	w.d.Write(p[:n])
+	w.n += int64(n)
+	return n, err
+}
+
+// Sum64 returns the digest of the data written so far.
+func (w *Writer) Sum64() uint64 { return w.d.Sum64() }
+
+// Written returns the number of bytes written so far.
+func (w *Writer) Written() int64 { return w.n }
+
+// errInvalidWrite means that a write returned an impossible count, like
+// io.Copy's errInvalidWrite.
+var errInvalidWrite = errors.New("xxhash: invalid write result")
+
+// CopyWithSum copies from src to dst until either EOF is reached on src or an
+// error occurs, like io.Copy, and returns the number of bytes written, the
+// XXH64 digest of those bytes, and the first error encountered, if any. A
+// successful CopyWithSum returns err == nil, not err == io.EOF.
+//
+// CopyWithSum copies through a buffer that it reuses across calls.
+func CopyWithSum(dst io.Writer, src io.Reader) (written int64, sum uint64, err error) {
+	d := GetDigest()
+	defer PutDigest(d)
+	_, err = readAdaptive(src, minReadBufSize, maxReadBufSize, func(b []byte) error {
+		if len(b) == 0 {
+			return nil
+		}
+		nw, werr := dst.Write(b)
+		if nw < 0 || nw > len(b) {
+			nw = 0
+			if werr == nil {
+				werr = errInvalidWrite
+			}
+		}
+		d.Write(b[:nw])
+		written += int64(nw)
+		if werr == nil && nw != len(b) {
+			werr = io.ErrShortWrite
+		}
+		return werr
+	})
+	return written, d.Sum64(), err
+}
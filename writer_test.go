@@ -0,0 +1,132 @@
+package xxhash
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriter(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := w.Write(data[i:end]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("wrong data written")
+	}
+	if got, want := w.Sum64(), Sum64(data); got != want {
+		t.Fatalf("Sum64: got 0x%x; want 0x%x", got, want)
+	}
+	if w.Written() != int64(len(data)) {
+		t.Fatalf("Written: got %d; want %d", w.Written(), len(data))
+	}
+
+	var buf2 bytes.Buffer
+	w.Reset(&buf2)
+	w.Write([]byte("abc"))
+	if w.Sum64() != Sum64String("abc") || w.Written() != 3 || buf2.String() != "abc" {
+		t.Fatal("wrong state after Reset")
+	}
+}
+
+type shortWriter struct {
+	n   int
+	err error
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, w.err
+	}
+	return len(p), nil
+}
+
+func TestWriterShortWrite(t *testing.T) {
+	errWrite := errors.New("write failed")
+	w := NewWriter(&shortWriter{n: 4, err: errWrite})
+	n, err := w.Write([]byte("abcdefgh"))
+	if n != 4 || err != errWrite {
+		t.Fatalf("got (%d, %v); want (4, %v)", n, err, errWrite)
+	}
+	if w.Sum64() != Sum64String("abcd") || w.Written() != 4 {
+		t.Fatal("short write hashed or counted unwritten bytes")
+	}
+}
+
+func TestWriterInvalidWrite(t *testing.T) {
+	w := NewWriter(&shortWriter{n: -1})
+	n, err := w.Write([]byte("abcdefgh"))
+	if n != 0 || err == nil {
+		t.Fatalf("got (%d, %v); want (0, non-nil error)", n, err)
+	}
+	if w.Sum64() != Sum64String("") || w.Written() != 0 {
+		t.Fatal("invalid write hashed or counted bytes")
+	}
+}
+
+func TestWriterAllocs(t *testing.T) {
+	data := make([]byte, 1000)
+	w := NewWriter(ioutil.Discard)
+	testAllocs(t, func() {
+		w.Reset(ioutil.Discard)
+		w.Write(data)
+		sink = w.Sum64()
+	})
+}
+
+func TestCopyWithSum(t *testing.T) {
+	data := make([]byte, 3*maxReadBufSize+17)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	for _, n := range []int{0, 1, maxReadBufSize, len(data)} {
+		var buf bytes.Buffer
+		written, sum, err := CopyWithSum(&buf, iotest.HalfReader(bytes.NewReader(data[:n])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != int64(n) || sum != Sum64(data[:n]) || !bytes.Equal(buf.Bytes(), data[:n]) {
+			t.Fatalf("n=%d: got (%d, 0x%x); want (%d, 0x%x)", n, written, sum, n, Sum64(data[:n]))
+		}
+	}
+}
+
+func TestCopyWithSumErrors(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("abc"), errReader{errRead})
+	written, sum, err := CopyWithSum(ioutil.Discard, r)
+	if written != 3 || sum != Sum64String("abc") || err != errRead {
+		t.Fatalf("read error: got (%d, 0x%x, %v)", written, sum, err)
+	}
+
+	written, sum, err = CopyWithSum(&shortWriter{n: 4}, strings.NewReader("abcdefgh"))
+	if written != 4 || sum != Sum64String("abcd") || err != io.ErrShortWrite {
+		t.Fatalf("short write: got (%d, 0x%x, %v)", written, sum, err)
+	}
+}
+
+func TestCopyWithSumAllocs(t *testing.T) {
+	data := make([]byte, 100000)
+	br := bytes.NewReader(data)
+	var r io.Reader = struct{ io.Reader }{br}
+	testAllocs(t, func() {
+		br.Reset(data)
+		_, sum, _ := CopyWithSum(ioutil.Discard, r)
+		sink = sum
+	})
+}
@@ -0,0 +1,62 @@
+package xxhash
+
+import "encoding/binary"
+
+// An XOF is an unbounded pseudorandom byte stream determined by a 64-bit
+// digest, for deriving test data, salts, or probe sequences from a hash. It
+// is produced in counter mode: the stream is the concatenation of the
+// little-endian encodings of Sum64Pair(sum, i) for i = 0, 1, 2, ...
+//
+// The stream has only as much entropy as the digest and is not suitable for
+// cryptographic use.
+type XOF struct {
+	sum uint64
+	ctr uint64
+	buf [8]byte
+	n   int // number of unread bytes at the end of buf
+}
+
+// NewXOF returns an XOF whose stream is determined by d.Sum64(). It does not
+// change the underlying hash state.
+func (d *Digest) NewXOF() *XOF {
+	return NewXOF(d.Sum64())
+}
+
+// NewXOF returns an XOF whose stream is determined by sum.
+func NewXOF(sum uint64) *XOF {
+	return &XOF{sum: sum}
+}
+
+// Read fills p with the next len(p) bytes of the stream. It always returns
+// len(p), nil.
+func (x *XOF) Read(p []byte) (int, error) {
+	n := len(p)
+	if x.n > 0 {
+		m := copy(p, x.buf[8-x.n:])
+		x.n -= m
+		p = p[m:]
+	}
+	for len(p) >= 8 {
+		binary.LittleEndian.PutUint64(p, Sum64Pair(x.sum, x.ctr))
+		x.ctr++
+		p = p[8:]
+	}
+	if len(p) > 0 {
+		binary.LittleEndian.PutUint64(x.buf[:], Sum64Pair(x.sum, x.ctr))
+		x.ctr++
+		x.n = 8 - copy(p, x.buf[:])
+	}
+	return n, nil
+}
+
+// Uint64 returns the next 8 bytes of the stream as a little-endian uint64.
+func (x *XOF) Uint64() uint64 {
+	if x.n == 0 {
+		v := Sum64Pair(x.sum, x.ctr)
+		x.ctr++
+		return v
+	}
+	var b [8]byte
+	x.Read(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,53 @@
+package xxhash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXOF(t *testing.T) {
+	const sum = 0x44bc2cf5ad770999
+	var want []byte
+	for i := uint64(0); i < 20; i++ {
+		want = appendUint64(want, Sum64Pair(sum, i))
+	}
+
+	// Reads of any size see the same stream.
+	for _, size := range []int{1, 3, 8, 13, 160} {
+		x := NewXOF(sum)
+		var got []byte
+		for len(got) < len(want) {
+			p := make([]byte, size)
+			if n, err := x.Read(p); n != size || err != nil {
+				t.Fatalf("Read = (%d, %v); want (%d, nil)", n, err, size)
+			}
+			got = append(got, p...)
+		}
+		if !bytes.Equal(got[:len(want)], want) {
+			t.Fatalf("size=%d: got %x; want %x", size, got, want)
+		}
+	}
+
+	x := NewXOF(sum)
+	var b [3]byte
+	x.Read(b[:])
+	if got, want := x.Uint64(), u64(want[3:11]); got != want {
+		t.Errorf("unaligned Uint64 = 0x%x; want 0x%x", got, want)
+	}
+	x.Read(make([]byte, 5)) // realign
+	if got, want := x.Uint64(), u64(want[16:24]); got != want {
+		t.Errorf("aligned Uint64 = 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestDigestNewXOF(t *testing.T) {
+	d := New()
+	d.WriteString("abc")
+	x := d.NewXOF()
+	if got, want := x.Uint64(), Sum64Pair(Sum64String("abc"), 0); got != want {
+		t.Errorf("got 0x%x; want 0x%x", got, want)
+	}
+	if d.Sum64() != Sum64String("abc") {
+		t.Error("NewXOF changed the digest")
+	}
+}
@@ -0,0 +1,250 @@
+package xxhash
+
+import "math/bits"
+
+// This file implements the 64-bit variant of XXH3, the newer member of the
+// xxHash family. It follows the reference implementation (xxHash v0.8) and
+// produces identical results.
+
+const (
+	xxh3SecretSizeMin = 136
+	xxh3StripeLen     = 64
+	xxh3MidsizeMax    = 240
+
+	// Secret offsets used for inputs of 129 to 240 bytes.
+	xxh3MidsizeStartOffset = 3
+	xxh3MidsizeLastOffset  = 17
+
+	// Secret offset, from the end of the block secret, for the last stripe.
+	xxh3LastAccStart = 7
+
+	primeMx1 uint64 = 0x165667919e3779f9
+	primeMx2 uint64 = 0x9fb21c651e98df25
+)
+
+// xxh3Secret is the default secret, XXH3_kSecret in the reference
+// implementation.
+var xxh3Secret = [192]byte{
+	0xb8, 0xfe, 0x6c, 0x39, 0x23, 0xa4, 0x4b, 0xbe, 0x7c, 0x01, 0x81, 0x2c, 0xf7, 0x21, 0xad, 0x1c,
+	0xde, 0xd4, 0x6d, 0xe9, 0x83, 0x90, 0x97, 0xdb, 0x72, 0x40, 0xa4, 0xa4, 0xb7, 0xb3, 0x67, 0x1f,
+	0xcb, 0x79, 0xe6, 0x4e, 0xcc, 0xc0, 0xe5, 0x78, 0x82, 0x5a, 0xd0, 0x7d, 0xcc, 0xff, 0x72, 0x21,
+	0xb8, 0x08, 0x46, 0x74, 0xf7, 0x43, 0x24, 0x8e, 0xe0, 0x35, 0x90, 0xe6, 0x81, 0x3a, 0x26, 0x4c,
+	0x3c, 0x28, 0x52, 0xbb, 0x91, 0xc3, 0x00, 0xcb, 0x88, 0xd0, 0x65, 0x8b, 0x1b, 0x53, 0x2e, 0xa3,
+	0x71, 0x64, 0x48, 0x97, 0xa2, 0x0d, 0xf9, 0x4e, 0x38, 0x19, 0xef, 0x46, 0xa9, 0xde, 0xac, 0xd8,
+	0xa8, 0xfa, 0x76, 0x3f, 0xe3, 0x9c, 0x34, 0x3f, 0xf9, 0xdc, 0xbb, 0xc7, 0xc7, 0x0b, 0x4f, 0x1d,
+	0x8a, 0x51, 0xe0, 0x4b, 0xcd, 0xb4, 0x59, 0x31, 0xc8, 0x9f, 0x7e, 0xc9, 0xd9, 0x78, 0x73, 0x64,
+	0xea, 0xc5, 0xac, 0x83, 0x34, 0xd3, 0xeb, 0xc3, 0xc5, 0x81, 0xa0, 0xff, 0xfa, 0x13, 0x63, 0xeb,
+	0x17, 0x0d, 0xdd, 0x51, 0xb7, 0xf0, 0xda, 0x49, 0xd3, 0x16, 0x55, 0x26, 0x29, 0xd4, 0x68, 0x9e,
+	0x2b, 0x16, 0xbe, 0x58, 0x7d, 0x47, 0xa1, 0xfc, 0x8f, 0xf8, 0xb8, 0xd1, 0x7a, 0xd0, 0x31, 0xce,
+	0x45, 0xcb, 0x3a, 0x8f, 0x95, 0x16, 0x04, 0x28, 0xaf, 0xd7, 0xfb, 0xca, 0xbb, 0x4b, 0x40, 0x7e,
+}
+
+// xxh3DefaultSecret is xxh3Secret as a slice. Using it rather than slicing
+// the array at each call site keeps SumXXH3_64String within the inliner's
+// budget.
+var xxh3DefaultSecret = xxh3Secret[:]
+
+// xxh3InitAcc is the initial value of the accumulators for long inputs.
+var xxh3InitAcc = [8]uint64{
+	uint64(prime32_3), prime1, prime2, prime3,
+	prime4, uint64(prime32_2), prime5, uint64(prime32_1),
+}
+
+// SumXXH3_64 computes the 64-bit XXH3 digest of b.
+func SumXXH3_64(b []byte) uint64 {
+	return xxh3Hash64(b, xxh3DefaultSecret, 0)
+}
+
+// xxh3Hash64 computes the 64-bit XXH3 digest of b using the given secret and
+// seed. For inputs longer than xxh3MidsizeMax the seed is ignored: the caller
+// must have already derived the secret from it; see SumXXH3_64WithSeed.
+func xxh3Hash64(b, secret []byte, seed uint64) uint64 {
+	n := len(b)
+	switch {
+	case n <= 16:
+		return xxh3Len0To16_64(b, secret, seed)
+	case n <= 128:
+		return xxh3Len17To128_64(b, secret, seed)
+	case n <= xxh3MidsizeMax:
+		return xxh3Len129To240_64(b, secret, seed)
+	}
+	return xxh3HashLong64(b, secret)
+}
+
+func xxh3Len0To16_64(b, secret []byte, seed uint64) uint64 {
+	n := len(b)
+	switch {
+	case n > 8:
+		bitflip1 := (u64(secret[24:32]) ^ u64(secret[32:40])) + seed
+		bitflip2 := (u64(secret[40:48]) ^ u64(secret[48:56])) - seed
+		lo := u64(b[0:8]) ^ bitflip1
+		hi := u64(b[n-8:]) ^ bitflip2
+		acc := uint64(n) + bits.ReverseBytes64(lo) + hi + mul128Fold64(lo, hi)
+		return xxh3Avalanche(acc)
+	case n >= 4:
+		seed ^= uint64(bits.ReverseBytes32(uint32(seed))) << 32
+		in1 := u32(b[0:4])
+		in2 := u32(b[n-4:])
+		bitflip := (u64(secret[8:16]) ^ u64(secret[16:24])) - seed
+		in64 := uint64(in2) + uint64(in1)<<32
+		return rrmxmx(in64^bitflip, uint64(n))
+	case n > 0:
+		c1, c2, c3 := b[0], b[n>>1], b[n-1]
+		combined := uint32(c1)<<16 | uint32(c2)<<24 | uint32(c3) | uint32(n)<<8
+		bitflip := uint64(u32(secret[0:4])^u32(secret[4:8])) + seed
+		return avalanche(uint64(combined) ^ bitflip)
+	}
+	return avalanche(seed ^ u64(secret[56:64]) ^ u64(secret[64:72]))
+}
+
+func xxh3Len17To128_64(b, secret []byte, seed uint64) uint64 {
+	n := len(b)
+	acc := uint64(n) * prime1
+	if n > 32 {
+		if n > 64 {
+			if n > 96 {
+				acc += mix16B(b[48:], secret[96:], seed)
+				acc += mix16B(b[n-64:], secret[112:], seed)
+			}
+			acc += mix16B(b[32:], secret[64:], seed)
+			acc += mix16B(b[n-48:], secret[80:], seed)
+		}
+		acc += mix16B(b[16:], secret[32:], seed)
+		acc += mix16B(b[n-32:], secret[48:], seed)
+	}
+	acc += mix16B(b, secret, seed)
+	acc += mix16B(b[n-16:], secret[16:], seed)
+	return xxh3Avalanche(acc)
+}
+
+func xxh3Len129To240_64(b, secret []byte, seed uint64) uint64 {
+	n := len(b)
+	acc := uint64(n) * prime1
+	for i := 0; i < 8; i++ {
+		acc += mix16B(b[16*i:], secret[16*i:], seed)
+	}
+	accEnd := mix16B(b[n-16:], secret[xxh3SecretSizeMin-xxh3MidsizeLastOffset:], seed)
+	acc = xxh3Avalanche(acc)
+	for i := 8; i < n/16; i++ {
+		accEnd += mix16B(b[16*i:], secret[16*(i-8)+xxh3MidsizeStartOffset:], seed)
+	}
+	return xxh3Avalanche(acc + accEnd)
+}
+
+func xxh3HashLong64(b, secret []byte) uint64 {
+	acc := xxh3InitAcc
+	xxh3HashLongLoop(&acc, b, secret)
+	return xxh3MergeAccs(&acc, secret[11:], uint64(len(b))*prime1)
+}
+
+// xxh3HashLongLoop runs the accumulation loop over all of b, which must be
+// longer than one stripe.
+func xxh3HashLongLoop(acc *[8]uint64, b, secret []byte) {
+	stripesPerBlock := (len(secret) - xxh3StripeLen) / 8
+	blockLen := xxh3StripeLen * stripesPerBlock
+	blocks := (len(b) - 1) / blockLen
+	for i := 0; i < blocks; i++ {
+		xxh3Accumulate(acc, b[i*blockLen:], secret, stripesPerBlock)
+		xxh3ScrambleAcc(acc, secret[len(secret)-xxh3StripeLen:])
+	}
+
+	// Last partial block.
+	stripes := ((len(b) - 1) - blockLen*blocks) / xxh3StripeLen
+	xxh3Accumulate(acc, b[blocks*blockLen:], secret, stripes)
+
+	// Last stripe.
+	xxh3Accumulate512(acc, b[len(b)-xxh3StripeLen:], secret[len(secret)-xxh3StripeLen-xxh3LastAccStart:])
+}
+
+// xxh3AccumulateGeneric processes stripes consecutive 64-byte stripes of b,
+// advancing through secret by 8 bytes per stripe. xxh3Accumulate calls it or
+// an equivalent vector kernel.
+func xxh3AccumulateGeneric(acc *[8]uint64, b, secret []byte, stripes int) {
+	for i := 0; i < stripes; i++ {
+		xxh3Accumulate512(acc, b[i*xxh3StripeLen:], secret[i*8:])
+	}
+}
+
+func xxh3Accumulate512(acc *[8]uint64, b, secret []byte) {
+	b = b[:64:len(b)]
+	secret = secret[:64:len(secret)]
+
+	v0 := u64(b[0:8])
+	v1 := u64(b[8:16])
+	v2 := u64(b[16:24])
+	v3 := u64(b[24:32])
+	v4 := u64(b[32:40])
+	v5 := u64(b[40:48])
+	v6 := u64(b[48:56])
+	v7 := u64(b[56:64])
+
+	k0 := v0 ^ u64(secret[0:8])
+	k1 := v1 ^ u64(secret[8:16])
+	k2 := v2 ^ u64(secret[16:24])
+	k3 := v3 ^ u64(secret[24:32])
+	k4 := v4 ^ u64(secret[32:40])
+	k5 := v5 ^ u64(secret[40:48])
+	k6 := v6 ^ u64(secret[48:56])
+	k7 := v7 ^ u64(secret[56:64])
+
+	// Each lane accumulates the product of the two halves of its keyed input
+	// plus the raw input of its neighbor.
+	acc[0] += v1 + uint64(uint32(k0))*(k0>>32)
+	acc[1] += v0 + uint64(uint32(k1))*(k1>>32)
+	acc[2] += v3 + uint64(uint32(k2))*(k2>>32)
+	acc[3] += v2 + uint64(uint32(k3))*(k3>>32)
+	acc[4] += v5 + uint64(uint32(k4))*(k4>>32)
+	acc[5] += v4 + uint64(uint32(k5))*(k5>>32)
+	acc[6] += v7 + uint64(uint32(k6))*(k6>>32)
+	acc[7] += v6 + uint64(uint32(k7))*(k7>>32)
+}
+
+func xxh3ScrambleAccGeneric(acc *[8]uint64, secret []byte) {
+	secret = secret[:64:len(secret)]
+	for i := range acc {
+		a := acc[i]
+		a ^= a >> 47
+		a ^= u64(secret[8*i : 8*i+8])
+		a *= uint64(prime32_1)
+		acc[i] = a
+	}
+}
+
+func xxh3MergeAccs(acc *[8]uint64, secret []byte, start uint64) uint64 {
+	secret = secret[:64:len(secret)]
+	h := start
+	h += mul128Fold64(acc[0]^u64(secret[0:8]), acc[1]^u64(secret[8:16]))
+	h += mul128Fold64(acc[2]^u64(secret[16:24]), acc[3]^u64(secret[24:32]))
+	h += mul128Fold64(acc[4]^u64(secret[32:40]), acc[5]^u64(secret[40:48]))
+	h += mul128Fold64(acc[6]^u64(secret[48:56]), acc[7]^u64(secret[56:64]))
+	return xxh3Avalanche(h)
+}
+
+func mix16B(b, secret []byte, seed uint64) uint64 {
+	lo := u64(b[0:8])
+	hi := u64(b[8:16])
+	return mul128Fold64(lo^(u64(secret[0:8])+seed), hi^(u64(secret[8:16])-seed))
+}
+
+// mul128Fold64 returns the xor of the high and low halves of the 128-bit
+// product a*b.
+func mul128Fold64(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+func xxh3Avalanche(h uint64) uint64 {
+	h ^= h >> 37
+	h *= primeMx1
+	h ^= h >> 32
+	return h
+}
+
+func rrmxmx(h, n uint64) uint64 {
+	h ^= bits.RotateLeft64(h, 49) ^ bits.RotateLeft64(h, 24)
+	h *= primeMx2
+	h ^= h>>35 + n
+	h *= primeMx2
+	h ^= h >> 28
+	return h
+}
@@ -0,0 +1,194 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Hash128 is the common interface implemented by hash functions with a
+// 128-bit result, such as DigestXXH3.
+type Hash128 interface {
+	hash.Hash
+	Sum128() Uint128
+}
+
+// Uint128 is a 128-bit hash value, such as the result of SumXXH3_128.
+type Uint128 struct {
+	Hi uint64
+	Lo uint64
+}
+
+// Uint128FromBytes returns the Uint128 whose canonical representation (see
+// Bytes) is the first 16 bytes of b. It panics if b is shorter than 16 bytes.
+func Uint128FromBytes(b []byte) Uint128 {
+	_ = b[15] // bounds check hint to compiler; see golang.org/issue/14808
+	return Uint128{
+		Hi: binary.BigEndian.Uint64(b[0:8]),
+		Lo: binary.BigEndian.Uint64(b[8:16]),
+	}
+}
+
+// Bytes returns the canonical representation of h: Hi then Lo, each
+// big-endian, as written by the reference implementation
+// (XXH128_canonicalFromHash) and by xxhsum.
+func (h Uint128) Bytes() [16]byte {
+	var b [16]byte
+	putUint128BE(b[:], h)
+	return b
+}
+
+// PutBytes stores the canonical representation of h in the first 16 bytes of
+// b. It panics if b is shorter than 16 bytes.
+func (h Uint128) PutBytes(b []byte) {
+	_ = b[15] // bounds check hint to compiler; see golang.org/issue/14808
+	putUint128BE(b, h)
+}
+
+// Hex returns the canonical representation of h as 32 lowercase hexadecimal
+// digits, as printed by xxhsum.
+func (h Uint128) Hex() string {
+	var b [32]byte
+	return string(appendHex64(appendHex64(b[:0], h.Hi), h.Lo))
+}
+
+// Compare returns -1, 0, or +1 depending on whether h is less than, equal
+// to, or greater than g, comparing Hi first, like XXH128_cmp.
+func (h Uint128) Compare(g Uint128) int {
+	switch {
+	case h.Hi < g.Hi:
+		return -1
+	case h.Hi > g.Hi:
+		return 1
+	case h.Lo < g.Lo:
+		return -1
+	case h.Lo > g.Lo:
+		return 1
+	}
+	return 0
+}
+
+// SumXXH3_128 computes the 128-bit XXH3 digest of b.
+func SumXXH3_128(b []byte) Uint128 {
+	return xxh3Hash128(b, xxh3DefaultSecret, 0)
+}
+
+// xxh3Hash128 is the 128-bit counterpart of xxh3Hash64.
+func xxh3Hash128(b, secret []byte, seed uint64) Uint128 {
+	n := len(b)
+	switch {
+	case n <= 16:
+		return xxh3Len0To16_128(b, secret, seed)
+	case n <= 128:
+		return xxh3Len17To128_128(b, secret, seed)
+	case n <= xxh3MidsizeMax:
+		return xxh3Len129To240_128(b, secret, seed)
+	}
+	return xxh3HashLong128(b, secret)
+}
+
+func xxh3Len0To16_128(b, secret []byte, seed uint64) Uint128 {
+	n := len(b)
+	switch {
+	case n > 8:
+		bitflipl := (u64(secret[32:40]) ^ u64(secret[40:48])) - seed
+		bitfliph := (u64(secret[48:56]) ^ u64(secret[56:64])) + seed
+		inLo := u64(b[0:8])
+		inHi := u64(b[n-8:])
+		mHi, mLo := bits.Mul64(inLo^inHi^bitflipl, prime1)
+		mLo += uint64(n-1) << 54
+		inHi ^= bitfliph
+		mHi += inHi + uint64(uint32(inHi))*uint64(prime32_2-1)
+		mLo ^= bits.ReverseBytes64(mHi)
+		hHi, hLo := bits.Mul64(mLo, prime2)
+		hHi += mHi * prime2
+		return Uint128{Hi: xxh3Avalanche(hHi), Lo: xxh3Avalanche(hLo)}
+	case n >= 4:
+		seed ^= uint64(bits.ReverseBytes32(uint32(seed))) << 32
+		inLo := u32(b[0:4])
+		inHi := u32(b[n-4:])
+		in64 := uint64(inLo) + uint64(inHi)<<32
+		bitflip := (u64(secret[16:24]) ^ u64(secret[24:32])) + seed
+		hi, lo := bits.Mul64(in64^bitflip, prime1+uint64(n)<<2)
+		hi += lo << 1
+		lo ^= hi >> 3
+		lo ^= lo >> 35
+		lo *= primeMx2
+		lo ^= lo >> 28
+		return Uint128{Hi: xxh3Avalanche(hi), Lo: lo}
+	case n > 0:
+		c1, c2, c3 := b[0], b[n>>1], b[n-1]
+		combinedl := uint32(c1)<<16 | uint32(c2)<<24 | uint32(c3) | uint32(n)<<8
+		combinedh := bits.RotateLeft32(bits.ReverseBytes32(combinedl), 13)
+		bitflipl := uint64(u32(secret[0:4])^u32(secret[4:8])) + seed
+		bitfliph := uint64(u32(secret[8:12])^u32(secret[12:16])) - seed
+		return Uint128{
+			Hi: avalanche(uint64(combinedh) ^ bitfliph),
+			Lo: avalanche(uint64(combinedl) ^ bitflipl),
+		}
+	}
+	return Uint128{
+		Hi: avalanche(seed ^ u64(secret[80:88]) ^ u64(secret[88:96])),
+		Lo: avalanche(seed ^ u64(secret[64:72]) ^ u64(secret[72:80])),
+	}
+}
+
+func xxh3Len17To128_128(b, secret []byte, seed uint64) Uint128 {
+	n := len(b)
+	acc := Uint128{Lo: uint64(n) * prime1}
+	if n > 32 {
+		if n > 64 {
+			if n > 96 {
+				acc = mix32B(acc, b[48:], b[n-64:], secret[96:], seed)
+			}
+			acc = mix32B(acc, b[32:], b[n-48:], secret[64:], seed)
+		}
+		acc = mix32B(acc, b[16:], b[n-32:], secret[32:], seed)
+	}
+	acc = mix32B(acc, b, b[n-16:], secret, seed)
+	return xxh3Final128(acc, uint64(n), seed)
+}
+
+func xxh3Len129To240_128(b, secret []byte, seed uint64) Uint128 {
+	n := len(b)
+	acc := Uint128{Lo: uint64(n) * prime1}
+	for i := 32; i < 160; i += 32 {
+		acc = mix32B(acc, b[i-32:], b[i-16:], secret[i-32:], seed)
+	}
+	acc.Lo = xxh3Avalanche(acc.Lo)
+	acc.Hi = xxh3Avalanche(acc.Hi)
+	for i := 160; i <= n; i += 32 {
+		acc = mix32B(acc, b[i-32:], b[i-16:], secret[xxh3MidsizeStartOffset+i-160:], seed)
+	}
+	acc = mix32B(acc, b[n-16:], b[n-32:], secret[xxh3SecretSizeMin-xxh3MidsizeLastOffset-16:], -seed)
+	return xxh3Final128(acc, uint64(n), seed)
+}
+
+// xxh3Final128 computes the result for inputs of 17 to 240 bytes from the
+// state accumulated by mix32B.
+func xxh3Final128(acc Uint128, n, seed uint64) Uint128 {
+	lo := acc.Lo + acc.Hi
+	hi := acc.Lo*prime1 + acc.Hi*prime4 + (n-seed)*prime2
+	return Uint128{Hi: -xxh3Avalanche(hi), Lo: xxh3Avalanche(lo)}
+}
+
+func xxh3HashLong128(b, secret []byte) Uint128 {
+	acc := xxh3InitAcc
+	xxh3HashLongLoop(&acc, b, secret)
+	return xxh3MergeAccs128(&acc, secret, uint64(len(b)))
+}
+
+func xxh3MergeAccs128(acc *[8]uint64, secret []byte, n uint64) Uint128 {
+	return Uint128{
+		Hi: xxh3MergeAccs(acc, secret[len(secret)-64-11:], ^(n * prime2)),
+		Lo: xxh3MergeAccs(acc, secret[11:], n*prime1),
+	}
+}
+
+func mix32B(acc Uint128, b1, b2, secret []byte, seed uint64) Uint128 {
+	acc.Lo += mix16B(b1, secret, seed)
+	acc.Lo ^= u64(b2[0:8]) + u64(b2[8:16])
+	acc.Hi += mix16B(b2, secret[16:], seed)
+	acc.Hi ^= u64(b1[0:8]) + u64(b1[8:16])
+	return acc
+}
@@ -0,0 +1,127 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSumXXH3_128(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Uint128
+	}{
+		{"", Uint128{0x99aa06d3014798d8, 0x6001c324468d497f}},
+		{"a", Uint128{0xa96faf705af16834, 0xe6c632b61e964e1f}},
+		{"as", Uint128{0x73df18a870cbe8e1, 0x1e0844fa8dccd17d}},
+		{"asd", Uint128{0xc2d2b4ffac53808b, 0xab4e634a5d854219}},
+		{"asdf", Uint128{0x85eafe41d9172802, 0xa8c5b5bb4adf474f}},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", Uint128{0xbb173896057b4569, 0x761e120552c72704}},
+	} {
+		if got := SumXXH3_128([]byte(tt.input)); got != tt.want {
+			t.Errorf("SumXXH3_128(%q): got %016x; want %016x", tt.input, got, tt.want)
+		}
+		if got := SumXXH3_128String(tt.input); got != tt.want {
+			t.Errorf("SumXXH3_128String(%q): got %016x; want %016x", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSumXXH3_128Lengths(t *testing.T) {
+	want := []Uint128{
+		{0x99aa06d3014798d8, 0x6001c324468d497f},
+		{0xa6cd5e9392000f6a, 0xc44bdff4074eecdb},
+		{0xb519c2793d896766, 0x9093381c8763d62e},
+		{0x656e81c56e41fe02, 0xc3489259e968ad9e},
+		{0xab5c3e7474d809db, 0x81a65295de8e7dde},
+		{0xf72d100531dfb713, 0x57c3cf21d8799995},
+		{0xe4b9dd0b66ff3c50, 0xebabbd0695002ff6},
+		{0x82ddc95bc7600767, 0x1c69c3f04aaed08c},
+		{0x70b7db4639552dd1, 0xb36ce5c48d7bf7e7},
+		{0xddf6c1254d70f767, 0x94eaa17b20756f46},
+		{0x263f67af63088041, 0x735fe434ded90c3c},
+		{0xf1df8057a85df9ac, 0x55042d263d832451},
+		{0xa86b514658f976a5, 0x407920045a9a834c},
+		{0xa943d80ce26ed292, 0x8b59b4dfba3c9de4},
+		{0x5ac2b625fb663d1a, 0x560426410aa736ea},
+		{0xa7fa95f7f23b64a7, 0xedae5e0312655703},
+		{0xc12aaf5f8a1782a4, 0x5c95500a9909a96f},
+		{0xdbfa0cd6e568ef54, 0xacb9f0967e182865},
+		{0xbc31691d04ea6efe, 0xfee64835dcb60271},
+		{0xbb5c68515c5c4d5c, 0x787be25a74f9f45e},
+		{0xdd9e5aa9bd51cc9c, 0xc6bd21ecc865f29f},
+		{0x00433635cf8d872e, 0x7f4accb76587485b},
+		{0xdbfff5e13c798ab9, 0x0497bdb3d145ccd6},
+		{0xf2fe3c78143adc2d, 0xcd853c48eb4e074f},
+		{0x89e3a0a2ee355d25, 0xd10beb4e0599e4b3},
+		{0x75f4da43f23cce5a, 0x541b19226f0052e8},
+		{0x57619d72d7b77094, 0x99b37c2c806e33d3},
+		{0x2f433606b2ebce2d, 0xff5a1cefade75bb9},
+		{0xbae798f9321bda6c, 0xd1db6a0afea2cc82},
+		{0x1ffe28f0f336d6be, 0x3d31fcc1037648a4},
+		{0xa3da96fbd6887361, 0x71bee625238addb4},
+		{0xa53cd4fd16206676, 0xd9b414f4e1bbf7ad},
+		{0x7e487a6edeb1f3fe, 0x3293e8238bd8f743},
+		{0x08ef8fc6d37a7191, 0x5c722d9ceb6f9064},
+		{0xe814573b3db786c4, 0xed0146266d138bd8},
+		{0x4e53faeda1b5812b, 0xb25cea78018497ff},
+	}
+	input := xxh3TestInput(100000)
+	for i, n := range xxh3TestLengths {
+		t.Run(fmt.Sprintf("len=%d", n), func(t *testing.T) {
+			if got := SumXXH3_128(input[:n]); got != want[i] {
+				t.Fatalf("got %016x; want %016x", got, want[i])
+			}
+		})
+	}
+}
+
+func TestSumXXH3_128LoMatches64(t *testing.T) {
+	// For inputs of 1-3 bytes and for long inputs, the low half of the 128-bit
+	// digest is the 64-bit digest.
+	input := xxh3TestInput(5000)
+	for _, n := range []int{1, 2, 3, 241, 1024, 5000} {
+		if lo, want := SumXXH3_128(input[:n]).Lo, SumXXH3_64(input[:n]); lo != want {
+			t.Errorf("len=%d: got Lo=0x%016x; want 0x%016x", n, lo, want)
+		}
+	}
+}
+
+var _ Hash128 = (*DigestXXH3)(nil)
+
+func TestUint128Bytes(t *testing.T) {
+	h := Uint128{Hi: 0x0102030405060708, Lo: 0x090a0b0c0d0e0f10}
+	b := h.Bytes()
+	want := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	if !bytes.Equal(b[:], want) {
+		t.Fatalf("Bytes: got %x; want %x", b, want)
+	}
+	buf := make([]byte, 17)
+	h.PutBytes(buf)
+	if !bytes.Equal(buf[:16], want) || buf[16] != 0 {
+		t.Fatalf("PutBytes: got %x", buf)
+	}
+	if got := Uint128FromBytes(want); got != h {
+		t.Fatalf("Uint128FromBytes: got %+v; want %+v", got, h)
+	}
+	if got, want := h.Hex(), "0102030405060708090a0b0c0d0e0f10"; got != want {
+		t.Fatalf("Hex: got %q; want %q", got, want)
+	}
+}
+
+func TestUint128Compare(t *testing.T) {
+	vals := []Uint128{{0, 0}, {0, 1}, {0, ^uint64(0)}, {1, 0}, {^uint64(0), 0}, {^uint64(0), ^uint64(0)}}
+	for i, a := range vals {
+		for j, b := range vals {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := a.Compare(b); got != want {
+				t.Errorf("%+v.Compare(%+v) = %d; want %d", a, b, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// +build !appengine
+// +build gc
+// +build !purego
+// +build !tinygo
+
+package xxhash
+
+// The XXH3 accumulation kernels, as in the reference implementation's
+// performance tiers. SSE2 is always available on amd64; AVX2 is used when
+// the CPU and OS support it. XXHASH_PUREGO=1 selects the generic Go code.
+const (
+	xxh3KernelGeneric = iota
+	xxh3KernelSSE2
+	xxh3KernelAVX2
+)
+
+var xxh3Kernel = xxh3SelectKernel()
+
+func xxh3SelectKernel() int {
+	switch {
+	case usePureGo:
+		return xxh3KernelGeneric
+	case hasAVX2():
+		return xxh3KernelAVX2
+	}
+	return xxh3KernelSSE2
+}
+
+func xxh3Implementation() string {
+	switch xxh3Kernel {
+	case xxh3KernelAVX2:
+		return "avx2"
+	case xxh3KernelSSE2:
+		return "sse2"
+	}
+	return "go"
+}
+
+func xxh3Implementations() []string {
+	if hasAVX2() {
+		return []string{"avx2", "sse2", "go"}
+	}
+	return []string{"sse2", "go"}
+}
+
+func setXXH3Implementation(name string) bool {
+	switch {
+	case name == "avx2" && hasAVX2():
+		xxh3Kernel = xxh3KernelAVX2
+	case name == "sse2":
+		xxh3Kernel = xxh3KernelSSE2
+	case name == "go":
+		xxh3Kernel = xxh3KernelGeneric
+	default:
+		return false
+	}
+	return true
+}
+
+func xxh3Accumulate(acc *[8]uint64, b, secret []byte, stripes int) {
+	if stripes <= 0 || xxh3Kernel == xxh3KernelGeneric {
+		xxh3AccumulateGeneric(acc, b, secret, stripes)
+		return
+	}
+	// The kernels read stripes*64 bytes of b and (stripes-1)*8+64 bytes of
+	// secret.
+	_ = b[stripes*xxh3StripeLen-1]
+	_ = secret[(stripes-1)*8+xxh3StripeLen-1]
+	if xxh3Kernel == xxh3KernelAVX2 {
+		accumulateAVX2(acc, &b[0], &secret[0], stripes)
+	} else {
+		accumulateSSE2(acc, &b[0], &secret[0], stripes)
+	}
+}
+
+func xxh3ScrambleAcc(acc *[8]uint64, secret []byte) {
+	switch xxh3Kernel {
+	case xxh3KernelAVX2:
+		scrambleAVX2(acc, &secret[:xxh3StripeLen][0])
+	case xxh3KernelSSE2:
+		scrambleSSE2(acc, &secret[:xxh3StripeLen][0])
+	default:
+		xxh3ScrambleAccGeneric(acc, secret)
+	}
+}
+
+// hasAVX2 reports whether the CPU supports AVX2 and the OS saves the YMM
+// registers.
+func hasAVX2() bool
+
+//go:noescape
+func accumulateSSE2(acc *[8]uint64, b, secret *byte, stripes int)
+
+//go:noescape
+func accumulateAVX2(acc *[8]uint64, b, secret *byte, stripes int)
+
+//go:noescape
+func scrambleSSE2(acc *[8]uint64, secret *byte)
+
+//go:noescape
+func scrambleAVX2(acc *[8]uint64, secret *byte)
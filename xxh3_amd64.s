@@ -0,0 +1,184 @@
+// +build !appengine
+// +build gc
+// +build !purego
+// +build !tinygo
+
+#include "textflag.h"
+
+// The kernels below compute the same lanes as xxh3AccumulateGeneric and
+// xxh3ScrambleAccGeneric. For each 8-byte lane of a stripe, with v the input
+// and k = v ^ secret:
+//
+//	acc[i] += v[i^1] + uint64(uint32(k[i])) * (k[i] >> 32)
+//
+// PSHUFD $0x31 moves the high half of each 64-bit lane of k into its low half
+// for PMULULQ, which multiplies the low halves; PSHUFD $0x4e swaps the 64-bit
+// lanes of v within each 128-bit lane.
+
+// The secret is only 8-byte aligned, so it is loaded with unaligned moves.
+
+// accumulateSSE2 keeps the accumulators in X0-X3.
+#define accSSE2(off, x) \
+	MOVOU   off(SI), X4        \
+	MOVOU   off(DX), X5        \
+	PXOR    X4, X5             \
+	PSHUFD  $0x31, X5, X6      \
+	PMULULQ X5, X6             \
+	PSHUFD  $0x4e, X4, X4      \
+	PADDQ   X4, x              \
+	PADDQ   X6, x
+
+// func accumulateSSE2(acc *[8]uint64, b, secret *byte, stripes int)
+TEXT ·accumulateSSE2(SB), NOSPLIT, $0-32
+	MOVQ  acc+0(FP), AX
+	MOVQ  b+8(FP), SI
+	MOVQ  secret+16(FP), DX
+	MOVQ  stripes+24(FP), CX
+	MOVOU 0(AX), X0
+	MOVOU 16(AX), X1
+	MOVOU 32(AX), X2
+	MOVOU 48(AX), X3
+
+loop:
+	accSSE2(0, X0)
+	accSSE2(16, X1)
+	accSSE2(32, X2)
+	accSSE2(48, X3)
+	ADDQ $64, SI
+	ADDQ $8, DX
+	DECQ CX
+	JNZ  loop
+
+	MOVOU X0, 0(AX)
+	MOVOU X1, 16(AX)
+	MOVOU X2, 32(AX)
+	MOVOU X3, 48(AX)
+	RET
+
+// accumulateAVX2 keeps the accumulators in Y0 and Y1.
+#define accAVX2(off, y) \
+	VMOVDQU  off(SI), Y2       \
+	VPXOR    off(DX), Y2, Y3   \
+	VPSHUFD  $0x31, Y3, Y4     \
+	VPMULUDQ Y4, Y3, Y4        \
+	VPSHUFD  $0x4e, Y2, Y2     \
+	VPADDQ   Y2, y, y          \
+	VPADDQ   Y4, y, y
+
+// func accumulateAVX2(acc *[8]uint64, b, secret *byte, stripes int)
+TEXT ·accumulateAVX2(SB), NOSPLIT, $0-32
+	MOVQ    acc+0(FP), AX
+	MOVQ    b+8(FP), SI
+	MOVQ    secret+16(FP), DX
+	MOVQ    stripes+24(FP), CX
+	VMOVDQU 0(AX), Y0
+	VMOVDQU 32(AX), Y1
+
+loop:
+	accAVX2(0, Y0)
+	accAVX2(32, Y1)
+	ADDQ $64, SI
+	ADDQ $8, DX
+	DECQ CX
+	JNZ  loop
+
+	VMOVDQU Y0, 0(AX)
+	VMOVDQU Y1, 32(AX)
+	VZEROUPPER
+	RET
+
+// The scramble step computes, for each lane,
+//
+//	acc[i] = (acc[i] ^ acc[i]>>47 ^ secret[i]) * prime32_1
+//
+// with the 64x32-bit multiplication done as two 32x32-bit ones, since prime32_1
+// fits in 32 bits: lo*p + (hi*p)<<32.
+
+#define scrambleSSE2Lane(off) \
+	MOVOU   off(AX), X0  \
+	MOVOU   X0, X1       \
+	PSRLQ   $47, X1      \
+	PXOR    X1, X0       \
+	MOVOU   off(DX), X1  \
+	PXOR    X1, X0       \
+	MOVOU   X0, X1       \
+	PSRLQ   $32, X1      \
+	PMULULQ X7, X0       \
+	PMULULQ X7, X1       \
+	PSLLQ   $32, X1      \
+	PADDQ   X1, X0       \
+	MOVOU   X0, off(AX)
+
+// func scrambleSSE2(acc *[8]uint64, secret *byte)
+TEXT ·scrambleSSE2(SB), NOSPLIT, $0-16
+	MOVQ   acc+0(FP), AX
+	MOVQ   secret+8(FP), DX
+	MOVQ   $2654435761, BX
+	MOVQ   BX, X7
+	PSHUFD $0x44, X7, X7
+	scrambleSSE2Lane(0)
+	scrambleSSE2Lane(16)
+	scrambleSSE2Lane(32)
+	scrambleSSE2Lane(48)
+	RET
+
+#define scrambleAVX2Lane(off) \
+	VMOVDQU  off(AX), Y0      \
+	VPSRLQ   $47, Y0, Y1      \
+	VPXOR    Y1, Y0, Y0       \
+	VPXOR    off(DX), Y0, Y0  \
+	VPSRLQ   $32, Y0, Y1      \
+	VPMULUDQ Y5, Y0, Y0       \
+	VPMULUDQ Y5, Y1, Y1       \
+	VPSLLQ   $32, Y1, Y1      \
+	VPADDQ   Y1, Y0, Y0       \
+	VMOVDQU  Y0, off(AX)
+
+// func scrambleAVX2(acc *[8]uint64, secret *byte)
+TEXT ·scrambleAVX2(SB), NOSPLIT, $0-16
+	MOVQ         acc+0(FP), AX
+	MOVQ         secret+8(FP), DX
+	MOVQ         $2654435761, BX
+	MOVQ         BX, X5
+	VPBROADCASTQ X5, Y5
+	scrambleAVX2Lane(0)
+	scrambleAVX2Lane(32)
+	VZEROUPPER
+	RET
+
+// func hasAVX2() bool
+TEXT ·hasAVX2(SB), NOSPLIT, $0-1
+	// CPUID leaf 7 must exist.
+	XORL  AX, AX
+	CPUID
+	CMPL  AX, $7
+	JLT   no
+
+	// AVX and OSXSAVE (leaf 1, ECX bits 28 and 27).
+	MOVL  $1, AX
+	XORL  CX, CX
+	CPUID
+	ANDL  $0x18000000, CX
+	CMPL  CX, $0x18000000
+	JNE   no
+
+	// The OS saves the XMM and YMM registers (XCR0 bits 1 and 2).
+	XORL   CX, CX
+	XGETBV
+	ANDL   $6, AX
+	CMPL   AX, $6
+	JNE    no
+
+	// AVX2 (leaf 7, EBX bit 5).
+	MOVL $7, AX
+	XORL CX, CX
+	CPUID
+	BTL  $5, BX
+	JCC  no
+
+	MOVB $1, ret+0(FP)
+	RET
+
+no:
+	MOVB $0, ret+0(FP)
+	RET
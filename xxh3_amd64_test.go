@@ -0,0 +1,77 @@
+// +build !appengine
+// +build gc
+// +build !purego
+// +build !tinygo
+
+package xxhash
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestXXH3Kernels(t *testing.T) {
+	defer func(old int) { xxh3Kernel = old }(xxh3Kernel)
+	kernels := []int{xxh3KernelSSE2}
+	if hasAVX2() {
+		kernels = append(kernels, xxh3KernelAVX2)
+	} else {
+		t.Log("AVX2 not supported; testing SSE2 only")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	b := make([]byte, 64*40)
+	rng.Read(b)
+	secret := make([]byte, 200)
+	rng.Read(secret)
+	for _, kernel := range kernels {
+		// The kernels match the generic code on the accumulators directly,
+		// including unaligned input and secret offsets.
+		for _, stripes := range []int{1, 2, 16, 17} {
+			for _, off := range []int{0, 1, 7} {
+				var acc, want [8]uint64
+				for i := range acc {
+					acc[i] = rng.Uint64()
+				}
+				want = acc
+				xxh3Kernel = xxh3KernelGeneric
+				xxh3Accumulate(&want, b[off:], secret[off:], stripes)
+				xxh3ScrambleAcc(&want, secret[off:])
+				xxh3Kernel = kernel
+				xxh3Accumulate(&acc, b[off:], secret[off:], stripes)
+				xxh3ScrambleAcc(&acc, secret[off:])
+				if acc != want {
+					t.Fatalf("kernel %d, %d stripes, offset %d: got %x; want %x", kernel, stripes, off, acc, want)
+				}
+			}
+		}
+
+		// And so do the digests.
+		for _, n := range []int{241, 1024, 1025, 2000, len(b)} {
+			xxh3Kernel = xxh3KernelGeneric
+			want64, want128 := SumXXH3_64(b[:n]), SumXXH3_128(b[:n])
+			wantSecret := SumXXH3_64WithSecret(b[:n], secret)
+			xxh3Kernel = kernel
+			if got := SumXXH3_64(b[:n]); got != want64 {
+				t.Errorf("kernel %d, n=%d: SumXXH3_64 = 0x%x; want 0x%x", kernel, n, got, want64)
+			}
+			if got := SumXXH3_128(b[:n]); got != want128 {
+				t.Errorf("kernel %d, n=%d: SumXXH3_128 = %v; want %v", kernel, n, got, want128)
+			}
+			if got := SumXXH3_64WithSecret(b[:n], secret); got != wantSecret {
+				t.Errorf("kernel %d, n=%d: with secret = 0x%x; want 0x%x", kernel, n, got, wantSecret)
+			}
+			d := NewXXH3()
+			for i := 0; i < n; i += 100 {
+				end := i + 100
+				if end > n {
+					end = n
+				}
+				d.Write(b[i:end])
+			}
+			if got := d.Sum64(); got != want64 {
+				t.Errorf("kernel %d, n=%d: DigestXXH3 = 0x%x; want 0x%x", kernel, n, got, want64)
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package xxhash
+
+// xxh3BufSize is the size of DigestXXH3's internal buffer. It must be a
+// multiple of xxh3StripeLen.
+const xxh3BufSize = 256
+
+// DigestXXH3 implements hash.Hash64 using XXH3. It computes both the 64-bit
+// digest (Sum64) and the 128-bit digest (Sum128) of the data written to it.
+//
+// The zero value is not ready to use; call NewXXH3 or Reset first. A
+// DigestXXH3 is a single fixed-size allocation of a few hundred bytes and can
+// be reused with Reset, for example through a sync.Pool.
+type DigestXXH3 struct {
+	acc     [8]uint64
+	buf     [xxh3BufSize]byte
+	n       int    // how much of buf is used
+	stripes int    // how many stripes of the current block have been consumed
+	total   uint64 // total bytes written
+	secret  []byte
+	seed    uint64 // nonzero if secret was derived from a seed
+
+	seedSecret *[len(xxh3Secret)]byte // storage for a seed-derived secret
+}
+
+// NewXXH3 creates a new DigestXXH3 that computes the XXH3 algorithm.
+func NewXXH3() *DigestXXH3 {
+	var d DigestXXH3
+	d.Reset()
+	return &d
+}
+
+// Reset clears the DigestXXH3's state so that it can be reused.
+func (d *DigestXXH3) Reset() {
+	d.acc = xxh3InitAcc
+	d.n = 0
+	d.stripes = 0
+	d.total = 0
+	d.secret = xxh3DefaultSecret
+	d.seed = 0
+}
+
+// Size always returns 8 bytes.
+func (d *DigestXXH3) Size() int { return 8 }
+
+// BlockSize always returns 64 bytes.
+func (d *DigestXXH3) BlockSize() int { return xxh3StripeLen }
+
+// Write adds more data to d. It always returns len(b), nil.
+func (d *DigestXXH3) Write(b []byte) (n int, err error) {
+	n = len(b)
+	d.total += uint64(n)
+
+	if n <= len(d.buf)-d.n {
+		// This new data fits in the buffer.
+		d.n += copy(d.buf[d.n:], b)
+		return
+	}
+
+	if d.n > 0 {
+		// Fill up and consume the buffer. There is always more input left
+		// over, so the buffer is never left full.
+		b = b[copy(d.buf[d.n:], b):]
+		xxh3ConsumeStripes(&d.acc, &d.stripes, d.buf[:], d.secret, len(d.buf)/xxh3StripeLen)
+		d.n = 0
+	}
+
+	if len(b) > len(d.buf) {
+		// Consume all but the last (possibly partial) stripe directly from b,
+		// and save the last consumed stripe: if fewer than xxh3StripeLen bytes
+		// remain when the digest is computed, the final stripe overlaps it.
+		stripes := (len(b) - 1) / xxh3StripeLen
+		xxh3ConsumeStripes(&d.acc, &d.stripes, b, d.secret, stripes)
+		end := stripes * xxh3StripeLen
+		copy(d.buf[len(d.buf)-xxh3StripeLen:], b[end-xxh3StripeLen:end])
+		b = b[end:]
+	}
+
+	// Store the remaining input.
+	d.n = copy(d.buf[:], b)
+
+	return
+}
+
+// Sum appends the current 64-bit hash to b and returns the resulting slice.
+func (d *DigestXXH3) Sum(b []byte) []byte {
+	s := d.Sum64()
+	return append(
+		b,
+		byte(s>>56),
+		byte(s>>48),
+		byte(s>>40),
+		byte(s>>32),
+		byte(s>>24),
+		byte(s>>16),
+		byte(s>>8),
+		byte(s),
+	)
+}
+
+// Sum64 returns the current 64-bit hash.
+func (d *DigestXXH3) Sum64() uint64 {
+	if d.total <= xxh3MidsizeMax {
+		if d.seed != 0 {
+			return xxh3Hash64(d.buf[:d.total], xxh3DefaultSecret, d.seed)
+		}
+		return xxh3Hash64(d.buf[:d.total], d.secret, 0)
+	}
+	acc := d.digestLong()
+	return xxh3MergeAccs(&acc, d.secret[11:], d.total*prime1)
+}
+
+// Sum128 returns the current 128-bit hash.
+func (d *DigestXXH3) Sum128() Uint128 {
+	if d.total <= xxh3MidsizeMax {
+		if d.seed != 0 {
+			return xxh3Hash128(d.buf[:d.total], xxh3DefaultSecret, d.seed)
+		}
+		return xxh3Hash128(d.buf[:d.total], d.secret, 0)
+	}
+	acc := d.digestLong()
+	return xxh3MergeAccs128(&acc, d.secret, d.total)
+}
+
+// digestLong finishes the accumulation for an input longer than
+// xxh3MidsizeMax on a copy of the accumulators, leaving d unchanged.
+func (d *DigestXXH3) digestLong() [8]uint64 {
+	acc := d.acc
+	var last [xxh3StripeLen]byte
+	if d.n >= xxh3StripeLen {
+		stripes := d.stripes
+		xxh3ConsumeStripes(&acc, &stripes, d.buf[:], d.secret, (d.n-1)/xxh3StripeLen)
+		copy(last[:], d.buf[d.n-xxh3StripeLen:d.n])
+	} else {
+		// The last stripe starts in the previously consumed input, whose
+		// final stripe is kept at the end of the buffer.
+		k := copy(last[:], d.buf[len(d.buf)-(xxh3StripeLen-d.n):])
+		copy(last[k:], d.buf[:d.n])
+	}
+	xxh3Accumulate512(&acc, last[:], d.secret[len(d.secret)-xxh3StripeLen-xxh3LastAccStart:])
+	return acc
+}
+
+// xxh3ConsumeStripes accumulates stripes stripes of b, scrambling the
+// accumulators at each block boundary. *stripesSoFar tracks the position in
+// the current block across calls.
+func xxh3ConsumeStripes(acc *[8]uint64, stripesSoFar *int, b, secret []byte, stripes int) {
+	stripesPerBlock := (len(secret) - xxh3StripeLen) / 8
+	scramble := secret[len(secret)-xxh3StripeLen:]
+	if left := stripesPerBlock - *stripesSoFar; stripes >= left {
+		// Finish the current block, then process any full blocks.
+		xxh3Accumulate(acc, b, secret[*stripesSoFar*8:], left)
+		xxh3ScrambleAcc(acc, scramble)
+		b = b[left*xxh3StripeLen:]
+		stripes -= left
+		for stripes >= stripesPerBlock {
+			xxh3Accumulate(acc, b, secret, stripesPerBlock)
+			xxh3ScrambleAcc(acc, scramble)
+			b = b[stripesPerBlock*xxh3StripeLen:]
+			stripes -= stripesPerBlock
+		}
+		*stripesSoFar = 0
+	}
+	if stripes > 0 {
+		xxh3Accumulate(acc, b, secret[*stripesSoFar*8:], stripes)
+		*stripesSoFar += stripes
+	}
+}
@@ -0,0 +1,85 @@
+package xxhash
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"testing"
+)
+
+var _ hash.Hash64 = (*DigestXXH3)(nil)
+
+func TestDigestXXH3(t *testing.T) {
+	input := xxh3TestInput(5000)
+	for _, n := range xxh3TestLengths {
+		if n > len(input) {
+			continue
+		}
+		in := input[:n]
+		want64 := SumXXH3_64(in)
+		want128 := SumXXH3_128(in)
+		for _, chunkSize := range []int{1, 7, 63, 64, 65, 200, 256, 257, 1000, 5000} {
+			t.Run(fmt.Sprintf("len=%d,chunkSize=%d", n, chunkSize), func(t *testing.T) {
+				d := NewXXH3()
+				ds := NewXXH3() // uses WriteString
+				for i := 0; i < len(in); i += chunkSize {
+					chunk := in[i:]
+					if len(chunk) > chunkSize {
+						chunk = chunk[:chunkSize]
+					}
+					if n, err := d.Write(chunk); err != nil || n != len(chunk) {
+						t.Fatalf("Write: got (%d, %v); want (%d, nil)", n, err, len(chunk))
+					}
+					if n, err := ds.WriteString(string(chunk)); err != nil || n != len(chunk) {
+						t.Fatalf("WriteString: got (%d, %v); want (%d, nil)", n, err, len(chunk))
+					}
+				}
+				if got := d.Sum64(); got != want64 {
+					t.Fatalf("Sum64: got 0x%016x; want 0x%016x", got, want64)
+				}
+				if got := ds.Sum64(); got != want64 {
+					t.Fatalf("Sum64 (WriteString): got 0x%016x; want 0x%016x", got, want64)
+				}
+				if got := d.Sum128(); got != want128 {
+					t.Fatalf("Sum128: got %016x; want %016x", got, want128)
+				}
+			})
+		}
+	}
+}
+
+func TestDigestXXH3Continue(t *testing.T) {
+	// Computing the digest must not disturb the state.
+	input := xxh3TestInput(3000)
+	d := NewXXH3()
+	for i := 0; i < len(input); i += 100 {
+		d.Write(input[i : i+100])
+		if got, want := d.Sum64(), SumXXH3_64(input[:i+100]); got != want {
+			t.Fatalf("after %d bytes: got 0x%016x; want 0x%016x", i+100, got, want)
+		}
+	}
+	var want [8]byte
+	h := SumXXH3_64(input)
+	for i := range want {
+		want[i] = byte(h >> (56 - 8*uint(i)))
+	}
+	if got := d.Sum([]byte("x")); !bytes.Equal(got, append([]byte("x"), want[:]...)) {
+		t.Fatalf("Sum: got %x; want 78%x", got, want)
+	}
+
+	d.Reset()
+	d.Write(input[:10])
+	if got, want := d.Sum64(), SumXXH3_64(input[:10]); got != want {
+		t.Fatalf("after Reset: got 0x%016x; want 0x%016x", got, want)
+	}
+}
+
+func TestDigestXXH3Allocs(t *testing.T) {
+	input := xxh3TestInput(1000)
+	d := NewXXH3()
+	testAllocs(t, func() {
+		d.Reset()
+		d.Write(input)
+		sink = d.Sum64() ^ d.Sum128().Hi
+	})
+}
@@ -0,0 +1,15 @@
+// +build !amd64 appengine !gc purego tinygo
+
+package xxhash
+
+func xxh3Accumulate(acc *[8]uint64, b, secret []byte, stripes int) {
+	xxh3AccumulateGeneric(acc, b, secret, stripes)
+}
+
+func xxh3ScrambleAcc(acc *[8]uint64, secret []byte) { xxh3ScrambleAccGeneric(acc, secret) }
+
+func xxh3Implementation() string { return "go" }
+
+func xxh3Implementations() []string { return []string{"go"} }
+
+func setXXH3Implementation(name string) bool { return name == "go" }
@@ -0,0 +1,88 @@
+package xxhash
+
+import "encoding/binary"
+
+// XXH3SecretSizeMin is the minimum length of a custom XXH3 secret.
+const XXH3SecretSizeMin = xxh3SecretSizeMin
+
+// SumXXH3_64WithSecret computes the 64-bit XXH3 digest of b using a custom
+// secret, as with XXH3_64bits_withSecret in the reference implementation.
+//
+// The secret must be at least XXH3SecretSizeMin bytes long and should look
+// like random bytes; GenerateXXH3Secret can produce a suitable secret from an
+// arbitrary seed. It panics if the secret is too short.
+func SumXXH3_64WithSecret(b, secret []byte) uint64 {
+	checkXXH3Secret(secret)
+	return xxh3Hash64(b, secret, 0)
+}
+
+// SumXXH3_128WithSecret computes the 128-bit XXH3 digest of b using a custom
+// secret. See SumXXH3_64WithSecret.
+func SumXXH3_128WithSecret(b, secret []byte) Uint128 {
+	checkXXH3Secret(secret)
+	return xxh3Hash128(b, secret, 0)
+}
+
+// NewXXH3WithSecret creates a new DigestXXH3 that uses a custom secret. See
+// SumXXH3_64WithSecret for the requirements on secret. The DigestXXH3 refers
+// to secret rather than copying it, so secret must not be modified while the
+// digest is in use.
+func NewXXH3WithSecret(secret []byte) *DigestXXH3 {
+	var d DigestXXH3
+	d.ResetWithSecret(secret)
+	return &d
+}
+
+// ResetWithSecret clears the DigestXXH3's state and switches it to a custom
+// secret, as with NewXXH3WithSecret.
+func (d *DigestXXH3) ResetWithSecret(secret []byte) {
+	checkXXH3Secret(secret)
+	d.Reset()
+	d.secret = secret
+}
+
+// GenerateXXH3Secret derives a size-byte secret, suitable for the
+// XXH3 WithSecret functions, from seed, which may be of any length. It
+// produces the same output as XXH3_generateSecret in the reference
+// implementation. It panics if size is less than XXH3SecretSizeMin.
+func GenerateXXH3Secret(seed []byte, size int) []byte {
+	if size < XXH3SecretSizeMin {
+		panic("xxhash: XXH3 secret size is less than XXH3SecretSizeMin")
+	}
+	if len(seed) == 0 {
+		seed = xxh3Secret[:]
+	}
+
+	// Fill the secret with copies of the seed, then scramble each 16-byte
+	// segment using hashes of the seed.
+	secret := make([]byte, size)
+	for i := 0; i < size; i += len(seed) {
+		copy(secret[i:], seed)
+	}
+	h := SumXXH3_128(seed)
+	var scrambler [16]byte
+	putUint128BE(scrambler[:], h)
+	for i := 0; i < size/16; i++ {
+		combine16(secret[16*i:], xxh3Hash128(scrambler[:], xxh3DefaultSecret, uint64(i)))
+	}
+	combine16(secret[size-16:], h)
+	return secret
+}
+
+func checkXXH3Secret(secret []byte) {
+	if len(secret) < XXH3SecretSizeMin {
+		panic("xxhash: XXH3 secret is shorter than XXH3SecretSizeMin")
+	}
+}
+
+// combine16 xors h into the 16 bytes at the start of b.
+func combine16(b []byte, h Uint128) {
+	binary.LittleEndian.PutUint64(b[0:8], u64(b[0:8])^h.Lo)
+	binary.LittleEndian.PutUint64(b[8:16], u64(b[8:16])^h.Hi)
+}
+
+// putUint128BE stores h in b in the canonical (big-endian) XXH128 encoding.
+func putUint128BE(b []byte, h Uint128) {
+	binary.BigEndian.PutUint64(b[0:8], h.Hi)
+	binary.BigEndian.PutUint64(b[8:16], h.Lo)
+}
@@ -0,0 +1,120 @@
+package xxhash
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+type xxh3SecretSum struct {
+	n      int
+	sum64  uint64
+	sum128 Uint128
+}
+
+func TestXXH3WithSecret(t *testing.T) {
+	input := xxh3TestInput(5000)
+	for _, tt := range []struct {
+		seed   string
+		size   int
+		secret string
+		sums   []xxh3SecretSum
+	}{
+		{
+			seed: "",
+			size: 136,
+			secret: "e7ed21ba56ad5a80259901947a13df152a5235a1887f04b75f0a7eb4938e0223" +
+				"c546d60ac87d995c477660c6b6de10c3eee0fa621d52adf951a0e1ba74708ce6" +
+				"75a8de0e30109ca15706a51923827f519326d759abfab475572a823dc6ec0d2f" +
+				"62f515f44f905bc00a6ecf1f0a2f86c5aebf68922393fb411b76b15b314de27e" +
+				"e2bf5830da849d1e",
+			sums: []xxh3SecretSum{
+				{0, 0x4d426a36efa01666, Uint128{0xd2705831ab9410fa, 0xa66d6568e252ab9b}},
+				{3, 0x964a094aa08a59b1, Uint128{0x7748132e266fb58e, 0x964a094aa08a59b1}},
+				{8, 0xb260b18756708e75, Uint128{0x87cf48c136e273e7, 0xfc129806d2ba89c4}},
+				{16, 0xd679018f7e275cc3, Uint128{0x72628b115a2095eb, 0xa6f1ef8ae7a9810b}},
+				{100, 0x740093d39e980560, Uint128{0x76fbb3f94352cc11, 0x810ddf989b3e0fa0}},
+				{200, 0xfc7b5f33fe2e0d03, Uint128{0x223ebd85efc7cf42, 0xb52c73e87b3b3659}},
+				{240, 0xc1a30073c24a43bd, Uint128{0xe86742fdcdf043d8, 0x23c76972640d9c60}},
+				{241, 0xf0cfb581944bf73c, Uint128{0xf7c29f6046d22012, 0xf0cfb581944bf73c}},
+				{1000, 0x81586c77a4aa6ca4, Uint128{0xc8a91180e0c302bd, 0x81586c77a4aa6ca4}},
+				{1024, 0x670991f6d05491a6, Uint128{0xea710c819c01080e, 0x670991f6d05491a6}},
+				{2000, 0x11315dd29fb280cb, Uint128{0xe7cafe77843b7221, 0x11315dd29fb280cb}},
+				{5000, 0x2727f36a97cc0753, Uint128{0x1f334314ff7bff71, 0x2727f36a97cc0753}},
+			},
+		},
+		{
+			seed: "correct horse battery staple",
+			size: 200,
+			secret: "679b6ddcc8edd148d375de15ec2fdba8ade9c24a2e866e56ac45b8c8c9164f99" +
+				"b9d80f5b255fb50e0af4f1a134bd802754075fb2e2c7a09b43ce3dad086f0c51" +
+				"f7fa3af415ce2aa85836e591e010dd6158cedea4dfdb6f2a1ecef704177e7ab0" +
+				"001c321d428040e2ca2694ef7d2867978ce55d78f9d14bc2d7027bff8dd79ce6" +
+				"70b5afe9afa5a10fe0ebd6cc09bf2984b36b95d56115a83a36412c46ac5342f3" +
+				"56ed4a36b55bfcd7f2a969d989e20d7cdc6febb9a0b07072eb835b648cdc596f" +
+				"127e75f53ba59d61",
+			sums: []xxh3SecretSum{
+				{0, 0xbab28daa92ddf629, Uint128{0x5a79ec2edab03289, 0xc6a145c612561570}},
+				{3, 0x62555c63b9291408, Uint128{0x81c0980a7c005e8b, 0x62555c63b9291408}},
+				{8, 0x253d400bf6fc68ac, Uint128{0x3315d5e2e8653153, 0x057ca422090a7f64}},
+				{16, 0xeb95d07878f020be, Uint128{0x623076c476f86be9, 0x74719b757cc27ac9}},
+				{100, 0x7341e92cd46d980d, Uint128{0x1bea381df5a886b5, 0x6ea831307db6cf9b}},
+				{200, 0xa2e1363f3324b2aa, Uint128{0x8c7f06bde4d28c13, 0xdd6633214a419e97}},
+				{240, 0x3a2bf6d63627dafc, Uint128{0x8c2bd011a97f617e, 0xf41f55e565a2b699}},
+				{241, 0x72c491419d6a9718, Uint128{0xe8a969c677c7de10, 0x72c491419d6a9718}},
+				{1000, 0x07a28647628e4526, Uint128{0x2669ec33401e61fc, 0x07a28647628e4526}},
+				{1024, 0xdc8ad79bb9f1ef20, Uint128{0xa7ac53222e6ae44f, 0xdc8ad79bb9f1ef20}},
+				{2000, 0xaec3f29309311e1d, Uint128{0x4fcdf464ed49d1cb, 0xaec3f29309311e1d}},
+				{5000, 0x8105f735372e1309, Uint128{0x7696cdb1302deb2b, 0x8105f735372e1309}},
+			},
+		},
+	} {
+		secret := GenerateXXH3Secret([]byte(tt.seed), tt.size)
+		if got := hex.EncodeToString(secret); got != tt.secret {
+			t.Fatalf("GenerateXXH3Secret(%q, %d): got\n%s\nwant\n%s", tt.seed, tt.size, got, tt.secret)
+		}
+		for _, s := range tt.sums {
+			t.Run(fmt.Sprintf("size=%d,len=%d", tt.size, s.n), func(t *testing.T) {
+				in := input[:s.n]
+				if got := SumXXH3_64WithSecret(in, secret); got != s.sum64 {
+					t.Errorf("SumXXH3_64WithSecret: got 0x%016x; want 0x%016x", got, s.sum64)
+				}
+				if got := SumXXH3_128WithSecret(in, secret); got != s.sum128 {
+					t.Errorf("SumXXH3_128WithSecret: got %016x; want %016x", got, s.sum128)
+				}
+				d := NewXXH3WithSecret(secret)
+				for i := 0; i < len(in); i += 97 {
+					end := i + 97
+					if end > len(in) {
+						end = len(in)
+					}
+					d.Write(in[i:end])
+				}
+				if got := d.Sum64(); got != s.sum64 {
+					t.Errorf("DigestXXH3.Sum64: got 0x%016x; want 0x%016x", got, s.sum64)
+				}
+				if got := d.Sum128(); got != s.sum128 {
+					t.Errorf("DigestXXH3.Sum128: got %016x; want %016x", got, s.sum128)
+				}
+			})
+		}
+	}
+}
+
+func TestXXH3SecretTooShort(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"SumXXH3_64WithSecret":  func() { SumXXH3_64WithSecret(nil, make([]byte, XXH3SecretSizeMin-1)) },
+		"SumXXH3_128WithSecret": func() { SumXXH3_128WithSecret(nil, make([]byte, XXH3SecretSizeMin-1)) },
+		"NewXXH3WithSecret":     func() { NewXXH3WithSecret(make([]byte, XXH3SecretSizeMin-1)) },
+		"GenerateXXH3Secret":    func() { GenerateXXH3Secret(nil, XXH3SecretSizeMin-1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,62 @@
+package xxhash
+
+import "encoding/binary"
+
+// SumXXH3_64WithSeed computes the 64-bit XXH3 digest of b with the given
+// seed, as with XXH3_64bits_withSeed in the reference implementation. A seed
+// of zero gives the same result as SumXXH3_64.
+func SumXXH3_64WithSeed(b []byte, seed uint64) uint64 {
+	if seed == 0 || len(b) <= xxh3MidsizeMax {
+		return xxh3Hash64(b, xxh3DefaultSecret, seed)
+	}
+	var secret [len(xxh3Secret)]byte
+	initXXH3SeededSecret(&secret, seed)
+	return xxh3HashLong64(b, secret[:])
+}
+
+// SumXXH3_128WithSeed computes the 128-bit XXH3 digest of b with the given
+// seed, as with XXH3_128bits_withSeed in the reference implementation. A
+// seed of zero gives the same result as SumXXH3_128.
+func SumXXH3_128WithSeed(b []byte, seed uint64) Uint128 {
+	if seed == 0 || len(b) <= xxh3MidsizeMax {
+		return xxh3Hash128(b, xxh3DefaultSecret, seed)
+	}
+	var secret [len(xxh3Secret)]byte
+	initXXH3SeededSecret(&secret, seed)
+	return xxh3HashLong128(b, secret[:])
+}
+
+// NewXXH3WithSeed creates a new DigestXXH3 that computes XXH3 with the given
+// seed.
+func NewXXH3WithSeed(seed uint64) *DigestXXH3 {
+	var d DigestXXH3
+	d.ResetWithSeed(seed)
+	return &d
+}
+
+// ResetWithSeed clears the DigestXXH3's state and switches it to the given
+// seed, as with NewXXH3WithSeed.
+func (d *DigestXXH3) ResetWithSeed(seed uint64) {
+	d.Reset()
+	if seed == 0 {
+		return
+	}
+	if d.seedSecret == nil {
+		d.seedSecret = new([len(xxh3Secret)]byte)
+	}
+	initXXH3SeededSecret(d.seedSecret, seed)
+	d.seed = seed
+	d.secret = d.seedSecret[:]
+}
+
+// initXXH3SeededSecret derives the secret that XXH3 uses with a seed for
+// inputs longer than xxh3MidsizeMax, as XXH3_initCustomSecret does: the seed
+// is added to the first and subtracted from the second half of each 16-byte
+// segment of the default secret. Shorter inputs use the default secret and
+// mix in the seed directly.
+func initXXH3SeededSecret(secret *[len(xxh3Secret)]byte, seed uint64) {
+	for i := 0; i < len(secret); i += 16 {
+		binary.LittleEndian.PutUint64(secret[i:], u64(xxh3Secret[i:])+seed)
+		binary.LittleEndian.PutUint64(secret[i+8:], u64(xxh3Secret[i+8:])-seed)
+	}
+}
@@ -0,0 +1,129 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+// xxh3SanityInput returns the first n bytes of the sanity-check buffer used
+// by the reference xxhsum to produce its XXH3 test vectors.
+func xxh3SanityInput(n int) []byte {
+	b := make([]byte, n)
+	gen := uint64(2654435761)
+	for i := range b {
+		b[i] = byte(gen >> 56)
+		gen *= 11400714785074694797
+	}
+	return b
+}
+
+// The vectors in these tests are from the XXH3 sanity checks of the reference
+// xxhsum (xxHash v0.8). They cover every size class; for inputs longer than
+// 240 bytes the secret is derived from the seed.
+
+func TestSumXXH3_64WithSeed(t *testing.T) {
+	for _, tt := range []struct {
+		n    int
+		seed uint64
+		want uint64
+	}{
+		{0, 0, 0x2d06800538d394c2},
+		{0, 11400714785074694797, 0xa8a6b918b2f0364a},
+		{1, 0, 0xc44bdff4074eecdb},
+		{1, 11400714785074694797, 0x032be332dd766ef8},
+		{6, 0, 0x27b56a84cd2d7325},
+		{6, 11400714785074694797, 0x84589c116ab59ab9},
+		{12, 0, 0xa713daf0dfbb77e7},
+		{12, 11400714785074694797, 0xe7303e1b2336de0e},
+		{24, 0, 0xa3fe70bf9d3510eb},
+		{24, 11400714785074694797, 0x850e80fc35bdd690},
+		{48, 0, 0x397da259ecba1f11},
+		{48, 11400714785074694797, 0xadc2cbaa44acc616},
+		{80, 0, 0xbcdefbbb2c47c90a},
+		{80, 11400714785074694797, 0xc6dd0cb699532e73},
+		{195, 0, 0xcd94217ee362ec3a},
+		{195, 11400714785074694797, 0xba68003d370cb3d9},
+		{403, 0, 0xcdeb804d65c6dea4},
+		{403, 11400714785074694797, 0x6259f6ecfd6443fd},
+		{512, 0, 0x617e49599013cb6b},
+		{512, 11400714785074694797, 0x3ce457de14c27708},
+		{2048, 0, 0xdd59e2c3a5f038e0},
+		{2048, 11400714785074694797, 0x66f81670669ababc},
+		{2240, 0, 0x6e73a90539cf2948},
+		{2240, 11400714785074694797, 0x757ba8487d1b5247},
+		{2367, 0, 0xcb37aeb9e5d361ed},
+		{2367, 11400714785074694797, 0xd2db3415b942b42a},
+	} {
+		input := xxh3SanityInput(tt.n)
+		if got := SumXXH3_64WithSeed(input, tt.seed); got != tt.want {
+			t.Errorf("len=%d, seed=%#x: got 0x%016x; want 0x%016x", tt.n, tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestSumXXH3_128WithSeed(t *testing.T) {
+	for _, tt := range []struct {
+		n    int
+		seed uint64
+		want Uint128
+	}{
+		{0, 0, Uint128{0x99aa06d3014798d8, 0x6001c324468d497f}},
+		{0, 2654435761, Uint128{0x92220ae55e14ab50, 0x5444f7869c671ab0}},
+		{1, 0, Uint128{0xa6cd5e9392000f6a, 0xc44bdff4074eecdb}},
+		{1, 2654435761, Uint128{0x89b99554ba22467c, 0xb53d5557e7f76f8d}},
+		{6, 0, Uint128{0x082afe0b8162d12a, 0x3e7039bdda43cfc6}},
+		{6, 2654435761, Uint128{0x5a865b5389abd2b1, 0x269d8f70be98856e}},
+		{12, 0, Uint128{0x6e3efd8fc7802b18, 0x061a192713f69ad9}},
+		{12, 2654435761, Uint128{0xd7e09d518a3405d3, 0x9be9f9a67f3c7dfb}},
+		{24, 0, Uint128{0x0ce966e4678d3761, 0x1e7044d28b1b901d}},
+		{24, 2654435761, Uint128{0x3162026714a6a243, 0xd7304c54ebad40a9}},
+		{48, 0, Uint128{0xa002ac4e5478227e, 0xf942219aed80f67b}},
+		{48, 2654435761, Uint128{0x163adde36c072295, 0x7ba3c3e453a1934e}},
+		{81, 0, Uint128{0x4952f58181ab0042, 0x5e8bafb9f95fb803}},
+		{81, 2654435761, Uint128{0x2724ec7adc750fb6, 0x703fbb3d7a5f755c}},
+		{222, 0, Uint128{0x337e09641b948717, 0xf1aebd597cec6b3a}},
+		{222, 2654435761, Uint128{0x91820016621e97f1, 0xae995bb8af917a8d}},
+		{403, 0, Uint128{0x1b6de21e332dd73d, 0xcdeb804d65c6dea4}},
+		{403, 11400714785074694797, Uint128{0xbed311971e0be8f2, 0x6259f6ecfd6443fd}},
+		{512, 0, Uint128{0x18d2d110dcc9bca1, 0x617e49599013cb6b}},
+		{512, 11400714785074694797, Uint128{0x925d06b8ec5b8040, 0x3ce457de14c27708}},
+		{2048, 0, Uint128{0xf736557fd47073a5, 0xdd59e2c3a5f038e0}},
+		{2048, 2654435761, Uint128{0x7fb03f7e7186c3ea, 0x230d43f30206260b}},
+		{2240, 0, Uint128{0xccb134fbfa7ce49d, 0x6e73a90539cf2948}},
+		{2240, 2654435761, Uint128{0x50a1fe17b338995f, 0xed385111126fba6f}},
+		{2367, 0, Uint128{0xe89c0f6ff369b427, 0xcb37aeb9e5d361ed}},
+		{2367, 2654435761, Uint128{0xd23aae4b76c31ecb, 0x6f5360ae69c2f406}},
+	} {
+		input := xxh3SanityInput(tt.n)
+		if got := SumXXH3_128WithSeed(input, tt.seed); got != tt.want {
+			t.Errorf("len=%d, seed=%#x: got %s; want %s", tt.n, tt.seed, got.Hex(), tt.want.Hex())
+		}
+	}
+}
+
+func TestDigestXXH3WithSeed(t *testing.T) {
+	input := xxh3TestInput(100000)
+	for _, seed := range []uint64{0, 1, 11400714785074694797} {
+		d := NewXXH3WithSeed(seed)
+		for _, n := range xxh3TestLengths {
+			t.Run(fmt.Sprintf("seed=%#x,len=%d", seed, n), func(t *testing.T) {
+				d.ResetWithSeed(seed)
+				d.Write(input[:n/3])
+				d.Write(input[n/3 : n])
+				if got, want := d.Sum64(), SumXXH3_64WithSeed(input[:n], seed); got != want {
+					t.Errorf("Sum64: got 0x%016x; want 0x%016x", got, want)
+				}
+				if got, want := d.Sum128(), SumXXH3_128WithSeed(input[:n], seed); got != want {
+					t.Errorf("Sum128: got %s; want %s", got.Hex(), want.Hex())
+				}
+			})
+		}
+	}
+	// Reset goes back to the unseeded hash.
+	d := NewXXH3WithSeed(1)
+	d.Reset()
+	d.Write(input[:1000])
+	if got, want := d.Sum64(), SumXXH3_64(input[:1000]); got != want {
+		t.Errorf("after Reset: got 0x%016x; want 0x%016x", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package xxhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+// xxh3TestInput returns n bytes of deterministic, non-repeating-looking input.
+func xxh3TestInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i*7 + i/256)
+	}
+	return b
+}
+
+func TestSumXXH3_64(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  uint64
+	}{
+		{"", 0x2d06800538d394c2},
+		{"a", 0xe6c632b61e964e1f},
+		{"as", 0x1e0844fa8dccd17d},
+		{"asd", 0xab4e634a5d854219},
+		{"asdf", 0x43a74511c2a27ecc},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", 0x3f1a447475ae2069},
+	} {
+		if got := SumXXH3_64([]byte(tt.input)); got != tt.want {
+			t.Errorf("SumXXH3_64(%q): got 0x%016x; want 0x%016x", tt.input, got, tt.want)
+		}
+		if got := SumXXH3_64String(tt.input); got != tt.want {
+			t.Errorf("SumXXH3_64String(%q): got 0x%016x; want 0x%016x", tt.input, got, tt.want)
+		}
+	}
+}
+
+// The lengths below cover every size class of XXH3 and both sides of each
+// boundary, including the 1024-byte block boundary of the default secret.
+var xxh3TestLengths = []int{
+	0, 1, 2, 3, 4, 5, 8, 9, 15, 16, 17, 31, 32, 33, 63, 64, 65, 96, 97, 127, 128,
+	129, 200, 239, 240, 241, 255, 256, 1000, 1023, 1024, 1025, 2048, 4096, 5000,
+	100000,
+}
+
+func TestSumXXH3_64Lengths(t *testing.T) {
+	want := []uint64{
+		0x2d06800538d394c2, 0xc44bdff4074eecdb, 0x9093381c8763d62e, 0xc3489259e968ad9e,
+		0xd3d60c1519014e89, 0x559935c0f3f7327f, 0xb88dee77f6bf6980, 0x03688dcad730d826,
+		0x2e52bc81c6a30fa3, 0x9da23836adf2be1e, 0xf34c3c9cf5a112d1, 0x1c67766271b95c64,
+		0x99cb9ad0f1a11fbe, 0xc077b45492d29cde, 0x2b15bbbf8594aa32, 0x6efb76ff16f37561,
+		0x2640848e9137156b, 0x764d2d5db92942df, 0x077acb7e5f4fd940, 0x92113fc28f4b970c,
+		0x65f3c2c00fa93185, 0x28065c6ec25f5b25, 0x7c64f3b17285e96a, 0x6ce0b13191e635ea,
+		0x4917a75c0ef8eed7, 0x541b19226f0052e8, 0x99b37c2c806e33d3, 0xff5a1cefade75bb9,
+		0xd1db6a0afea2cc82, 0x3d31fcc1037648a4, 0x71bee625238addb4, 0xd9b414f4e1bbf7ad,
+		0x3293e8238bd8f743, 0x5c722d9ceb6f9064, 0xed0146266d138bd8, 0xb25cea78018497ff,
+	}
+	input := xxh3TestInput(100000)
+	for i, n := range xxh3TestLengths {
+		t.Run(fmt.Sprintf("len=%d", n), func(t *testing.T) {
+			if got := SumXXH3_64(input[:n]); got != want[i] {
+				t.Fatalf("got 0x%016x; want 0x%016x", got, want[i])
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 // Package xxhash implements the 64-bit variant of xxHash (XXH64) as described
-// at http://cyan4973.github.io/xxHash/.
+// at http://cyan4973.github.io/xxHash/. It also implements the 32-bit variant
+// (XXH32) for interoperability with formats that use it, and the 64-bit
+// and 128-bit variants of the newer XXH3 algorithm.
 package xxhash
 
 import (
 	"encoding/binary"
 	"errors"
 	"math/bits"
+	"runtime"
 )
 
 const (
@@ -31,6 +34,12 @@ var (
 )
 
 // Digest implements hash.Hash64.
+//
+// The zero value is ready to use and is equivalent to New(), so a Digest can
+// be declared as a local variable or embedded by value in another struct.
+//
+// The value returned by Sum64 cannot be used to continue hashing where a
+// Digest left off; to persist a partial computation, use MarshalBinary.
 type Digest struct {
 	v1    uint64
 	v2    uint64
@@ -48,16 +57,58 @@ func New() *Digest {
 	return &d
 }
 
+// NewWithSeed creates a new Digest that computes the 64-bit xxHash algorithm
+// using the given seed.
+func NewWithSeed(seed uint64) *Digest {
+	var d Digest
+	d.ResetWithSeed(seed)
+	return &d
+}
+
 // Reset clears the Digest's state so that it can be reused.
+// It uses a seed value of zero.
 func (d *Digest) Reset() {
-	d.v1 = prime1v + prime2
-	d.v2 = prime2
-	d.v3 = 0
-	d.v4 = -prime1v
+	d.ResetWithSeed(0)
+}
+
+// ResetWithSeed clears the Digest's state so that it can be reused.
+// It uses the given seed to initialize the state.
+func (d *Digest) ResetWithSeed(seed uint64) {
+	d.v1 = seed + prime1 + prime2
+	d.v2 = seed + prime2
+	d.v3 = seed
+	d.v4 = seed - prime1
 	d.total = 0
 	d.n = 0
 }
 
+// init sets up the accumulators of a zero Digest for a seed of zero. It
+// must only be called before the first block is processed; until then the
+// accumulators are untouched and, after any Reset, v1-v2 is prime1.
+func (d *Digest) init() {
+	if d.v1 == d.v2 {
+		d.v1 = prime1v + prime2
+		d.v2 = prime2
+		d.v4 = -prime1v
+	}
+}
+
+// accs returns d's accumulators, as initialized if d is the zero value.
+func (d *Digest) accs() (v1, v2, v3, v4 uint64) {
+	if d.total < 32 && d.v1 == d.v2 {
+		return prime1v + prime2, prime2, 0, -prime1v
+	}
+	return d.v1, d.v2, d.v3, d.v4
+}
+
+// Clone returns a copy of d. The copy and d can then be written to
+// independently, which is useful for hashing several inputs that share a
+// common prefix.
+func (d *Digest) Clone() *Digest {
+	c := *d
+	return &c
+}
+
 // Size always returns 8 bytes.
 func (d *Digest) Size() int { return 8 }
 
@@ -76,6 +127,11 @@ func (d *Digest) Write(b []byte) (n int, err error) {
 		return
 	}
 
+	if d.total-uint64(n) < 32 {
+		// No block has been processed yet, so d may still be the zero value.
+		d.init()
+	}
+
 	if d.n > 0 {
 		// Finish off the partial block.
 		copy(d.mem[d.n:], b)
@@ -100,6 +156,29 @@ func (d *Digest) Write(b []byte) (n int, err error) {
 	return
 }
 
+// WriteVec adds the concatenation of bufs to d, as if each were passed to
+// Write in turn. It always returns the total length of bufs, nil.
+func (d *Digest) WriteVec(bufs ...[]byte) (n int, err error) {
+	for _, b := range bufs {
+		d.Write(b)
+		n += len(b)
+	}
+	return n, nil
+}
+
+// Len returns the number of bytes written to d since it was created or last
+// reset.
+func (d *Digest) Len() uint64 {
+	return d.total
+}
+
+// PastBlockThreshold reports whether at least one full 32-byte block has been
+// written to d. This determines which of the two XXH64 finalization paths
+// Sum64 takes; it does not change d's state.
+func (d *Digest) PastBlockThreshold() bool {
+	return d.total >= 32
+}
+
 // Sum appends the current hash to b and returns the resulting slice.
 func (d *Digest) Sum(b []byte) []byte {
 	s := d.Sum64()
@@ -116,6 +195,36 @@ func (d *Digest) Sum(b []byte) []byte {
 	)
 }
 
+// SumLE returns the current hash in little-endian byte order, as stored by
+// formats such as LZ4 and zstd frames, rather than the canonical big-endian
+// order of Sum.
+func (d *Digest) SumLE() [8]byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], d.Sum64())
+	return b
+}
+
+// AppendSumLE appends the current hash to b in little-endian byte order and
+// returns the resulting slice.
+func (d *Digest) AppendSumLE(b []byte) []byte {
+	return appendUint64(b, d.Sum64())
+}
+
+// String returns the current hash as 16 lowercase hexadecimal digits, as
+// printed by xxhsum. Like Sum64, it does not change d's state.
+func (d *Digest) String() string {
+	var b [16]byte
+	return string(appendHex64(b[:0], d.Sum64()))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. It returns the
+// same hex digits as String, so a Digest appears as its current hash in text
+// encodings such as JSON and structured logs. (There is no UnmarshalText: the
+// hash does not determine the state. Use MarshalBinary to save the state.)
+func (d *Digest) MarshalText() ([]byte, error) {
+	return appendHex64(make([]byte, 0, 16), d.Sum64()), nil
+}
+
 // Sum64 returns the current hash.
 func (d *Digest) Sum64() uint64 {
 	var h uint64
@@ -159,22 +268,59 @@ func (d *Digest) Sum64() uint64 {
 	return h
 }
 
+// Sum64AndReset returns the current hash and then resets d (to a seed of zero),
+// as with Sum64 followed by Reset.
+func (d *Digest) Sum64AndReset() uint64 {
+	h := d.Sum64()
+	d.Reset()
+	return h
+}
+
+// Sum64WithSeed computes the 64-bit xxHash digest of b using the given seed.
+// It matches XXH64 in the reference implementation with the same seed; a seed
+// of 0 gives the same result as Sum64.
+func Sum64WithSeed(b []byte, seed uint64) uint64 {
+	var d Digest
+	d.ResetWithSeed(seed)
+	d.total = uint64(len(b))
+	if len(b) >= 32 {
+		b = b[writeBlocks(&d, b):]
+	}
+	d.n = copy(d.mem[:], b)
+	return d.Sum64()
+}
+
 const (
 	magic         = "xxh\x06"
 	marshaledSize = len(magic) + 8*5 + 32
 )
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The format is stable: the 4-byte identifier "xxh\x06" (whose last byte
+// acts as a version number), the four accumulators and the total length as
+// little-endian uint64s, and the 32-byte block buffer, of which only the first
+// total%32 bytes are meaningful.
 func (d *Digest) MarshalBinary() ([]byte, error) {
-	b := make([]byte, 0, marshaledSize)
+	return d.AppendBinary(make([]byte, 0, marshaledSize))
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface. It appends
+// the same encoding as MarshalBinary to b and returns the extended slice; it
+// does not allocate if b has enough spare capacity.
+func (d *Digest) AppendBinary(b []byte) ([]byte, error) {
+	v1, v2, v3, v4 := d.accs()
 	b = append(b, magic...)
-	b = appendUint64(b, d.v1)
-	b = appendUint64(b, d.v2)
-	b = appendUint64(b, d.v3)
-	b = appendUint64(b, d.v4)
+	b = appendUint64(b, v1)
+	b = appendUint64(b, v2)
+	b = appendUint64(b, v3)
+	b = appendUint64(b, v4)
 	b = appendUint64(b, d.total)
+	// Only the used part of mem is meaningful; pad the rest with zeros rather
+	// than leaking stale data.
+	var zeros [len(d.mem)]byte
 	b = append(b, d.mem[:d.n]...)
-	b = b[:len(b)+len(d.mem)-d.n]
+	b = append(b, zeros[d.n:]...)
 	return b, nil
 }
 
@@ -208,7 +354,17 @@ func consumeUint64(b []byte) ([]byte, uint64) {
 	return b[8:], x
 }
 
-func u64(b []byte) uint64 { return binary.LittleEndian.Uint64(b) }
+func u64(b []byte) uint64 {
+	if runtime.GOARCH == "386" {
+		// The 386 compiler doesn't combine the byte loads of Uint64 into
+		// 32-bit loads, but it does for Uint32. The branch is resolved at
+		// compile time.
+		_ = b[7] // bounds check hint to compiler; see golang.org/issue/14808
+		return uint64(u32(b)) | uint64(u32(b[4:]))<<32
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
 func u32(b []byte) uint32 { return binary.LittleEndian.Uint32(b) }
 
 func round(acc, input uint64) uint64 {
@@ -225,6 +381,16 @@ func mergeRound(acc, val uint64) uint64 {
 	return acc
 }
 
+// avalanche is the final mixing step of XXH64.
+func avalanche(h uint64) uint64 {
+	h ^= h >> 33
+	h *= prime2
+	h ^= h >> 29
+	h *= prime3
+	h ^= h >> 32
+	return h
+}
+
 func rol1(x uint64) uint64  { return bits.RotateLeft64(x, 1) }
 func rol7(x uint64) uint64  { return bits.RotateLeft64(x, 7) }
 func rol11(x uint64) uint64 { return bits.RotateLeft64(x, 11) }
@@ -0,0 +1,200 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	prime32_1 uint32 = 2654435761
+	prime32_2 uint32 = 2246822519
+	prime32_3 uint32 = 3266489917
+	prime32_4 uint32 = 668265263
+	prime32_5 uint32 = 374761393
+)
+
+// prime32_1v is needed to avoid overflowing constant arithmetic; see the
+// note on prime1v.
+var prime32_1v = prime32_1
+
+// Digest32 implements hash.Hash32 using the 32-bit variant of xxHash (XXH32).
+type Digest32 struct {
+	v1    uint32
+	v2    uint32
+	v3    uint32
+	v4    uint32
+	total uint64
+	mem   [16]byte
+	n     int // how much of mem is used
+}
+
+// New32 creates a new Digest32 that computes the 32-bit xxHash algorithm.
+func New32() *Digest32 {
+	var d Digest32
+	d.Reset()
+	return &d
+}
+
+// Reset clears the Digest32's state so that it can be reused.
+func (d *Digest32) Reset() {
+	d.resetWithSeed(0)
+}
+
+func (d *Digest32) resetWithSeed(seed uint32) {
+	d.v1 = seed + prime32_1 + prime32_2
+	d.v2 = seed + prime32_2
+	d.v3 = seed
+	d.v4 = seed - prime32_1
+	d.total = 0
+	d.n = 0
+}
+
+// Size always returns 4 bytes.
+func (d *Digest32) Size() int { return 4 }
+
+// BlockSize always returns 16 bytes.
+func (d *Digest32) BlockSize() int { return 16 }
+
+// Write adds more data to d. It always returns len(b), nil.
+func (d *Digest32) Write(b []byte) (n int, err error) {
+	n = len(b)
+	d.total += uint64(n)
+
+	if d.n+n < 16 {
+		// This new data doesn't even fill the current block.
+		copy(d.mem[d.n:], b)
+		d.n += n
+		return
+	}
+
+	if d.n > 0 {
+		// Finish off the partial block.
+		copy(d.mem[d.n:], b)
+		d.v1 = round32(d.v1, u32(d.mem[0:4]))
+		d.v2 = round32(d.v2, u32(d.mem[4:8]))
+		d.v3 = round32(d.v3, u32(d.mem[8:12]))
+		d.v4 = round32(d.v4, u32(d.mem[12:16]))
+		b = b[16-d.n:]
+		d.n = 0
+	}
+
+	if len(b) >= 16 {
+		// One or more full blocks left.
+		v1, v2, v3, v4 := d.v1, d.v2, d.v3, d.v4
+		for len(b) >= 16 {
+			v1 = round32(v1, u32(b[0:4:len(b)]))
+			v2 = round32(v2, u32(b[4:8:len(b)]))
+			v3 = round32(v3, u32(b[8:12:len(b)]))
+			v4 = round32(v4, u32(b[12:16:len(b)]))
+			b = b[16:len(b):len(b)]
+		}
+		d.v1, d.v2, d.v3, d.v4 = v1, v2, v3, v4
+	}
+
+	// Store any remaining partial block.
+	copy(d.mem[:], b)
+	d.n = len(b)
+
+	return
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (d *Digest32) Sum(b []byte) []byte {
+	s := d.Sum32()
+	return append(
+		b,
+		byte(s>>24),
+		byte(s>>16),
+		byte(s>>8),
+		byte(s),
+	)
+}
+
+// SumLE returns the current hash in little-endian byte order, as stored by
+// formats such as LZ4 frames, rather than the canonical big-endian order of
+// Sum.
+func (d *Digest32) SumLE() [4]byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], d.Sum32())
+	return b
+}
+
+// AppendSumLE appends the current hash to b in little-endian byte order and
+// returns the resulting slice.
+func (d *Digest32) AppendSumLE(b []byte) []byte {
+	s := d.Sum32()
+	return append(b, byte(s), byte(s>>8), byte(s>>16), byte(s>>24))
+}
+
+// Sum32 returns the current hash.
+func (d *Digest32) Sum32() uint32 {
+	var h uint32
+
+	if d.total >= 16 {
+		h = bits.RotateLeft32(d.v1, 1) + bits.RotateLeft32(d.v2, 7) +
+			bits.RotateLeft32(d.v3, 12) + bits.RotateLeft32(d.v4, 18)
+	} else {
+		h = d.v3 + prime32_5
+	}
+
+	// XXH32 mixes in the length modulo 2^32.
+	h += uint32(d.total)
+
+	return finalize32(h, d.mem[:d.n])
+}
+
+// Sum32 computes the 32-bit xxHash digest of b.
+func Sum32(b []byte) uint32 {
+	n := len(b)
+	var h uint32
+
+	if n >= 16 {
+		v1 := prime32_1v + prime32_2
+		v2 := prime32_2
+		v3 := uint32(0)
+		v4 := -prime32_1v
+		for len(b) >= 16 {
+			v1 = round32(v1, u32(b[0:4:len(b)]))
+			v2 = round32(v2, u32(b[4:8:len(b)]))
+			v3 = round32(v3, u32(b[8:12:len(b)]))
+			v4 = round32(v4, u32(b[12:16:len(b)]))
+			b = b[16:len(b):len(b)]
+		}
+		h = bits.RotateLeft32(v1, 1) + bits.RotateLeft32(v2, 7) +
+			bits.RotateLeft32(v3, 12) + bits.RotateLeft32(v4, 18)
+	} else {
+		h = prime32_5
+	}
+
+	h += uint32(n)
+
+	return finalize32(h, b)
+}
+
+// finalize32 mixes the remaining (fewer than 16) bytes of input into h and
+// applies the final avalanche.
+func finalize32(h uint32, b []byte) uint32 {
+	for ; len(b) >= 4; b = b[4:] {
+		h += u32(b) * prime32_3
+		h = bits.RotateLeft32(h, 17) * prime32_4
+	}
+	for _, c := range b {
+		h += uint32(c) * prime32_5
+		h = bits.RotateLeft32(h, 11) * prime32_1
+	}
+
+	h ^= h >> 15
+	h *= prime32_2
+	h ^= h >> 13
+	h *= prime32_3
+	h ^= h >> 16
+
+	return h
+}
+
+func round32(acc, input uint32) uint32 {
+	acc += input * prime32_2
+	acc = bits.RotateLeft32(acc, 13)
+	acc *= prime32_1
+	return acc
+}
@@ -0,0 +1,127 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+	"testing"
+)
+
+var _ hash.Hash32 = (*Digest32)(nil)
+
+func TestAll32(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		input string
+		want  uint32
+	}{
+		{"empty", "", 0x02cc5d05},
+		{"a", "a", 0x550d7456},
+		{"as", "as", 0x9d5a0464},
+		{"asd", "asd", 0x3d83552b},
+		{"asdf", "asdf", 0x5e702c32},
+		{
+			"len=63",
+			"Call me Ishmael. Some years ago--never mind how long precisely-",
+			0x6f320359,
+		},
+	} {
+		for chunkSize := 1; chunkSize <= len(tt.input); chunkSize++ {
+			name := fmt.Sprintf("%s,chunkSize=%d", tt.name, chunkSize)
+			t.Run(name, func(t *testing.T) {
+				testDigest32(t, tt.input, chunkSize, tt.want)
+			})
+		}
+		t.Run(tt.name, func(t *testing.T) { testSum32(t, tt.input, tt.want) })
+	}
+}
+
+func testDigest32(t *testing.T, input string, chunkSize int, want uint32) {
+	d := New32()
+	ds := New32() // uses WriteString
+	for i := 0; i < len(input); i += chunkSize {
+		chunk := input[i:]
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		n, err := d.Write([]byte(chunk))
+		if err != nil || n != len(chunk) {
+			t.Fatalf("Digest32.Write: got (%d, %v); want (%d, nil)", n, err, len(chunk))
+		}
+		n, err = ds.WriteString(chunk)
+		if err != nil || n != len(chunk) {
+			t.Fatalf("Digest32.WriteString: got (%d, %v); want (%d, nil)", n, err, len(chunk))
+		}
+	}
+	if got := d.Sum32(); got != want {
+		t.Fatalf("Digest32.Sum32: got 0x%x; want 0x%x", got, want)
+	}
+	if got := ds.Sum32(); got != want {
+		t.Fatalf("Digest32.Sum32 (WriteString): got 0x%x; want 0x%x", got, want)
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], want)
+	if got := d.Sum(nil); !bytes.Equal(got, b[:]) {
+		t.Fatalf("Sum: got %v; want %v", got, b[:])
+	}
+}
+
+func testSum32(t *testing.T, input string, want uint32) {
+	if got := Sum32([]byte(input)); got != want {
+		t.Fatalf("Sum32: got 0x%x; want 0x%x", got, want)
+	}
+	if got := Sum32String(input); got != want {
+		t.Fatalf("Sum32String: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestReset32(t *testing.T) {
+	parts := []string{"The quic", "k br", "o", "wn fox jumps", " ov", "er the lazy ", "dog."}
+	d := New32()
+	for _, part := range parts {
+		d.Write([]byte(part))
+	}
+	h0 := d.Sum32()
+
+	d.Reset()
+	d.Write([]byte(strings.Join(parts, "")))
+	h1 := d.Sum32()
+
+	if h0 != h1 {
+		t.Errorf("0x%x != 0x%x", h0, h1)
+	}
+}
+
+func TestSumLE32(t *testing.T) {
+	d := New32()
+	d.WriteString("hello")
+	be := d.Sum(nil)
+	le := d.SumLE()
+	for i := range le {
+		if le[i] != be[3-i] {
+			t.Fatalf("SumLE = %x; want reverse of Sum %x", le, be)
+		}
+	}
+	if got := d.AppendSumLE([]byte("x")); string(got) != "x"+string(le[:]) {
+		t.Fatalf("AppendSumLE = %x; want %x", got, append([]byte("x"), le[:]...))
+	}
+}
+
+func TestAllocs32(t *testing.T) {
+	const shortStr = "abcdefghijklmnop"
+	t.Run("Sum32", func(t *testing.T) {
+		testAllocs(t, func() {
+			sink = uint64(Sum32([]byte(shortStr)))
+		})
+	})
+	t.Run("Digest32", func(t *testing.T) {
+		b := []byte("asdf")
+		testAllocs(t, func() {
+			d := New32()
+			d.Write(b)
+			sink = uint64(d.Sum32())
+		})
+	})
+}
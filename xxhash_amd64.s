@@ -1,6 +1,7 @@
 // +build !appengine
 // +build gc
 // +build !purego
+// +build !tinygo
 
 #include "textflag.h"
 
@@ -40,6 +41,10 @@
 
 // func Sum64(b []byte) uint64
 TEXT ·Sum64(SB), NOSPLIT, $0-32
+	// Use the pure-Go implementation if usePureGo is set.
+	CMPB ·usePureGo(SB), $0
+	JNE  generic
+
 	// Load fixed primes.
 	MOVQ ·prime1v(SB), R13
 	MOVQ ·prime2v(SB), R14
@@ -169,11 +174,18 @@ finalize:
 	MOVQ AX, ret+24(FP)
 	RET
 
+generic:
+	JMP ·sum64Generic(SB)
+
 // writeBlocks uses the same registers as above except that it uses AX to store
 // the d pointer.
 
 // func writeBlocks(d *Digest, b []byte) int
 TEXT ·writeBlocks(SB), NOSPLIT, $0-40
+	// Use the pure-Go implementation if usePureGo is set.
+	CMPB ·usePureGo(SB), $0
+	JNE  generic
+
 	// Load fixed primes needed for round.
 	MOVQ ·prime1v(SB), R13
 	MOVQ ·prime2v(SB), R14
@@ -213,3 +225,6 @@ blockLoop:
 	MOVQ CX, ret+32(FP)
 
 	RET
+
+generic:
+	JMP ·writeBlocksGeneric(SB)
@@ -0,0 +1,44 @@
+// +build amd64 arm64 riscv64
+// +build !appengine
+// +build gc
+// +build !purego
+// +build !tinygo
+
+package xxhash
+
+import "os"
+
+// usePureGo makes the assembly Sum64 and writeBlocks jump to the pure-Go
+// implementation. It is set at init from the XXHASH_PUREGO environment
+// variable, for debugging and for comparing the two without rebuilding with
+// the purego tag.
+var usePureGo = os.Getenv("XXHASH_PUREGO") == "1"
+
+func xxh64Implementation() string {
+	if usePureGo {
+		return "go"
+	}
+	return "asm"
+}
+
+func xxh64Implementations() []string { return []string{"asm", "go"} }
+
+func setXXH64Implementation(name string) bool {
+	switch name {
+	case "asm":
+		usePureGo = false
+	case "go":
+		usePureGo = true
+	default:
+		return false
+	}
+	return true
+}
+
+// Sum64 computes the 64-bit xxHash digest of b.
+//
+//go:noescape
+func Sum64(b []byte) uint64
+
+//go:noescape
+func writeBlocks(d *Digest, b []byte) int
@@ -0,0 +1,35 @@
+// +build amd64 arm64 riscv64
+// +build !appengine
+// +build gc
+// +build !purego
+// +build !tinygo
+
+package xxhash
+
+import "testing"
+
+func TestUsePureGo(t *testing.T) {
+	defer func(old bool) { usePureGo = old }(usePureGo)
+
+	b := make([]byte, 1000)
+	for i := range b {
+		b[i] = byte(i*7 + i/256)
+	}
+	for _, n := range []int{0, 1, 31, 32, 100, 1000} {
+		usePureGo = false
+		want := Sum64(b[:n])
+		d := New()
+		d.Write(b[:n])
+		wantDigest := d.Sum64()
+
+		usePureGo = true
+		if got := Sum64(b[:n]); got != want {
+			t.Errorf("n=%d: Sum64 with usePureGo = 0x%x; want 0x%x", n, got, want)
+		}
+		d.Reset()
+		d.Write(b[:n])
+		if got := d.Sum64(); got != wantDigest {
+			t.Errorf("n=%d: Digest.Sum64 with usePureGo = 0x%x; want 0x%x", n, got, wantDigest)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package xxhash
+
+import "testing"
+
+// TestGenericMatchesSum64 checks the pure-Go implementation against Sum64 and
+// writeBlocks, which use assembly on some platforms.
+func TestGenericMatchesSum64(t *testing.T) {
+	buf := make([]byte, 8+1100)
+	for i := range buf {
+		buf[i] = byte(i*7 + i/256)
+	}
+	for offset := 0; offset < 8; offset++ {
+		for n := 0; n <= 1100; n++ {
+			b := buf[offset : offset+n]
+			if got, want := Sum64(b), sum64Generic(b); got != want {
+				t.Fatalf("offset=%d, n=%d: Sum64 = 0x%x; sum64Generic = 0x%x", offset, n, got, want)
+			}
+			if n < 32 {
+				continue
+			}
+			var d1, d2 Digest
+			d1.ResetWithSeed(uint64(n))
+			d2.ResetWithSeed(uint64(n))
+			nw1 := writeBlocks(&d1, b)
+			nw2 := writeBlocksGeneric(&d2, b)
+			if nw1 != nw2 || d1 != d2 {
+				t.Fatalf("offset=%d, n=%d: writeBlocks and writeBlocksGeneric disagree", offset, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// +build !appengine
+// +build gc
+// +build !purego
+// +build !tinygo
+
+#include "textflag.h"
+
+// Register allocation.
+#define digest	X9
+#define h	X13 // Return value.
+#define p	X10 // Input pointer.
+#define n	X11 // Input length.
+#define nblocks	X12 // n / 32.
+#define prime1	X14
+#define prime2	X15
+#define prime3	X16
+#define prime4	X17
+#define prime5	X18
+#define v1	X19
+#define v2	X20
+#define v3	X21
+#define v4	X22
+#define x1	X23
+#define x2	X24
+#define x3	X25
+#define x4	X26
+#define q	X5  // Aligned input pointer, for misaligned input.
+#define shr	X30 // 8 * (p & 7).
+#define shl	X6  // 64 - shr.
+#define carry	X7  // The high bytes of the last aligned word, shifted down.
+#define t	X28
+#define u	X29
+
+// rol rotates r left by k bits. The base ISA has no rotate instruction, so
+// this does not depend on the Zbb extension.
+#define rol(k, r) \
+	SLL $k, r, t      \
+	SRL $(64-k), r, r \
+	OR  t, r, r
+
+// round computes acc = rol31(acc + x*prime2) * prime1.
+#define round(acc, x) \
+	MUL prime2, x, x     \
+	ADD x, acc, acc      \
+	rol(31, acc)         \
+	MUL prime1, acc, acc
+
+// round0 performs the operation x = round(0, x).
+#define round0(x) \
+	MUL prime2, x, x \
+	rol(31, x)       \
+	MUL prime1, x, x
+
+#define mergeRound(acc, x) \
+	round0(x)            \
+	XOR x, acc, acc      \
+	MUL prime1, acc, acc \
+	ADD prime4, acc, acc
+
+// loadMisaligned assembles the next input word from the aligned word at
+// off(q) and the bytes carried over from the previous one.
+#define loadMisaligned(off, x) \
+	MOV off(q), u       \
+	SLL shl, u, x       \
+	OR  carry, x, x     \
+	SRL shr, u, carry
+
+// blockLoop processes as many 32-byte blocks as possible,
+// updating v1, v2, v3, and v4. It assumes that n >= 32.
+//
+// Misaligned loads may trap and be emulated by the execution environment,
+// which is very slow, so when p is not 8-byte aligned the words are
+// assembled from aligned loads instead. The last aligned load of each block
+// may read up to 7 bytes past the block, but never past the aligned word
+// holding its last byte, so it cannot fault.
+#define blockLoop() \
+	SRL   $5, n, nblocks          \
+	AND   $7, p, shr              \
+	BNEZ  shr, misaligned         \
+loop:                             \
+	MOV   0(p), x1                \
+	MOV   8(p), x2                \
+	MOV   16(p), x3               \
+	MOV   24(p), x4               \
+	round(v1, x1)                 \
+	round(v2, x2)                 \
+	round(v3, x3)                 \
+	round(v4, x4)                 \
+	ADD   $32, p, p               \
+	SUB   $1, nblocks, nblocks    \
+	BNEZ  nblocks, loop           \
+	JMP   blocksDone              \
+misaligned:                       \
+	SUB   shr, p, q               \
+	SLL   $3, shr, shr            \
+	MOV   $64, shl                \
+	SUB   shr, shl, shl           \
+	MOV   0(q), carry             \
+	SRL   shr, carry, carry       \
+misalignedLoop:                   \
+	loadMisaligned(8, x1)         \
+	loadMisaligned(16, x2)        \
+	loadMisaligned(24, x3)        \
+	loadMisaligned(32, x4)        \
+	round(v1, x1)                 \
+	round(v2, x2)                 \
+	round(v3, x3)                 \
+	round(v4, x4)                 \
+	ADD   $32, q, q               \
+	SUB   $1, nblocks, nblocks    \
+	BNEZ  nblocks, misalignedLoop \
+	AND   $-32, n, t              \
+	ADD   t, p, p                 \
+blocksDone:
+
+// loadByte ORs the byte at off(p) into x at bit position 8*off.
+#define loadByte(off, x) \
+	MOVBU off(p), t     \
+	SLL   $(8*off), t, t \
+	OR    t, x, x
+
+// loadPrimes loads prime1 through prime5.
+#define loadPrimes() \
+	MOV ·prime1v(SB), prime1 \
+	MOV ·prime2v(SB), prime2 \
+	MOV ·prime3v(SB), prime3 \
+	MOV ·prime4v(SB), prime4 \
+	MOV ·prime5v(SB), prime5
+
+// func Sum64(b []byte) uint64
+TEXT ·Sum64(SB), NOSPLIT|NOFRAME, $0-32
+	// Use the pure-Go implementation if usePureGo is set.
+	MOVBU ·usePureGo(SB), t
+	BNEZ  t, generic
+
+	MOV b_base+0(FP), p
+	MOV b_len+8(FP), n
+
+	loadPrimes()
+
+	MOV  prime5, h
+	MOV  $32, t
+	BLTU n, t, afterLoop
+
+	ADD prime1, prime2, v1
+	MOV prime2, v2
+	MOV ZERO, v3
+	SUB prime1, ZERO, v4
+
+	blockLoop()
+
+	MOV v1, x1
+	rol(1, x1)
+	MOV v2, x2
+	rol(7, x2)
+	ADD x1, x2, x2
+	MOV v3, x3
+	rol(12, x3)
+	MOV v4, x4
+	rol(18, x4)
+	ADD x3, x4, x4
+	ADD x2, x4, h
+
+	mergeRound(h, v1)
+	mergeRound(h, v2)
+	mergeRound(h, v3)
+	mergeRound(h, v4)
+
+afterLoop:
+	ADD n, h, h
+
+	// The low five bits of n give the length of the remaining tail, which is
+	// read a byte at a time to avoid misaligned loads.
+	AND $31, n, n
+	MOV $8, u
+
+loop8:
+	BLTU n, u, try4
+	MOVBU 0(p), x1
+	loadByte(1, x1)
+	loadByte(2, x1)
+	loadByte(3, x1)
+	loadByte(4, x1)
+	loadByte(5, x1)
+	loadByte(6, x1)
+	loadByte(7, x1)
+
+	round0(x1)
+	XOR x1, h, h
+	rol(27, h)
+	MUL prime1, h, h
+	ADD prime4, h, h
+
+	ADD $8, p, p
+	SUB $8, n, n
+	JMP loop8
+
+try4:
+	AND   $4, n, u
+	BEQZ  u, loop1
+	MOVBU 0(p), x2
+	loadByte(1, x2)
+	loadByte(2, x2)
+	loadByte(3, x2)
+
+	MUL prime1, x2, x2
+	XOR x2, h, h
+	rol(23, h)
+	MUL prime2, h, h
+	ADD prime3, h, h
+
+	ADD $4, p, p
+	SUB $4, n, n
+
+loop1:
+	BEQZ  n, finalize
+	MOVBU 0(p), x3
+
+	MUL prime5, x3, x3
+	XOR x3, h, h
+	rol(11, h)
+	MUL prime1, h, h
+
+	ADD $1, p, p
+	SUB $1, n, n
+	JMP loop1
+
+finalize:
+	SRL $33, h, t
+	XOR t, h, h
+	MUL prime2, h, h
+	SRL $29, h, t
+	XOR t, h, h
+	MUL prime3, h, h
+	SRL $32, h, t
+	XOR t, h, h
+
+	MOV h, ret+24(FP)
+	RET
+
+generic:
+	JMP ·sum64Generic(SB)
+
+// func writeBlocks(d *Digest, b []byte) int
+TEXT ·writeBlocks(SB), NOSPLIT|NOFRAME, $0-40
+	// Use the pure-Go implementation if usePureGo is set.
+	MOVBU ·usePureGo(SB), t
+	BNEZ  t, generic
+
+	MOV ·prime1v(SB), prime1
+	MOV ·prime2v(SB), prime2
+
+	// Load state. Assume v[1-4] are stored contiguously.
+	MOV d+0(FP), digest
+	MOV 0(digest), v1
+	MOV 8(digest), v2
+	MOV 16(digest), v3
+	MOV 24(digest), v4
+
+	MOV b_base+8(FP), p
+	MOV b_len+16(FP), n
+
+	blockLoop()
+
+	// Store updated state.
+	MOV v1, 0(digest)
+	MOV v2, 8(digest)
+	MOV v3, 16(digest)
+	MOV v4, 24(digest)
+
+	AND $-32, n, n
+	MOV n, ret+32(FP)
+	RET
+
+generic:
+	JMP ·writeBlocksGeneric(SB)
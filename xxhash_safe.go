@@ -1,4 +1,4 @@
-// +build appengine
+// +build appengine tinygo nounsafe
 
 // This file contains the safe implementations of otherwise unsafe-using code.
 
@@ -9,7 +9,38 @@ func Sum64String(s string) uint64 {
 	return Sum64([]byte(s))
 }
 
+// Sum64StringWithSeed computes the 64-bit xxHash digest of s using the given
+// seed.
+func Sum64StringWithSeed(s string, seed uint64) uint64 {
+	return Sum64WithSeed([]byte(s), seed)
+}
+
 // WriteString adds more data to d. It always returns len(s), nil.
 func (d *Digest) WriteString(s string) (n int, err error) {
 	return d.Write([]byte(s))
 }
+
+// Sum32String computes the 32-bit xxHash digest of s.
+func Sum32String(s string) uint32 {
+	return Sum32([]byte(s))
+}
+
+// WriteString adds more data to d. It always returns len(s), nil.
+func (d *Digest32) WriteString(s string) (n int, err error) {
+	return d.Write([]byte(s))
+}
+
+// SumXXH3_64String computes the 64-bit XXH3 digest of s.
+func SumXXH3_64String(s string) uint64 {
+	return SumXXH3_64([]byte(s))
+}
+
+// SumXXH3_128String computes the 128-bit XXH3 digest of s.
+func SumXXH3_128String(s string) Uint128 {
+	return SumXXH3_128([]byte(s))
+}
+
+// WriteString adds more data to d. It always returns len(s), nil.
+func (d *DigestXXH3) WriteString(s string) (n int, err error) {
+	return d.Write([]byte(s))
+}
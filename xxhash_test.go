@@ -3,7 +3,10 @@ package xxhash
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"io"
 	"strings"
 	"testing"
 )
@@ -92,6 +95,122 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestPastBlockThreshold(t *testing.T) {
+	d := New()
+	for i := 0; i < 40; i++ {
+		if got, want := d.PastBlockThreshold(), i >= 32; got != want {
+			t.Fatalf("after %d bytes: got %t; want %t", i, got, want)
+		}
+		d.Write([]byte{'a'})
+	}
+	d.Reset()
+	if d.PastBlockThreshold() {
+		t.Fatal("after Reset: got true")
+	}
+}
+
+func TestWriteVec(t *testing.T) {
+	data := make([]byte, 200)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	// Split data into header, key, and value pieces at every pair of
+	// positions near the 32-byte block boundaries.
+	for i := 0; i < 70; i++ {
+		for j := i; j < 70; j++ {
+			d := New()
+			d.Write(data[:5]) // start mid-block
+			n, err := d.WriteVec(data[5:5+i], data[5+i:5+j], nil, data[5+j:])
+			if n != len(data)-5 || err != nil {
+				t.Fatalf("got (%d, %v); want (%d, <nil>)", n, err, len(data)-5)
+			}
+			if got, want := d.Sum64(), Sum64(data); got != want {
+				t.Fatalf("split at %d, %d: got 0x%x; want 0x%x", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestSumLE(t *testing.T) {
+	d := New()
+	d.WriteString("hello")
+	be := d.Sum(nil)
+	le := d.SumLE()
+	for i := range le {
+		if le[i] != be[7-i] {
+			t.Fatalf("SumLE = %x; want reverse of Sum %x", le, be)
+		}
+	}
+	if got := d.AppendSumLE([]byte("x")); string(got) != "x"+string(le[:]) {
+		t.Fatalf("AppendSumLE = %x; want %x", got, append([]byte("x"), le[:]...))
+	}
+}
+
+func TestDigestString(t *testing.T) {
+	d := New()
+	d.WriteString("hello")
+	want := fmt.Sprintf("%016x", Sum64String("hello"))
+	if got := d.String(); got != want {
+		t.Fatalf("String: got %q; want %q", got, want)
+	}
+	if got := fmt.Sprint(d); got != want {
+		t.Fatalf("fmt.Sprint: got %q; want %q", got, want)
+	}
+	text, err := d.MarshalText()
+	if err != nil || string(text) != want {
+		t.Fatalf("MarshalText: got (%q, %v); want %q", text, err, want)
+	}
+	// Neither changes the state.
+	d.WriteString(", world")
+	if got, want := d.Sum64(), Sum64String("hello, world"); got != want {
+		t.Fatalf("after String: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestLen(t *testing.T) {
+	d := New()
+	var want uint64
+	for _, n := range []int{0, 1, 31, 32, 100} {
+		d.Write(make([]byte, n))
+		want += uint64(n)
+		if got := d.Len(); got != want {
+			t.Fatalf("got %d; want %d", got, want)
+		}
+	}
+	d.Reset()
+	if got := d.Len(); got != 0 {
+		t.Fatalf("after Reset: got %d; want 0", got)
+	}
+}
+
+func TestSum64AndReset(t *testing.T) {
+	d := New()
+	for _, rec := range []string{"first", "second record", strings.Repeat("x", 100)} {
+		d.WriteString(rec)
+		if got, want := d.Sum64AndReset(), Sum64String(rec); got != want {
+			t.Fatalf("%q: got 0x%x; want 0x%x", rec, got, want)
+		}
+	}
+	if got, want := d.Sum64(), Sum64(nil); got != want {
+		t.Fatalf("after Sum64AndReset: got 0x%x; want 0x%x", got, want)
+	}
+}
+
+func TestClone(t *testing.T) {
+	prefix := strings.Repeat("shared prefix ", 5)
+	d := New()
+	d.WriteString(prefix)
+	c := d.Clone()
+	d.WriteString("one")
+	c.WriteString("two")
+	if got, want := d.Sum64(), Sum64String(prefix+"one"); got != want {
+		t.Errorf("original: got 0x%x; want 0x%x", got, want)
+	}
+	if got, want := c.Sum64(), Sum64String(prefix+"two"); got != want {
+		t.Errorf("clone: got 0x%x; want 0x%x", got, want)
+	}
+}
+
 func TestBinaryMarshaling(t *testing.T) {
 	d := New()
 	d.WriteString("abc")
@@ -129,6 +248,66 @@ func TestBinaryMarshaling(t *testing.T) {
 	}
 }
 
+func TestAppendBinary(t *testing.T) {
+	d := New()
+	d.WriteString("abc")
+	want, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := []byte("prefix")
+	b, err := d.AppendBinary(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b[:len(prefix)], prefix) || !bytes.Equal(b[len(prefix):], want) {
+		t.Fatalf("AppendBinary: got %x; want %x followed by %x", b, prefix, want)
+	}
+
+	// Stale bytes beyond len(b) must not leak into the encoding.
+	dirty := bytes.Repeat([]byte{0xff}, 2*len(want))
+	b, _ = d.AppendBinary(dirty[:0])
+	if !bytes.Equal(b, want) {
+		t.Fatalf("AppendBinary into a dirty buffer: got %x; want %x", b, want)
+	}
+
+	buf := make([]byte, 0, len(want))
+	testAllocs(t, func() {
+		buf, _ = d.AppendBinary(buf[:0])
+	})
+}
+
+// TestBinaryMarshalingFormat pins the serialized form of a Digest so that
+// states written by earlier versions of this package keep working.
+func TestBinaryMarshalingFormat(t *testing.T) {
+	const input = "Call me Ishmael. Some years ago--never mind"
+	const want = "787868060022aa335c6b012a28c03ba003d935bf52d881e6" +
+		"29cf6d593620c050f37f739d2b000000000000002d6e65766572206d696e64" +
+		"000000000000000000000000000000000000000000"
+	d := NewWithSeed(1)
+	d.WriteString(input)
+	b, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(b); got != want {
+		t.Fatalf("MarshalBinary: got\n%s\nwant\n%s", got, want)
+	}
+	golden, _ := hex.DecodeString(want)
+	var d1 Digest
+	if err := d1.UnmarshalBinary(golden); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d1.Sum64(), Sum64WithSeed([]byte(input), 1); got != want {
+		t.Fatalf("after UnmarshalBinary: got 0x%x; want 0x%x", got, want)
+	}
+
+	golden[3] = 7 // unknown version
+	if err := d1.UnmarshalBinary(golden); err == nil {
+		t.Fatal("UnmarshalBinary accepted an unknown format version")
+	}
+}
+
 var sink uint64
 
 func TestAllocs(t *testing.T) {
@@ -152,6 +331,114 @@ func TestAllocs(t *testing.T) {
 			sink = d.Sum64()
 		})
 	})
+	t.Run("ZeroDigest", func(t *testing.T) {
+		b := make([]byte, 100)
+		testAllocs(t, func() {
+			var d Digest
+			d.Write(b)
+			sink = d.Sum64()
+		})
+	})
+	t.Run("WriteVec", func(t *testing.T) {
+		header, key, value := []byte("hdr"), []byte("key"), make([]byte, 100)
+		testAllocs(t, func() {
+			d := New()
+			d.WriteVec(header, key, value)
+			sink = d.Sum64()
+		})
+	})
+}
+
+func TestZeroDigest(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for _, n := range []int{0, 1, 31, 32, 33, 64, 100} {
+		var d Digest
+		d.Write(data[:n])
+		if got, want := d.Sum64(), Sum64(data[:n]); got != want {
+			t.Fatalf("n=%d: got 0x%x; want 0x%x", n, got, want)
+		}
+	}
+
+	// A zero Digest embedded by value, written in small pieces that start
+	// with the typed fast paths.
+	var s struct {
+		name string
+		d    Digest
+	}
+	s.d.WriteByte(data[0])
+	s.d.WriteUint32(u32(data[1:]))
+	s.d.WriteUint64(u64(data[5:]))
+	s.d.Write(data[13:])
+	if got, want := s.d.Sum64(), Sum64(data); got != want {
+		t.Fatalf("embedded: got 0x%x; want 0x%x", got, want)
+	}
+
+	var z Digest
+	b, _ := z.MarshalBinary()
+	want, _ := New().MarshalBinary()
+	if !bytes.Equal(b, want) {
+		t.Fatalf("MarshalBinary of zero Digest: got %x; want %x", b, want)
+	}
+	if got, want := z.ExportXXH64State(nil), New().ExportXXH64State(nil); !bytes.Equal(got, want) {
+		t.Fatalf("ExportXXH64State of zero Digest: got %x; want %x", got, want)
+	}
+}
+
+func TestSum64WithSeed(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		seed  uint64
+		want  uint64
+	}{
+		{"", 0x0, 0xef46db3751d8e999},
+		{"", 0x1, 0xd5afba1336a3be4b},
+		{"", 0x9e3779b97f4a7c15, 0xc4349fc93c010000},
+		{"", 0xffffffffffffffff, 0x298f4c84b24f5380},
+		{"a", 0x0, 0xd24ec4f1a98c6e5b},
+		{"a", 0x1, 0xdec2bc81c3cd46c6},
+		{"a", 0x9e3779b97f4a7c15, 0x9a7c6d2ea45568c9},
+		{"a", 0xffffffffffffffff, 0x60c43759873ece62},
+		{"asdf", 0x0, 0x415872f599cea71e},
+		{"asdf", 0x1, 0xd672719c1ce262ac},
+		{"asdf", 0x9e3779b97f4a7c15, 0x9b2d9e9aec9cbdce},
+		{"asdf", 0xffffffffffffffff, 0x9a2fd8473be539b6},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", 0x0, 0x02a2e85470d6fd96},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", 0x1, 0x67ca9f6ecb8a4659},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", 0x9e3779b97f4a7c15, 0x6bca380245838ac3},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", 0xffffffffffffffff, 0xe90de6936119b613},
+		{"Call me Ishmael. Some years ago--never mind how long precisely, having little or no money in my purse", 0x0, 0x2530e4b8f0df7cf4},
+		{"Call me Ishmael. Some years ago--never mind how long precisely, having little or no money in my purse", 0x1, 0x797f1776c991969d},
+		{"Call me Ishmael. Some years ago--never mind how long precisely, having little or no money in my purse", 0x9e3779b97f4a7c15, 0x58839b8a69410bfd},
+		{"Call me Ishmael. Some years ago--never mind how long precisely, having little or no money in my purse", 0xffffffffffffffff, 0x6580e972b0d43609},
+	} {
+		if got := Sum64WithSeed([]byte(tt.input), tt.seed); got != tt.want {
+			t.Errorf("Sum64WithSeed(%q, 0x%x): got 0x%016x; want 0x%016x", tt.input, tt.seed, got, tt.want)
+		}
+		if got := Sum64StringWithSeed(tt.input, tt.seed); got != tt.want {
+			t.Errorf("Sum64StringWithSeed(%q, 0x%x): got 0x%016x; want 0x%016x", tt.input, tt.seed, got, tt.want)
+		}
+		for chunkSize := 1; chunkSize <= len(tt.input); chunkSize++ {
+			d := NewWithSeed(tt.seed)
+			for i := 0; i < len(tt.input); i += chunkSize {
+				chunk := tt.input[i:]
+				if len(chunk) > chunkSize {
+					chunk = chunk[:chunkSize]
+				}
+				d.WriteString(chunk)
+			}
+			if got := d.Sum64(); got != tt.want {
+				t.Fatalf("NewWithSeed(0x%x), chunkSize=%d: got 0x%016x; want 0x%016x", tt.seed, chunkSize, got, tt.want)
+			}
+			d.ResetWithSeed(tt.seed)
+			d.WriteString(tt.input)
+			if got := d.Sum64(); got != tt.want {
+				t.Fatalf("ResetWithSeed(0x%x): got 0x%016x; want 0x%016x", tt.seed, got, tt.want)
+			}
+		}
+	}
 }
 
 func testAllocs(t *testing.T, fn func()) {
@@ -160,3 +447,58 @@ func testAllocs(t *testing.T, fn func()) {
 		t.Fatalf("got %d allocation(s) (want zero)", allocs)
 	}
 }
+
+// repeatReader yields n bytes of a repeating pattern.
+type repeatReader struct {
+	pattern []byte
+	off     int
+	n       int64
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+	var nr int
+	for nr < len(p) {
+		c := copy(p[nr:], r.pattern[r.off:])
+		nr += c
+		r.off = (r.off + c) % len(r.pattern)
+	}
+	r.n -= int64(nr)
+	return nr, nil
+}
+
+func TestLargeInput(t *testing.T) {
+	// The total length is mixed into each hash, so check that it doesn't
+	// wrap on 32-bit platforms for inputs of more than 4GiB.
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	const size = 4<<30 + 5
+	pattern := make([]byte, 997)
+	for i := range pattern {
+		pattern[i] = byte(i * 13)
+	}
+	for _, tt := range []struct {
+		name string
+		h    hash.Hash
+		want string
+	}{
+		{"XXH64", New(), "623987084a94e140"},
+		{"XXH32", New32(), "49166da1"},
+		{"XXH3", NewXXH3(), "6a57c1375480639f"},
+	} {
+		buf := make([]byte, 1<<20)
+		n, err := io.CopyBuffer(tt.h, &repeatReader{pattern: pattern, n: size}, buf)
+		if err != nil || n != size {
+			t.Fatalf("%s: copied %d bytes (err=%v); want %d", tt.name, n, err, int64(size))
+		}
+		if got := hex.EncodeToString(tt.h.Sum(nil)); got != tt.want {
+			t.Errorf("%s: got %s; want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-// +build !appengine
+// +build !appengine,!tinygo,!nounsafe
 
 // This file encapsulates usage of unsafe.
 // xxhash_safe.go contains the safe implementations.
@@ -39,6 +39,14 @@ func Sum64String(s string) uint64 {
 	return Sum64(b)
 }
 
+// Sum64StringWithSeed computes the 64-bit xxHash digest of s using the given
+// seed. It may be faster than Sum64WithSeed([]byte(s), seed) by avoiding a
+// copy.
+func Sum64StringWithSeed(s string, seed uint64) uint64 {
+	b := *(*[]byte)(unsafe.Pointer(&sliceHeader{s, len(s)}))
+	return Sum64WithSeed(b, seed)
+}
+
 // WriteString adds more data to d. It always returns len(s), nil.
 // It may be faster than Write([]byte(s)) by avoiding a copy.
 func (d *Digest) WriteString(s string) (n int, err error) {
@@ -49,6 +57,41 @@ func (d *Digest) WriteString(s string) (n int, err error) {
 	return len(s), nil
 }
 
+// Sum32String computes the 32-bit xxHash digest of s.
+// It may be faster than Sum32([]byte(s)) by avoiding a copy.
+func Sum32String(s string) uint32 {
+	b := *(*[]byte)(unsafe.Pointer(&sliceHeader{s, len(s)}))
+	return Sum32(b)
+}
+
+// WriteString adds more data to d. It always returns len(s), nil.
+// It may be faster than Write([]byte(s)) by avoiding a copy.
+func (d *Digest32) WriteString(s string) (n int, err error) {
+	d.Write(*(*[]byte)(unsafe.Pointer(&sliceHeader{s, len(s)})))
+	return len(s), nil
+}
+
+// SumXXH3_64String computes the 64-bit XXH3 digest of s.
+// It may be faster than SumXXH3_64([]byte(s)) by avoiding a copy.
+func SumXXH3_64String(s string) uint64 {
+	b := *(*[]byte)(unsafe.Pointer(&sliceHeader{s, len(s)}))
+	return SumXXH3_64(b)
+}
+
+// SumXXH3_128String computes the 128-bit XXH3 digest of s.
+// It may be faster than SumXXH3_128([]byte(s)) by avoiding a copy.
+func SumXXH3_128String(s string) Uint128 {
+	b := *(*[]byte)(unsafe.Pointer(&sliceHeader{s, len(s)}))
+	return SumXXH3_128(b)
+}
+
+// WriteString adds more data to d. It always returns len(s), nil.
+// It may be faster than Write([]byte(s)) by avoiding a copy.
+func (d *DigestXXH3) WriteString(s string) (n int, err error) {
+	d.Write(*(*[]byte)(unsafe.Pointer(&sliceHeader{s, len(s)})))
+	return len(s), nil
+}
+
 // sliceHeader is similar to reflect.SliceHeader, but it assumes that the layout
 // of the first two words is the same as the layout of a string.
 type sliceHeader struct {
@@ -1,4 +1,4 @@
-// +build !appengine
+// +build !appengine,!tinygo,!nounsafe
 
 package xxhash
 
@@ -16,6 +16,11 @@ func TestStringAllocs(t *testing.T) {
 			sink = Sum64String(longStr)
 		})
 	})
+	t.Run("Sum64StringWithSeed", func(t *testing.T) {
+		testAllocs(t, func() {
+			sink = Sum64StringWithSeed(longStr, 1)
+		})
+	})
 	t.Run("Digest.WriteString", func(t *testing.T) {
 		testAllocs(t, func() {
 			d := New()
@@ -23,14 +28,67 @@ func TestStringAllocs(t *testing.T) {
 			sink = d.Sum64()
 		})
 	})
+	t.Run("Sum32String", func(t *testing.T) {
+		testAllocs(t, func() {
+			sink = uint64(Sum32String(longStr))
+		})
+	})
+	t.Run("Digest32.WriteString", func(t *testing.T) {
+		testAllocs(t, func() {
+			d := New32()
+			d.WriteString(longStr)
+			sink = uint64(d.Sum32())
+		})
+	})
+	t.Run("SumXXH3_64String", func(t *testing.T) {
+		testAllocs(t, func() {
+			sink = SumXXH3_64String(longStr)
+		})
+	})
+	t.Run("SumXXH3_128String", func(t *testing.T) {
+		testAllocs(t, func() {
+			sink = SumXXH3_128String(longStr).Lo
+		})
+	})
+	t.Run("DigestXXH3.WriteString", func(t *testing.T) {
+		testAllocs(t, func() {
+			d := NewXXH3()
+			d.WriteString(longStr)
+			sink = d.Sum64()
+		})
+	})
+}
+
+// TestSafeBuilds asserts that the build tags that promise to avoid unsafe
+// keep it out of the package's imports.
+func TestSafeBuilds(t *testing.T) {
+	for _, tag := range []string{"appengine", "tinygo", "nounsafe"} {
+		cmd := exec.Command("go", "list", "-tags", tag, "-f", "{{range .Imports}}{{.}}\n{{end}}", ".")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Log(string(out))
+			t.Fatal(err)
+		}
+		for _, imp := range strings.Split(string(out), "\n") {
+			if imp == "unsafe" {
+				t.Errorf("-tags %s: package imports unsafe", tag)
+			}
+		}
+	}
 }
 
 // This test is inspired by the Go runtime tests in https://golang.org/cl/57410.
 // It asserts that certain important functions may be inlined.
 func TestInlining(t *testing.T) {
 	funcs := map[string]struct{}{
-		"Sum64String":           {},
-		"(*Digest).WriteString": {},
+		"Sum64String":               {},
+		"Sum64StringWithSeed":       {},
+		"(*Digest).WriteString":     {},
+		"Sum32String":               {},
+		"(*Digest32).WriteString":   {},
+		"SumXXH3_64String":          {},
+		"SumXXH3_128String":         {},
+		"(*DigestXXH3).WriteString": {},
 	}
 
 	// TODO: it would be better to use the go binary that is running
@@ -0,0 +1,188 @@
+// Command xxhashgen precomputes the XXH64 digests of string constants.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate xxhashgen
+//
+// xxhashgen reads the non-test Go files of the package in the given directory
+// (by default the current one) and finds the string constants marked with an
+// //xxhash comment, either on the constant itself or on the const block that
+// contains it:
+//
+//	const (
+//		RouteUsers = "/users" //xxhash
+//		RouteItems = "/items" //xxhash
+//	)
+//
+// For each one it writes a constant of the same name with the suffix "Hash"
+// holding xxhash.Sum64String of the value, so that code can switch on
+// precomputed digests without hashing at startup:
+//
+//	const (
+//		RouteUsersHash uint64 = 0x... // xxhash.Sum64String(RouteUsers)
+//		RouteItemsHash uint64 = 0x... // xxhash.Sum64String(RouteItems)
+//	)
+//
+// The marked constants must be plain string literals.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+const usage = `Usage:
+  %s [-o file] [-suffix s] [dir]
+Write the XXH64 digests of the string constants marked //xxhash in the
+package in dir (default .) to a Go file in that package.
+
+  -o file    name of the output file, relative to dir (default xxhash_gen.go)
+  -suffix s  suffix of the generated constant names (default Hash)
+`
+
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("xxhashgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprintf(stderr, usage, "xxhashgen") }
+	output := fs.String("o", "xxhash_gen.go", "")
+	suffix := fs.String("suffix", "Hash", "")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return 1
+	}
+	path := filepath.Join(dir, *output)
+	src, err := generate(dir, filepath.Base(path), *suffix)
+	if err != nil {
+		fmt.Fprintln(stderr, "xxhashgen:", err)
+		return 1
+	}
+	if err := ioutil.WriteFile(path, src, 0644); err != nil {
+		fmt.Fprintln(stderr, "xxhashgen:", err)
+		return 1
+	}
+	return 0
+}
+
+// A constant is a marked string constant.
+type constant struct {
+	name  string
+	value string
+}
+
+// generate returns the source of the generated file for the package in dir,
+// ignoring the existing output file.
+func generate(dir, output, suffix string) ([]byte, error) {
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("found %d packages in %s; want 1", len(pkgs), dir)
+	}
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var consts []constant
+	for _, name := range names {
+		cs, err := markedConstants(fset, pkg.Files[name])
+		if err != nil {
+			return nil, err
+		}
+		consts = append(consts, cs...)
+	}
+	if len(consts) == 0 {
+		return nil, fmt.Errorf("no constants marked //xxhash in %s", dir)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by xxhashgen; DO NOT EDIT.\n\npackage %s\n\nconst (\n", pkg.Name)
+	for _, c := range consts {
+		fmt.Fprintf(&buf, "\t%s%s uint64 = 0x%016x // xxhash.Sum64String(%s)\n", c.name, suffix, xxhash.Sum64String(c.value), c.name)
+	}
+	buf.WriteString(")\n")
+	return format.Source(buf.Bytes())
+}
+
+// markedConstants returns the marked constants declared in f, in order.
+func markedConstants(fset *token.FileSet, f *ast.File) ([]constant, error) {
+	var consts []constant
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		all := hasMarker(gd.Doc)
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if !all && !hasMarker(vs.Doc) && !hasMarker(vs.Comment) {
+				continue
+			}
+			for i, name := range vs.Names {
+				if name.Name == "_" {
+					continue
+				}
+				var lit *ast.BasicLit
+				if i < len(vs.Values) {
+					lit, _ = vs.Values[i].(*ast.BasicLit)
+				}
+				if lit == nil || lit.Kind != token.STRING {
+					return nil, fmt.Errorf("%s: constant %s is not a string literal", fset.Position(name.Pos()), name.Name)
+				}
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", fset.Position(lit.Pos()), err)
+				}
+				consts = append(consts, constant{name.Name, value})
+			}
+		}
+	}
+	return consts, nil
+}
+
+func hasMarker(cg *ast.CommentGroup) bool {
+	if cg == nil {
+		return false
+	}
+	for _, c := range cg.List {
+		if strings.TrimSpace(c.Text) == "//xxhash" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func writePackage(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "xxhashgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	dir := writePackage(t, map[string]string{
+		"a.go": `package routes
+
+const (
+	Users = "/users" //xxhash
+	Items = "/items" // not marked
+	// Raw is marked in its doc comment.
+	//xxhash
+	Raw = ` + "`a\\b`" + `
+)
+
+//xxhash
+const (
+	X, Y = "x", "y"
+	_    = "skipped"
+)
+`,
+		"b.go":          "package routes\n\nconst Empty = \"\" //xxhash\n",
+		"b_test.go":     "package routes\n\nconst Test = \"t\" //xxhash\n",
+		"xxhash_gen.go": "package routes\n\nconst Stale = \"stale\" //xxhash\n",
+	})
+	defer os.RemoveAll(dir)
+
+	var stderr bytes.Buffer
+	if status := run([]string{dir}, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	got, err := ioutil.ReadFile(filepath.Join(dir, "xxhash_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	want.WriteString("// Code generated by xxhashgen; DO NOT EDIT.\n\npackage routes\n\nconst (\n")
+	for _, c := range []constant{{"Users", "/users"}, {"Raw", `a\b`}, {"X", "x"}, {"Y", "y"}, {"Empty", ""}} {
+		fmt.Fprintf(&want, "\t%-9s uint64 = 0x%016x // xxhash.Sum64String(%s)\n", c.name+"Hash", xxhash.Sum64String(c.value), c.name)
+	}
+	want.WriteString(")\n")
+	if string(got) != want.String() {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want.String())
+	}
+}
+
+func TestFlags(t *testing.T) {
+	dir := writePackage(t, map[string]string{"a.go": "package p\n\nconst A = \"a\" //xxhash\n"})
+	defer os.RemoveAll(dir)
+	var stderr bytes.Buffer
+	if status := run([]string{"-o", "ids.go", "-suffix", "ID", dir}, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	got, err := ioutil.ReadFile(filepath.Join(dir, "ids.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("AID uint64 = 0x%016x", xxhash.Sum64String("a")); !strings.Contains(string(got), want) {
+		t.Errorf("output does not contain %q:\n%s", want, got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"not a literal", "package p\n\nconst B = \"b\"\n\nconst A = B //xxhash\n", "a.go:5:7: constant A is not a string literal"},
+		{"not a string", "package p\n\nconst A = 1 //xxhash\n", "constant A is not a string literal"},
+		{"no value", "package p\n\n//xxhash\nconst (\n\tA = \"a\"\n\tB\n)\n", "constant B is not a string literal"},
+		{"nothing marked", "package p\n\nconst A = \"a\"\n", "no constants marked //xxhash"},
+		{"syntax error", "package p\n\nconst A = \n", "expected"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePackage(t, map[string]string{"a.go": tt.src})
+			defer os.RemoveAll(dir)
+			var stderr bytes.Buffer
+			if status := run([]string{dir}, &stderr); status != 1 || !strings.Contains(stderr.String(), tt.want) {
+				t.Errorf("got status %d, stderr %q; want 1 and %q", status, stderr.String(), tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "xxhash_gen.go")); !os.IsNotExist(err) {
+				t.Errorf("output file was written")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// benchTime is how long each benchmark runs.
+var benchTime = time.Second
+
+// A benchmark measures one hash function with one of the implementations
+// of its variant, numbered as in the -b output.
+type benchmark struct {
+	name    string
+	variant xxhash.Variant
+	impl    string
+	fn      func(b []byte) uint64
+}
+
+// benchmarks holds a benchmark for each hash and each implementation of it
+// available on this CPU, so that -b shows the throughput of every dispatch
+// path. Index 0 is unused, as ids start at 1.
+var benchmarks = listBenchmarks()
+
+func listBenchmarks() []benchmark {
+	hashes := []struct {
+		name    string
+		variant xxhash.Variant
+		fn      func(b []byte) uint64
+	}{
+		{"XXH32", xxhash.XXH32, func(b []byte) uint64 { return uint64(xxhash.Sum32(b)) }},
+		{"XXH64", xxhash.XXH64, xxhash.Sum64},
+		{"XXH3_64b", xxhash.XXH3_64, xxhash.SumXXH3_64},
+		{"XXH128", xxhash.XXH3_128, func(b []byte) uint64 { return xxhash.SumXXH3_128(b).Lo }},
+	}
+	list := make([]benchmark, 1)
+	for _, h := range hashes {
+		for _, impl := range xxhash.Implementations(h.variant) {
+			list = append(list, benchmark{h.name + " (" + impl + ")", h.variant, impl, h.fn})
+		}
+	}
+	return list
+}
+
+// benchFlag is the value of -b: either alone, to run every benchmark, or
+// with the id of one benchmark.
+type benchFlag struct {
+	set bool
+	id  int // 0 for all
+}
+
+func (f *benchFlag) IsBoolFlag() bool { return true }
+
+func (f *benchFlag) String() string { return "" }
+
+func (f *benchFlag) Set(s string) error {
+	f.set = true
+	if s == "true" {
+		f.id = 0
+		return nil
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 1 || id >= len(benchmarks) {
+		return fmt.Errorf("unknown benchmark %q", s)
+	}
+	f.id = id
+	return nil
+}
+
+// runBenchmarks runs benchmark id, or all of them if id is 0, on a sample of
+// size bytes and prints the throughput of each, like the reference xxhsum -b.
+// Each benchmark selects its implementation with xxhash.SetImplementation,
+// and the previous one is restored afterwards.
+func runBenchmarks(id, size int, stdout, stderr io.Writer) int {
+	if size < 1 {
+		fmt.Fprintln(stderr, "xxhsum: -B must be at least 1")
+		return 1
+	}
+	sample := make([]byte, size)
+	for i := range sample {
+		sample[i] = byte(i * 7)
+	}
+	if id == 0 {
+		fmt.Fprintf(stdout, "Sample of %d bytes...\n", size)
+	}
+	status := 0
+	for i := 1; i < len(benchmarks); i++ {
+		if id != 0 && i != id {
+			continue
+		}
+		bm := benchmarks[i]
+		old := xxhash.Implementation(bm.variant)
+		if err := xxhash.SetImplementation(bm.variant, bm.impl); err != nil {
+			fmt.Fprintf(stderr, "xxhsum: benchmark %d: %v\n", i, err)
+			status = 1
+			continue
+		}
+		n, elapsed := measure(bm.fn, sample)
+		xxhash.SetImplementation(bm.variant, old)
+		perSec := float64(n) / elapsed.Seconds()
+		fmt.Fprintf(stdout, "%2d#%-24s: %10d -> %10.0f it/s (%9.1f MB/s)\n",
+			i, bm.name, size, perSec, perSec*float64(size)/1e6)
+	}
+	return status
+}
+
+var benchSink uint64
+
+// measure calls fn on sample repeatedly for about benchTime and returns the
+// number of calls and the time they took.
+func measure(fn func([]byte) uint64, sample []byte) (n int, elapsed time.Duration) {
+	start := time.Now()
+	for batch := 1; ; batch *= 2 {
+		for i := 0; i < batch; i++ {
+			benchSink += fn(sample)
+		}
+		n += batch
+		if elapsed = time.Since(start); elapsed >= benchTime {
+			return n, elapsed
+		}
+	}
+}
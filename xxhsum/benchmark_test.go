@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func TestBenchmarks(t *testing.T) {
+	defer func(d time.Duration) { benchTime = d }(benchTime)
+	benchTime = time.Millisecond
+	impls := map[string]bool{}
+	for i := 1; i < len(benchmarks); i++ {
+		bm := benchmarks[i]
+		impls[bm.impl] = true
+		old := xxhash.Implementation(bm.variant)
+		id := "-b" + strconv.Itoa(i)
+		got, stderr, status := runXXHSum(t, "", id, "-B1000")
+		if status != 0 || stderr != "" {
+			t.Fatalf("%s: got status %d, stderr %q", id, status, stderr)
+		}
+		want := strconv.Itoa(i) + "#" + bm.name
+		if !strings.HasPrefix(strings.TrimSpace(got), want) || !strings.Contains(got, "1000 ->") {
+			t.Errorf("%s: got %q", id, got)
+		}
+		if got := xxhash.Implementation(bm.variant); got != old {
+			t.Errorf("%s: implementation of %s left at %q; want %q", id, bm.variant, got, old)
+		}
+	}
+	if got, _, status := runXXHSum(t, "", "-b=1", "-B1000"); status != 0 || !strings.HasPrefix(strings.TrimSpace(got), "1#") {
+		t.Errorf("-b=1: got status %d, output %q", status, got)
+	}
+	if !impls["go"] {
+		t.Error("no benchmark of the pure-Go implementations")
+	}
+	for _, args := range [][]string{{"-b" + strconv.Itoa(len(benchmarks))}, {"-b", "-B0"}} {
+		if _, _, status := runXXHSum(t, "", args...); status != 1 {
+			t.Errorf("%v: got status %d; want 1", args, status)
+		}
+	}
+}
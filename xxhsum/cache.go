@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// A sumCache remembers the digests of files so that unchanged files need
+// not be hashed again. A file counts as unchanged if its size and
+// modification time are the same as when it was hashed.
+type sumCache interface {
+	get(alg algorithm, path string, fi os.FileInfo) (sum string, ok bool)
+	put(alg algorithm, path string, fi os.FileInfo, sum string)
+}
+
+// cacheEntry formats the part of a cache record that must match fi for the
+// record to be used.
+func cacheEntry(alg algorithm, fi os.FileInfo) string {
+	return fmt.Sprintf("%s %d %d", alg.name, fi.Size(), fi.ModTime().UnixNano())
+}
+
+// parseCacheRecord splits a record written as cacheEntry followed by a
+// space and the digest.
+func parseCacheRecord(rec string) (entry, sum string, ok bool) {
+	i := strings.LastIndexByte(rec, ' ')
+	if i < 0 || !isHex(rec[i+1:]) {
+		return "", "", false
+	}
+	return rec[:i], rec[i+1:], true
+}
+
+// A fileCache is a sumCache kept in a single file, for filesystems that
+// don't support extended attributes. Its lines are of the form
+//
+//	<algorithm> <size> <mtime> <digest> <absolute path>
+//
+// It is loaded when created and written back by save.
+type fileCache struct {
+	path string
+
+	mu      sync.Mutex
+	records map[string]string // absolute path and algorithm -> record
+	dirty   bool
+}
+
+func openFileCache(path string) (*fileCache, error) {
+	c := &fileCache{path: path, records: make(map[string]string)}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		c.records[fields[4]+"\x00"+fields[0]] = strings.Join(fields[:4], " ")
+	}
+	return c, scanner.Err()
+}
+
+func (c *fileCache) key(alg algorithm, path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil || strings.ContainsAny(abs, "\n\r") {
+		return "", false
+	}
+	return abs + "\x00" + alg.name, true
+}
+
+func (c *fileCache) get(alg algorithm, path string, fi os.FileInfo) (string, bool) {
+	key, ok := c.key(alg, path)
+	if !ok {
+		return "", false
+	}
+	c.mu.Lock()
+	rec := c.records[key]
+	c.mu.Unlock()
+	entry, sum, ok := parseCacheRecord(rec)
+	if !ok || entry != cacheEntry(alg, fi) {
+		return "", false
+	}
+	return sum, true
+}
+
+func (c *fileCache) put(alg algorithm, path string, fi os.FileInfo, sum string) {
+	key, ok := c.key(alg, path)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	c.records[key] = cacheEntry(alg, fi) + " " + sum
+	c.dirty = true
+	c.mu.Unlock()
+}
+
+// save writes the cache back to its file if it has changed.
+func (c *fileCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), ".xxhsum-cache")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	for key, rec := range c.records {
+		path := key[:strings.IndexByte(key, 0)]
+		fmt.Fprintf(w, "%s %s\n", rec, path)
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// xattrName is the extended attribute that holds the cached digest for alg.
+func xattrName(alg algorithm) string {
+	return "user.xxhsum." + alg.name
+}
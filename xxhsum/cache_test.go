@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCache checks that the cache selected by flags is used for an
+// unchanged file, by replacing the cached digest with a fake one through
+// setFake, and is ignored once the file is modified.
+func testCache(t *testing.T, dir string, setFake func(path string), flags ...string) {
+	t.Helper()
+	path := filepath.Join(dir, "a")
+	if err := ioutil.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const real = "44bc2cf5ad770999  "
+	args := append(flags, path)
+	if got, stderr, _ := runXXHSum(t, "", args...); got != real+path+"\n" {
+		t.Fatalf("first run: got (%q, %q)", got, stderr)
+	}
+	setFake(path)
+	if got, _, _ := runXXHSum(t, "", args...); got != "0123456789abcdef  "+path+"\n" {
+		t.Fatalf("cached run: got %q; want the fake digest", got)
+	}
+	if got, _, _ := runXXHSum(t, "", path); got != real+path+"\n" {
+		t.Fatalf("run without the cache: got %q", got)
+	}
+	// Another algorithm has its own entry.
+	if got, _, _ := runXXHSum(t, "", append([]string{"-H0"}, args...)...); got != "32d153ff  "+path+"\n" {
+		t.Fatalf("-H0: got %q", got)
+	}
+
+	mtime := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := runXXHSum(t, "", args...); got != real+path+"\n" {
+		t.Fatalf("after modification: got %q", got)
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cache := filepath.Join(dir, "cache")
+	testCache(t, dir, func(string) {
+		b, err := ioutil.ReadFile(cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := strings.Replace(string(b), "44bc2cf5ad770999", "0123456789abcdef", 1)
+		if err := ioutil.WriteFile(cache, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}, "--cache-file", cache)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// An xattrCache is a sumCache that stores each file's digest in an extended
+// attribute of the file. The attribute holds the same record as a line of
+// a fileCache, without the path.
+type xattrCache struct {
+	stderr io.Writer
+	warn   sync.Once
+}
+
+func newXattrCache(stderr io.Writer) (sumCache, error) {
+	return &xattrCache{stderr: stderr}, nil
+}
+
+func (c *xattrCache) get(alg algorithm, path string, fi os.FileInfo) (string, bool) {
+	var buf [128]byte
+	n, err := syscall.Getxattr(path, xattrName(alg), buf[:])
+	if err != nil {
+		return "", false
+	}
+	entry, sum, ok := parseCacheRecord(string(buf[:n]))
+	if !ok || entry != cacheEntry(alg, fi) {
+		return "", false
+	}
+	return sum, true
+}
+
+func (c *xattrCache) put(alg algorithm, path string, fi os.FileInfo, sum string) {
+	rec := cacheEntry(alg, fi) + " " + sum
+	err := syscall.Setxattr(path, xattrName(alg), []byte(rec), 0)
+	if err == syscall.ENOTSUP {
+		c.warn.Do(func() {
+			fmt.Fprintf(c.stderr, "xxhsum: %s: extended attributes are not supported; use --cache-file to cache digests on this filesystem\n", path)
+		})
+	}
+}
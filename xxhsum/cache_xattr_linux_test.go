@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestXattrCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	probe := filepath.Join(dir, "probe")
+	if err := ioutil.WriteFile(probe, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Setxattr(probe, "user.xxhsum.test", nil, 0); err != nil {
+		t.Skipf("extended attributes are not available in %s: %v", dir, err)
+	}
+	testCache(t, dir, func(path string) {
+		var buf [128]byte
+		n, err := syscall.Getxattr(path, "user.xxhsum.XXH64", buf[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := strings.Replace(string(buf[:n]), "44bc2cf5ad770999", "0123456789abcdef", 1)
+		if err := syscall.Setxattr(path, "user.xxhsum.XXH64", []byte(rec), 0); err != nil {
+			t.Fatal(err)
+		}
+	}, "--cache")
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package main
+
+import (
+	"errors"
+	"io"
+)
+
+func newXattrCache(stderr io.Writer) (sumCache, error) {
+	return nil, errors.New("--cache needs extended attributes, which are only supported on Linux; use --cache-file")
+}
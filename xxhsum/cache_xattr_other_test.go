@@ -0,0 +1,11 @@
+// +build !linux
+
+package main
+
+import "testing"
+
+func TestXattrCacheUnsupported(t *testing.T) {
+	if _, _, status := runXXHSum(t, "", "--cache"); status != 1 {
+		t.Fatalf("--cache: got status %d; want 1", status)
+	}
+}
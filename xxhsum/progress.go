@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressInterval is how often --progress reports on a file.
+var progressInterval = 2 * time.Second
+
+// withProgress returns r, wrapped to report progress reading the file at
+// path if --progress is set. size is the file's size, or -1 if unknown.
+func (in *inputs) withProgress(r io.Reader, path string, size int64) io.Reader {
+	if in.progress == nil {
+		return r
+	}
+	now := time.Now()
+	return &progressReader{r: r, w: in.progress, path: path, size: size, start: now, last: now}
+}
+
+// A progressReader reports how much of a file has been read to w every
+// progressInterval. Each report is a separate line, so reports from
+// concurrent -j workers don't overwrite each other.
+type progressReader struct {
+	r     io.Reader
+	w     io.Writer
+	path  string
+	size  int64
+	n     int64
+	start time.Time
+	last  time.Time
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n += int64(n)
+	if now := time.Now(); now.Sub(p.last) >= progressInterval {
+		p.last = now
+		p.report(now)
+	}
+	return n, err
+}
+
+func (p *progressReader) report(now time.Time) {
+	rate := float64(p.n) / now.Sub(p.start).Seconds()
+	if p.size >= 0 {
+		pct := 100.0
+		if p.size > 0 {
+			pct = 100 * float64(p.n) / float64(p.size)
+		}
+		fmt.Fprintf(p.w, "xxhsum: %s: %s of %s (%.1f%%), %s/s\n",
+			p.path, formatBytes(float64(p.n)), formatBytes(float64(p.size)), pct, formatBytes(rate))
+	} else {
+		fmt.Fprintf(p.w, "xxhsum: %s: %s, %s/s\n", p.path, formatBytes(float64(p.n)), formatBytes(rate))
+	}
+}
+
+// formatBytes formats n bytes using decimal units.
+func formatBytes(n float64) string {
+	const units = "kMGTPE"
+	if n < 1000 {
+		return fmt.Sprintf("%.0f B", n)
+	}
+	i := -1
+	for n >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.1f %cB", n, units[i])
+}
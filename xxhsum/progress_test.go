@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	defer func(d time.Duration) { progressInterval = d }(progressInterval)
+	progressInterval = 0 // report after every read
+
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a")
+	if err := ioutil.WriteFile(path, make([]byte, 5000), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, status := runXXHSum(t, "", "--progress", path)
+	if status != 0 || !strings.HasSuffix(stdout, "  "+path+"\n") {
+		t.Fatalf("got (%q, %d)", stdout, status)
+	}
+	last := regexp.MustCompile(`xxhsum: .*/a: 5\.0 kB of 5\.0 kB \(100\.0%\), .*B/s\n$`)
+	if !last.MatchString(stderr) {
+		t.Errorf("file progress: got %q", stderr)
+	}
+
+	// Without a size, only the byte count is shown.
+	var out, errOut strings.Builder
+	status = run([]string{"--progress"}, iotest.HalfReader(strings.NewReader(strings.Repeat("x", 2500))), &out, &errOut)
+	if !regexp.MustCompile(`xxhsum: -: 2\.5 kB, .*B/s\n$`).MatchString(errOut.String()) || status != 0 {
+		t.Errorf("stdin progress: got (%q, %d)", errOut.String(), status)
+	}
+
+	if _, stderr, _ := runXXHSum(t, "abc", path); stderr != "" {
+		t.Errorf("without --progress: got stderr %q", stderr)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for _, tt := range []struct {
+		n    float64
+		want string
+	}{
+		{0, "0 B"},
+		{999, "999 B"},
+		{1000, "1.0 kB"},
+		{1500000, "1.5 MB"},
+		{2e12, "2.0 TB"},
+	} {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%v): got %q; want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,50 +1,482 @@
 package main
 
 import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/cespare/xxhash/v2"
+	"github.com/cespare/xxhash/v2/checkfile"
 )
 
 func main() {
-	if contains(os.Args[1:], "-h") {
-		fmt.Fprintf(os.Stderr, `Usage:
-  %s [filenames]
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// An algorithm is one of the hashes selected by -H, numbered as in the
+// reference xxhsum.
+type algorithm struct {
+	id     checkfile.Algorithm
+	name   string // as in BSD-style output
+	prefix string // printed before the hex digest in the default output
+	sum    func(r io.Reader) (string, int64, error)
+}
+
+var algorithms = []algorithm{
+	0: {checkfile.XXH32, "XXH32", "", func(r io.Reader) (string, int64, error) {
+		d := xxhash.New32()
+		n, err := io.Copy(d, r)
+		return fmt.Sprintf("%08x", d.Sum32()), n, err
+	}},
+	1: {checkfile.XXH64, "XXH64", "", func(r io.Reader) (string, int64, error) {
+		d := xxhash.New()
+		n, err := io.Copy(d, r)
+		return fmt.Sprintf("%016x", d.Sum64()), n, err
+	}},
+	2: {checkfile.XXH128, "XXH128", "", func(r io.Reader) (string, int64, error) {
+		d := xxhash.NewXXH3()
+		n, err := io.Copy(d, r)
+		return d.Sum128().Hex(), n, err
+	}},
+	3: {checkfile.XXH3, "XXH3", "XXH3_", func(r io.Reader) (string, int64, error) {
+		d := xxhash.NewXXH3()
+		n, err := io.Copy(d, r)
+		return fmt.Sprintf("%016x", d.Sum64()), n, err
+	}},
+}
+
+const usage = `Usage:
+  %s [-H0|-H1|-H2|-H3] [--tag] [-r] [-j N] [filenames]
+  %[1]s -c [filenames]
 If no filenames are provided or only - is given, input is read from stdin.
-`, os.Args[0])
-		os.Exit(1)
+
+  -H0          XXH32
+  -H1          XXH64 (default)
+  -H2          XXH128
+  -H3          XXH3 (64-bit)
+  --tag        print BSD-style "XXH64 (file) = hash" lines
+  -c, --check  read checksum lines from the files and verify them
+  -r           hash the regular files in directories, recursively
+  -j N         hash up to N files concurrently (output order is unchanged)
+  --json       print a JSON object for each file, one per line
+  -z           end each output line with NUL rather than newline
+  --files-from FILE
+               also hash the files named in FILE, one per line (- for stdin)
+  -0           names in the --files-from list are separated by NUL
+  -b           benchmark each hash, with each implementation available on
+               this CPU, instead of hashing files; -b<n> runs only
+               benchmark n
+  -B<n>        use n-byte samples for -b (default 100KB)
+  --progress   report the progress of each file on stderr
+  --cache      store each digest in an extended attribute of the file, with
+               its size and modification time, and reuse it while those
+               are unchanged
+  --cache-file FILE
+               like --cache, but keep the digests in FILE; for filesystems
+               without extended attributes
+`
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("xxhsum", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprintf(stderr, usage, "xxhsum") }
+	algo := fs.Int("H", 1, "")
+	tag := fs.Bool("tag", false, "")
+	recursive := fs.Bool("r", false, "")
+	jobs := fs.Int("j", 1, "")
+	jsonOut := fs.Bool("json", false, "")
+	zero := fs.Bool("z", false, "")
+	filesFrom := fs.String("files-from", "", "")
+	nulList := fs.Bool("0", false, "")
+	progress := fs.Bool("progress", false, "")
+	useCache := fs.Bool("cache", false, "")
+	cacheFile := fs.String("cache-file", "", "")
+	var bench benchFlag
+	fs.Var(&bench, "b", "")
+	benchSize := fs.Int("B", 100<<10, "")
+	var check bool
+	fs.BoolVar(&check, "c", false, "")
+	fs.BoolVar(&check, "check", false, "")
+	if err := fs.Parse(expandNumericFlags(args)); err != nil {
+		return 1
+	}
+	if *algo < 0 || *algo >= len(algorithms) {
+		fmt.Fprintf(stderr, "xxhsum: unknown algorithm -H%d\n", *algo)
+		return 1
+	}
+	if *jobs < 1 {
+		fmt.Fprintln(stderr, "xxhsum: -j must be at least 1")
+		return 1
+	}
+	alg := algorithms[*algo]
+
+	in := &inputs{stdin: stdin}
+	if *progress {
+		in.progress = stderr
 	}
-	if len(os.Args) < 2 || len(os.Args) == 2 && os.Args[1] == "-" {
-		printHash(os.Stdin, "-")
-		return
+	var fc *fileCache
+	switch {
+	case *cacheFile != "":
+		var err error
+		if fc, err = openFileCache(*cacheFile); err != nil {
+			fmt.Fprintln(stderr, "xxhsum:", err)
+			return 1
+		}
+		in.cache = fc
+	case *useCache:
+		var err error
+		if in.cache, err = newXattrCache(stderr); err != nil {
+			fmt.Fprintln(stderr, "xxhsum:", err)
+			return 1
+		}
+	}
+	if bench.set {
+		return runBenchmarks(bench.id, *benchSize, stdout, stderr)
+	}
+
+	paths := fs.Args()
+	if *filesFrom != "" {
+		listed, err := readFileList(*filesFrom, *nulList, stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, "xxhsum:", err)
+			return 1
+		}
+		paths = append(paths, listed...)
+	} else if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	var status int
+	if check {
+		status = checkFiles(paths, in, stdout, stderr)
+	} else {
+		status = hashFiles(alg, paths, *recursive, *jobs, in, outputFormat{*jsonOut, *tag, *zero}, stdout, stderr)
+	}
+	if fc != nil {
+		if err := fc.save(); err != nil {
+			fmt.Fprintln(stderr, "xxhsum:", err)
+			status = 1
+		}
 	}
-	for _, path := range os.Args[1:] {
+	return status
+}
+
+type outputFormat struct {
+	json bool
+	tag  bool
+	zero bool
+}
+
+// hashFiles prints the digests of the files at paths.
+func hashFiles(alg algorithm, paths []string, recursive bool, jobs int, in *inputs, format outputFormat, stdout, stderr io.Writer) int {
+	status := 0
+	if recursive {
+		var ok bool
+		if paths, ok = expandDirs(paths, stderr); !ok {
+			status = 1
+		}
+	}
+	enc := json.NewEncoder(stdout)
+	for r := range sumFiles(alg, paths, jobs, in) {
+		if r.err != nil {
+			status = 1
+		}
+		switch {
+		case format.json:
+			enc.Encode(newJSONResult(alg, r))
+		case r.err != nil:
+			fmt.Fprintln(stderr, "xxhsum:", r.err)
+		case format.zero:
+			// As with GNU coreutils, names are not escaped in NUL-terminated
+			// output.
+			if format.tag {
+				fmt.Fprintf(stdout, "%s (%s) = %s\x00", alg.name, r.path, r.sum)
+			} else {
+				fmt.Fprintf(stdout, "%s%s  %s\x00", alg.prefix, r.sum, r.path)
+			}
+		default:
+			sum, _ := hex.DecodeString(r.sum)
+			e := checkfile.Entry{Algorithm: alg.id, Sum: sum, Name: r.path}
+			style := checkfile.GNU
+			if format.tag {
+				style = checkfile.BSD
+			}
+			fmt.Fprintln(stdout, e.Format(style))
+		}
+	}
+	return status
+}
+
+// A jsonResult is the --json output for one file. Size and Hash are omitted
+// if the file could not be hashed, and Error is omitted if it could.
+type jsonResult struct {
+	Path      string `json:"path"`
+	Size      *int64 `json:"size,omitempty"`
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func newJSONResult(alg algorithm, r sumResult) jsonResult {
+	res := jsonResult{Path: r.path, Algorithm: alg.name}
+	if r.err != nil {
+		res.Error = r.err.Error()
+	} else {
+		res.Size = &r.size
+		res.Hash = r.sum
+	}
+	return res
+}
+
+// readFileList reads the names in the --files-from list at path, or stdin if
+// path is "-". Names are separated by newlines, or by NUL if nul is set;
+// empty names are skipped.
+func readFileList(path string, nul bool, stdin io.Reader) ([]string, error) {
+	r := stdin
+	if path != "-" {
 		f, err := os.Open(path)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	sep := byte('\n')
+	if nul {
+		sep = 0
+	}
+	var names []string
+	br := bufio.NewReader(r)
+	for {
+		name, err := br.ReadString(sep)
+		if name = strings.TrimSuffix(name, string(sep)); name != "" {
+			names = append(names, name)
+		}
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// expandDirs replaces each directory in paths with the regular files below
+// it, in lexical order. It reports errors walking the directories to stderr
+// and returns false if there were any.
+func expandDirs(paths []string, stderr io.Writer) ([]string, bool) {
+	var out []string
+	ok := true
+	for _, root := range paths {
+		if root == "-" {
+			out = append(out, root)
+			continue
+		}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				fmt.Fprintln(stderr, "xxhsum:", err)
+				ok = false
+				return nil
+			}
+			if info.Mode().IsRegular() || path == root && !info.IsDir() {
+				out = append(out, path)
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(stderr, "xxhsum:", err)
+			ok = false
+		}
+	}
+	return out, ok
+}
+
+type sumResult struct {
+	path string
+	size int64
+	sum  string
+	err  error
+}
+
+// sumFiles hashes paths using up to jobs goroutines and sends the results
+// on the returned channel in the order of paths.
+func sumFiles(alg algorithm, paths []string, jobs int, in *inputs) <-chan sumResult {
+	results := make([]sumResult, len(paths))
+	done := make([]chan struct{}, len(paths))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	next := make(chan int)
+	for j := 0; j < jobs && j < len(paths); j++ {
+		go func() {
+			for i := range next {
+				sum, size, err := in.sum(alg, paths[i])
+				results[i] = sumResult{paths[i], size, sum, err}
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		for i := range paths {
+			next <- i
+		}
+		close(next)
+	}()
+
+	out := make(chan sumResult)
+	go func() {
+		for i := range paths {
+			<-done[i]
+			out <- results[i]
+		}
+		close(out)
+	}()
+	return out
+}
+
+// expandNumericFlags rewrites the reference tool's -H<n>, -b<n>, and -B<n>
+// spellings as -H=<n> and so on for the flag package.
+func expandNumericFlags(args []string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			copy(out[i:], args[i:])
+			break
+		}
+		if len(arg) > 2 && (arg[:2] == "-H" || arg[:2] == "-b" || arg[:2] == "-B") {
+			if _, err := strconv.Atoi(arg[2:]); err == nil {
+				arg = arg[:2] + "=" + arg[2:]
+			}
+		}
+		out[i] = arg
+	}
+	return out
+}
+
+// inputs opens the files to be hashed.
+type inputs struct {
+	stdin    io.Reader
+	progress io.Writer // if non-nil, where to report progress
+	cache    sumCache  // may be nil
+}
+
+// sum hashes the file at path, or stdin if path is "-". It also returns the
+// number of bytes hashed.
+func (in *inputs) sum(alg algorithm, path string) (string, int64, error) {
+	if path == "-" {
+		return alg.sum(in.withProgress(in.stdin, path, -1))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() {
+		return alg.sum(in.withProgress(f, path, -1))
+	}
+	if in.cache != nil {
+		if sum, ok := in.cache.get(alg, path, fi); ok {
+			return sum, fi.Size(), nil
+		}
+	}
+	sum, n, err := alg.sum(in.withProgress(f, path, fi.Size()))
+	if err == nil && in.cache != nil {
+		// Don't cache the digest of a file that changed while it was read.
+		if fi1, err := f.Stat(); err == nil && fi1.Size() == n && fi1.ModTime().Equal(fi.ModTime()) {
+			in.cache.put(alg, path, fi1, sum)
+		}
+	}
+	return sum, n, err
+}
+
+// checkFiles verifies the checksum lines in each of the lists at paths,
+// printing OK or FAILED for each file listed, like the reference xxhsum -c.
+// It returns 1 if any checksum did not match, any listed file could not be
+// read, or a list had no valid lines.
+func checkFiles(lists []string, in *inputs, stdout, stderr io.Writer) int {
+	status := 0
+	for _, list := range lists {
+		if !checkList(list, in, stdout, stderr) {
+			status = 1
+		}
+	}
+	return status
+}
+
+func checkList(list string, in *inputs, stdout, stderr io.Writer) bool {
+	r := in.stdin
+	if list != "-" {
+		f, err := os.Open(list)
+		if err != nil {
+			fmt.Fprintln(stderr, "xxhsum:", err)
+			return false
+		}
+		defer f.Close()
+		r = f
+	}
+	var ok, mismatched, unreadable, malformed int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		e, _, err := checkfile.ParseLine(line)
+		if err != nil {
+			malformed++
 			continue
 		}
-		printHash(f, path)
-		f.Close()
+		alg, want, path := algorithms[e.Algorithm], hex.EncodeToString(e.Sum), e.Name
+		got, _, err := in.sum(alg, path)
+		switch {
+		case err != nil:
+			fmt.Fprintf(stdout, "%s: FAILED open or read\n", path)
+			unreadable++
+		case got != want:
+			fmt.Fprintf(stdout, "%s: FAILED\n", path)
+			mismatched++
+		default:
+			fmt.Fprintf(stdout, "%s: OK\n", path)
+			ok++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "xxhsum: %s: %v\n", list, err)
+		return false
+	}
+	if ok+mismatched+unreadable == 0 {
+		fmt.Fprintf(stderr, "xxhsum: %s: no properly formatted xxHash checksum lines found\n", list)
+		return false
+	}
+	if malformed > 0 {
+		fmt.Fprintf(stderr, "xxhsum: %s: %d %s improperly formatted\n", list, malformed, plural(malformed, "line is", "lines are"))
+	}
+	if unreadable > 0 {
+		fmt.Fprintf(stderr, "xxhsum: %s: WARNING: %d listed %s could not be read\n", list, unreadable, plural(unreadable, "file", "files"))
+	}
+	if mismatched > 0 {
+		fmt.Fprintf(stderr, "xxhsum: %s: WARNING: %d computed %s did NOT match\n", list, mismatched, plural(mismatched, "checksum", "checksums"))
 	}
+	return mismatched == 0 && unreadable == 0
 }
 
-func contains(ss []string, s string) bool {
-	for _, s1 := range ss {
-		if s1 == s {
-			return true
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
 		}
 	}
-	return false
+	return s != ""
 }
 
-func printHash(r io.Reader, name string) {
-	h := xxhash.New()
-	if _, err := io.Copy(h, r); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
 	}
-	fmt.Printf("%016x  %s\n", h.Sum64(), name)
+	return many
 }
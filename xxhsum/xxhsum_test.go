@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func runXXHSum(t *testing.T, stdin string, args ...string) (stdout, stderr string, status int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	status = run(args, strings.NewReader(stdin), &out, &errOut)
+	return out.String(), errOut.String(), status
+}
+
+func TestAlgorithms(t *testing.T) {
+	// Values from the reference xxhsum for the input "abc".
+	for _, tt := range []struct {
+		flag string
+		want string
+	}{
+		{"-H0", "32d153ff  -\n"},
+		{"-H1", "44bc2cf5ad770999  -\n"},
+		{"-H2", "06b05ab6733a618578af5f94892f3950  -\n"},
+		{"-H3", "XXH3_78af5f94892f3950  -\n"},
+	} {
+		got, stderr, status := runXXHSum(t, "abc", tt.flag)
+		if got != tt.want || status != 0 {
+			t.Errorf("%s: got (%q, %d, %q); want (%q, 0, \"\")", tt.flag, got, status, stderr, tt.want)
+		}
+	}
+	if got, _, _ := runXXHSum(t, "abc"); got != "44bc2cf5ad770999  -\n" {
+		t.Errorf("default algorithm: got %q", got)
+	}
+	if _, _, status := runXXHSum(t, "", "-H9"); status != 1 {
+		t.Errorf("-H9: got exit status %d; want 1", status)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "abc")
+	if err := ioutil.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+	stdout, stderr, status := runXXHSum(t, "", path, missing)
+	if want := "44bc2cf5ad770999  " + path + "\n"; stdout != want {
+		t.Errorf("got output %q; want %q", stdout, want)
+	}
+	if status != 1 || !strings.Contains(stderr, missing) {
+		t.Errorf("missing file: got status %d, stderr %q", status, stderr)
+	}
+}
+
+func TestTag(t *testing.T) {
+	for _, tt := range []struct {
+		flag string
+		want string
+	}{
+		{"-H0", "XXH32 (-) = 32d153ff\n"},
+		{"-H1", "XXH64 (-) = 44bc2cf5ad770999\n"},
+		{"-H2", "XXH128 (-) = 06b05ab6733a618578af5f94892f3950\n"},
+		{"-H3", "XXH3 (-) = 78af5f94892f3950\n"},
+	} {
+		if got, _, _ := runXXHSum(t, "abc", tt.flag, "--tag"); got != tt.want {
+			t.Errorf("%s --tag: got %q; want %q", tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	for path, data := range map[string]string{a: "abc", b: "def"} {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Lists produced by xxhsum itself verify cleanly.
+	for _, args := range [][]string{
+		{a, b},
+		{"-H0", a, b},
+		{"-H2", "--tag", a, b},
+		{"-H3", a, b},
+		{"-H3", "--tag", a, b},
+	} {
+		list, _, _ := runXXHSum(t, "", args...)
+		for _, flag := range []string{"-c", "--check"} {
+			got, stderr, status := runXXHSum(t, list, flag)
+			want := a + ": OK\n" + b + ": OK\n"
+			if got != want || stderr != "" || status != 0 {
+				t.Errorf("%v %s: got (%q, %q, %d); want (%q, \"\", 0)", args, flag, got, stderr, status, want)
+			}
+		}
+	}
+
+	list := "44BC2CF5AD770999  " + a + "\n" + // uppercase is accepted
+		"XXH64 (" + b + ") = 44bc2cf5ad770999\n" +
+		"44bc2cf5ad770999  " + filepath.Join(dir, "missing") + "\n" +
+		"not a checksum line\n"
+	got, stderr, status := runXXHSum(t, list, "-c")
+	want := a + ": OK\n" +
+		b + ": FAILED\n" +
+		filepath.Join(dir, "missing") + ": FAILED open or read\n"
+	if got != want || status != 1 {
+		t.Errorf("got (%q, %d); want (%q, 1)", got, status, want)
+	}
+	for _, msg := range []string{
+		"1 line is improperly formatted",
+		"1 listed file could not be read",
+		"1 computed checksum did NOT match",
+	} {
+		if !strings.Contains(stderr, msg) {
+			t.Errorf("stderr %q does not contain %q", stderr, msg)
+		}
+	}
+
+	if _, stderr, status := runXXHSum(t, "garbage\n", "-c"); status != 1 || !strings.Contains(stderr, "no properly formatted") {
+		t.Errorf("list without checksums: got status %d, stderr %q", status, stderr)
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var want []string
+	for i, name := range []string{"a", "b/c", "b/d/e", "f"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := strings.Repeat("x", i*1000)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		line, _, _ := runXXHSum(t, data)
+		want = append(want, strings.TrimSuffix(line, "-\n")+path+"\n")
+	}
+	for _, jobs := range []string{"1", "3", "16"} {
+		got, stderr, status := runXXHSum(t, "", "-r", "-j", jobs, dir)
+		if got != strings.Join(want, "") || status != 0 {
+			t.Errorf("-j %s: got (%q, %q, %d); want (%q, \"\", 0)", jobs, got, stderr, status, strings.Join(want, ""))
+		}
+	}
+
+	// Without -r, a directory is an error.
+	if _, _, status := runXXHSum(t, "", dir); status != 1 {
+		t.Errorf("directory without -r: got status %d; want 1", status)
+	}
+	if _, _, status := runXXHSum(t, "", "-j", "0", dir); status != 1 {
+		t.Errorf("-j 0: got status %d; want 1", status)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a \"file\"\nwith newline")
+	if err := ioutil.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+	got, _, status := runXXHSum(t, "", "--json", "-H3", path, missing)
+	if status != 1 {
+		t.Errorf("got status %d; want 1", status)
+	}
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines of output; want 2:\n%s", len(lines), got)
+	}
+	var ok, failed map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"path":      path,
+		"size":      3.0,
+		"algorithm": "XXH3",
+		"hash":      "78af5f94892f3950",
+	}
+	if !reflect.DeepEqual(ok, want) {
+		t.Errorf("got %v; want %v", ok, want)
+	}
+	if failed["path"] != missing || failed["error"] == nil || failed["hash"] != nil || failed["size"] != nil {
+		t.Errorf("missing file: got %v", failed)
+	}
+}
+
+func TestNULTerminated(t *testing.T) {
+	if got, _, _ := runXXHSum(t, "abc", "-z"); got != "44bc2cf5ad770999  -\x00" {
+		t.Errorf("-z: got %q", got)
+	}
+	if got, _, _ := runXXHSum(t, "abc", "-z", "--tag"); got != "XXH64 (-) = 44bc2cf5ad770999\x00" {
+		t.Errorf("-z --tag: got %q", got)
+	}
+}
+
+func TestFilesFrom(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b with\nnewline")
+	for _, path := range []string{a, b} {
+		if err := ioutil.WriteFile(path, []byte("abc"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const sum = "44bc2cf5ad770999  "
+
+	got, _, status := runXXHSum(t, a+"\n\n"+a+"\n", "--files-from", "-")
+	if want := sum + a + "\n" + sum + a + "\n"; got != want || status != 0 {
+		t.Errorf("newline list: got (%q, %d); want (%q, 0)", got, status, want)
+	}
+
+	list := filepath.Join(dir, "list")
+	if err := ioutil.WriteFile(list, []byte(a+"\x00"+b+"\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, _, status = runXXHSum(t, "", "-z", "-0", "--files-from", list, a)
+	if want := sum + a + "\x00" + sum + a + "\x00" + sum + b + "\x00"; got != want || status != 0 {
+		t.Errorf("NUL list: got (%q, %d); want (%q, 0)", got, status, want)
+	}
+
+	if _, _, status := runXXHSum(t, "", "--files-from", filepath.Join(dir, "missing")); status != 1 {
+		t.Errorf("missing list: got status %d; want 1", status)
+	}
+}
+
+func TestEscapedNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxhsum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a\nb\\c")
+	if err := ioutil.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	escaped := strings.Replace(strings.Replace(path, "\\", "\\\\", -1), "\n", "\\n", -1)
+	list, _, _ := runXXHSum(t, "", path)
+	if want := "\\44bc2cf5ad770999  " + escaped + "\n"; list != want {
+		t.Fatalf("got %q; want %q", list, want)
+	}
+	if got, _, status := runXXHSum(t, list, "-c"); got != path+": OK\n" || status != 0 {
+		t.Errorf("check: got (%q, %d)", got, status)
+	}
+	if got, _, _ := runXXHSum(t, "", "-z", path); got != "44bc2cf5ad770999  "+path+"\x00" {
+		t.Errorf("-z: got %q", got)
+	}
+}
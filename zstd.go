@@ -0,0 +1,34 @@
+package xxhash
+
+import "io"
+
+// ZstdContentChecksum returns the content checksum that a zstd frame stores
+// for content with XXH64 digest (seed 0) sum: the low 32 bits of sum, which
+// the frame stores in 4 little-endian bytes after the last block.
+func ZstdContentChecksum(sum uint64) uint32 {
+	return uint32(sum)
+}
+
+// NewZstdVerifyingReader returns a reader that reads decompressed content
+// from r and checks it against checksum, the content checksum of its zstd
+// frame as decoded from the 4 little-endian bytes. It behaves like
+// NewVerifyingReader: once r returns io.EOF, the returned reader returns
+// io.EOF if the checksum matches and ErrChecksumMismatch otherwise.
+func NewZstdVerifyingReader(r io.Reader, checksum uint32) io.Reader {
+	v := &zstdVerifyingReader{want: checksum}
+	v.Reset(r)
+	return v
+}
+
+type zstdVerifyingReader struct {
+	Reader
+	want uint32
+}
+
+func (r *zstdVerifyingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF && ZstdContentChecksum(r.Sum64()) != r.want {
+		err = ErrChecksumMismatch
+	}
+	return n, err
+}
@@ -0,0 +1,40 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// emptyZstdFrame is the output of the reference zstd tool for empty input: the
+// magic number, a frame header with the checksum flag, an empty last raw
+// block, and the content checksum.
+var emptyZstdFrame = []byte{
+	0x28, 0xb5, 0x2f, 0xfd,
+	0x24, 0x00,
+	0x01, 0x00, 0x00,
+	0x99, 0xe9, 0xd8, 0x51,
+}
+
+func TestZstdContentChecksum(t *testing.T) {
+	stored := binary.LittleEndian.Uint32(emptyZstdFrame[9:])
+	if got := ZstdContentChecksum(Sum64(nil)); got != stored {
+		t.Errorf("empty content: got 0x%08x; want 0x%08x", got, stored)
+	}
+	if got := ZstdContentChecksum(0x0123456789abcdef); got != 0x89abcdef {
+		t.Errorf("got 0x%08x; want 0x89abcdef", got)
+	}
+}
+
+func TestZstdVerifyingReader(t *testing.T) {
+	const s = "decompressed content"
+	want := ZstdContentChecksum(Sum64String(s))
+	got, err := ioutil.ReadAll(NewZstdVerifyingReader(strings.NewReader(s), want))
+	if err != nil || string(got) != s {
+		t.Fatalf("got (%q, %v); want (%q, nil)", got, err, s)
+	}
+	if _, err := ioutil.ReadAll(NewZstdVerifyingReader(strings.NewReader(s), want^1)); err != ErrChecksumMismatch {
+		t.Fatalf("wrong checksum: got %v; want ErrChecksumMismatch", err)
+	}
+}